@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/swaggo/swag"
 	"github.com/swaggo/swag/gen"
@@ -13,8 +14,11 @@ import (
 const (
 	searchDirFlag        = "dir"
 	excludeFlag          = "exclude"
+	tagsFlag             = "tags"
 	generalInfoFlag      = "generalInfo"
+	generalInfoDirFlag   = "generalInfoAcrossDir"
 	propertyStrategyFlag = "propertyStrategy"
+	definitionNamingFlag = "definitionNaming"
 	outputFlag           = "output"
 	parseVendorFlag      = "parseVendor"
 	parseDependencyFlag  = "parseDependency"
@@ -22,7 +26,40 @@ const (
 	codeExampleFilesFlag = "codeExampleFiles"
 	parseInternalFlag    = "parseInternal"
 	generatedTimeFlag    = "generatedTime"
+	templateFlag         = "template"
 	parseDepthFlag       = "parseDepth"
+	parseGoListFlag      = "parseGoList"
+	outputTypesFlag      = "outputTypes"
+	jsonIndentFlag       = "jsonIndent"
+	splitByTagFlag       = "splitByTag"
+	jsonSchemaFlag       = "emitJSONSchema"
+	defaultOpIDFlag      = "defaultOperationID"
+	validateSemverFlag   = "validateSemver"
+	validateRefsFlag     = "validateRefs"
+	versionPatternFlag   = "versionPattern"
+	emitMsEnumFlag       = "emitMsEnum"
+	nullableFlag         = "nullable"
+	pruneDefinitionsFlag = "pruneUnusedDefinitions"
+	dedupeInlineFlag     = "dedupeInlineSchemas"
+	inlineSingleUseFlag  = "inlineSingleUse"
+	resolveIfacesFlag    = "resolveInterfaces"
+	includeHiddenFlag    = "includeHidden"
+	routerPrefixFlag     = "routerPrefix"
+	continueOnErrorFlag  = "continueOnError"
+	inferResponsesFlag   = "inferResponses"
+	requiredDefaultFlag  = "requiredByDefault"
+	int64AsStringFlag    = "int64AsString"
+	swagDirectiveFlag    = "swagDirectiveStyle"
+	lockFileFlag         = "lockFile"
+	checkDriftFlag       = "checkDrift"
+	diffAgainstFlag      = "diffAgainst"
+	failOnBreakingFlag   = "failOnBreaking"
+	quietFlag            = "quiet"
+	collectionFormatFlag = "collectionFormat"
+	outputModeFlag       = "outputMode"
+	durationTypeFlag     = "durationType"
+	localeFlag           = "locale"
+	parallelismFlag      = "parallelism"
 )
 
 var initFlags = []cli.Flag{
@@ -32,15 +69,23 @@ var initFlags = []cli.Flag{
 		Value:   "main.go",
 		Usage:   "Go file path in which 'swagger general API Info' is written",
 	},
+	&cli.BoolFlag{
+		Name:  generalInfoDirFlag,
+		Usage: "Also look for 'swagger general API Info' in the other files of the search dirs, disabled by default",
+	},
 	&cli.StringFlag{
 		Name:    searchDirFlag,
 		Aliases: []string{"d"},
 		Value:   "./",
-		Usage:   "Directory you want to parse",
+		Usage:   "Directories you want to parse,comma separated and general-info file must be in the first one",
 	},
 	&cli.StringFlag{
 		Name:  excludeFlag,
-		Usage: "Exclude directories and files when searching, comma separated",
+		Usage: "Exclude directories and files when searching, comma separated paths or glob patterns relative to dir",
+	},
+	&cli.StringFlag{
+		Name:  tagsFlag,
+		Usage: "Only document the operations having one of these tags, comma separated, a tag starting with ! excludes the operations having it",
 	},
 	&cli.StringFlag{
 		Name:    propertyStrategyFlag,
@@ -48,6 +93,10 @@ var initFlags = []cli.Flag{
 		Value:   "camelcase",
 		Usage:   "Property Naming Strategy like snakecase,camelcase,pascalcase",
 	},
+	&cli.StringFlag{
+		Name:  definitionNamingFlag,
+		Usage: "Naming of the definitions, short like model.User or full like github.com_org_app_model.User, the definitions of the same name are renamed like full ones when empty",
+	},
 	&cli.StringFlag{
 		Name:    outputFlag,
 		Aliases: []string{"o"},
@@ -82,11 +131,152 @@ var initFlags = []cli.Flag{
 		Name:  generatedTimeFlag,
 		Usage: "Generate timestamp at the top of docs.go, disabled by default",
 	},
+	&cli.StringFlag{
+		Name:  templateFlag,
+		Usage: "File of the text/template generating docs.go instead of the default one",
+	},
 	&cli.IntFlag{
 		Name:  parseDepthFlag,
 		Value: 100,
 		Usage: "Dependency parse depth",
 	},
+	&cli.BoolFlag{
+		Name:  parseGoListFlag,
+		Usage: "Find the dependencies with 'go list', parsing only the imported packages, disabled by default",
+	},
+	&cli.StringFlag{
+		Name:    outputTypesFlag,
+		Aliases: []string{"ot"},
+		Value:   "go,json,yaml",
+		Usage:   "Output types of generated files (docs.go, swagger.json, swagger.yaml, insomnia.json) like go,json,yaml,insomnia",
+	},
+	&cli.StringFlag{
+		Name:  jsonIndentFlag,
+		Value: "    ",
+		Usage: "Indentation of the generated json, \\t standing for a tab, the json is minified when empty",
+	},
+	&cli.BoolFlag{
+		Name:  splitByTagFlag,
+		Usage: "Also write a swagger.<tag>.json file per tag holding only its operations, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  jsonSchemaFlag,
+		Usage: "Also write schemas.json holding the definitions as JSON Schema draft-07, disabled by default",
+	},
+	&cli.StringFlag{
+		Name:  outputModeFlag,
+		Value: gen.OutputModeFiles,
+		Usage: "Output mode, files writes a file per output type, single writes only docs.go embedding the json spec",
+	},
+	&cli.BoolFlag{
+		Name:  defaultOpIDFlag,
+		Value: true,
+		Usage: "Use the handler function name as operationId when @ID is absent",
+	},
+	&cli.BoolFlag{
+		Name:  validateSemverFlag,
+		Usage: "Check that @version is a valid semantic version, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  validateRefsFlag,
+		Value: true,
+		Usage: "Check that the definitions referenced by $ref exist",
+	},
+	&cli.StringFlag{
+		Name:  versionPatternFlag,
+		Usage: "Regular expression used instead of semantic versioning by --validateSemver",
+	},
+	&cli.BoolFlag{
+		Name:  emitMsEnumFlag,
+		Usage: "Add the x-ms-enum extension of AutoRest to enums of named types, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  nullableFlag,
+		Usage: "Add the x-nullable extension to pointer fields, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  pruneDefinitionsFlag,
+		Usage: "Remove the definitions not referenced by any operation, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  dedupeInlineFlag,
+		Usage: "Replace the identical inline objects found more than once by a shared InlineSchemaN definition, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  inlineSingleUseFlag,
+		Usage: "Replace the references of the definitions referenced once by their schema, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  resolveIfacesFlag,
+		Usage: "Document the interface types as one of their implementers in the scanned packages rather than as any value, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  includeHiddenFlag,
+		Usage: "Keep the operations annotated with @Hidden, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  routerPrefixFlag,
+		Usage: "Prepend the path of the file level @RouterPrefix annotation to the @Router paths of the file, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  continueOnErrorFlag,
+		Usage: "Skip the files failing to parse instead of failing, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  inferResponsesFlag,
+		Usage: "Infer the 2xx responses of the Gin handlers lacking them from their c.JSON calls, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  requiredDefaultFlag,
+		Usage: "Mark every field required unless it's tagged omitempty, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  int64AsStringFlag,
+		Usage: "Document int64 and uint64 as strings, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  swagDirectiveFlag,
+		Usage: "Also recognize annotations written as //swag:xxx directives, disabled by default",
+	},
+	&cli.BoolFlag{
+		Name:  lockFileFlag,
+		Usage: "Write swagger.lock holding a hash of the generated spec",
+	},
+	&cli.BoolFlag{
+		Name:  checkDriftFlag,
+		Usage: "Don't write anything, fail if the docs in the output directory are out of date",
+	},
+	&cli.StringFlag{
+		Name:  diffAgainstFlag,
+		Usage: "Former swagger.json to compare the generated spec with, the changes are listed in swagger.diff.txt",
+	},
+	&cli.BoolFlag{
+		Name:  failOnBreakingFlag,
+		Usage: "Fail if the spec has breaking changes since the one of --diffAgainst, disabled by default",
+	},
+	&cli.StringFlag{
+		Name:    collectionFormatFlag,
+		Aliases: []string{"cf"},
+		Usage:   "Default collectionFormat of array params in query like csv,ssv,tsv,pipes,multi",
+	},
+	&cli.StringFlag{
+		Name:  durationTypeFlag,
+		Value: swag.INTEGER,
+		Usage: "Type documenting time.Duration, integer holding nanoseconds or string",
+	},
+	&cli.StringFlag{
+		Name:  localeFlag,
+		Usage: "Locale of the @Summary[locale] and @Description[locale] to use as the summary and description of the operations",
+	},
+	&cli.IntFlag{
+		Name:  parallelismFlag,
+		Usage: "Number of the Go source files parsed at once, GOMAXPROCS when 0",
+	},
+	&cli.BoolFlag{
+		Name:    quietFlag,
+		Aliases: []string{"q"},
+		Usage:   "Make the logger quiet",
+	},
 }
 
 func initAction(c *cli.Context) error {
@@ -98,20 +288,70 @@ func initAction(c *cli.Context) error {
 		return fmt.Errorf("not supported %s propertyStrategy", strategy)
 	}
 
-	return gen.New().Build(&gen.Config{
-		SearchDir:           c.String(searchDirFlag),
-		Excludes:            c.String(excludeFlag),
-		MainAPIFile:         c.String(generalInfoFlag),
-		PropNamingStrategy:  strategy,
-		OutputDir:           c.String(outputFlag),
-		ParseVendor:         c.Bool(parseVendorFlag),
-		ParseDependency:     c.Bool(parseDependencyFlag),
-		MarkdownFilesDir:    c.String(markdownFilesFlag),
-		ParseInternal:       c.Bool(parseInternalFlag),
-		GeneratedTime:       c.Bool(generatedTimeFlag),
-		CodeExampleFilesDir: c.String(codeExampleFilesFlag),
-		ParseDepth:          c.Int(parseDepthFlag),
-	})
+	definitionNaming := c.String(definitionNamingFlag)
+	switch definitionNaming {
+	case "", swag.ShortDefinitionNaming, swag.FullDefinitionNaming:
+	default:
+		return fmt.Errorf("not supported %s definitionNaming", definitionNaming)
+	}
+
+	config := &gen.Config{
+		SearchDir:                 c.String(searchDirFlag),
+		Excludes:                  c.String(excludeFlag),
+		Tags:                      c.String(tagsFlag),
+		MainAPIFile:               c.String(generalInfoFlag),
+		ParseGeneralInfoAcrossDir: c.Bool(generalInfoDirFlag),
+		PropNamingStrategy:        strategy,
+		DefinitionNaming:          definitionNaming,
+		OutputDir:                 c.String(outputFlag),
+		ParseVendor:               c.Bool(parseVendorFlag),
+		ParseDependency:           c.Bool(parseDependencyFlag),
+		MarkdownFilesDir:          c.String(markdownFilesFlag),
+		ParseInternal:             c.Bool(parseInternalFlag),
+		GeneratedTime:             c.Bool(generatedTimeFlag),
+		Template:                  c.String(templateFlag),
+		CodeExampleFilesDir:       c.String(codeExampleFilesFlag),
+		ParseDepth:                c.Int(parseDepthFlag),
+		ParseGoList:               c.Bool(parseGoListFlag),
+		OutputTypes:               strings.Split(c.String(outputTypesFlag), ","),
+		SplitByTag:                c.Bool(splitByTagFlag),
+		EmitJSONSchema:            c.Bool(jsonSchemaFlag),
+		JSONIndent:                strings.ReplaceAll(c.String(jsonIndentFlag), `\t`, "\t"),
+		OutputMode:                c.String(outputModeFlag),
+		SkipDefaultOperationID:    !c.Bool(defaultOpIDFlag),
+		ValidateSemver:            c.Bool(validateSemverFlag),
+		SkipValidateRefs:          !c.Bool(validateRefsFlag),
+		VersionPattern:            c.String(versionPatternFlag),
+		EmitMsEnum:                c.Bool(emitMsEnumFlag),
+		Nullable:                  c.Bool(nullableFlag),
+		PruneUnusedDefinitions:    c.Bool(pruneDefinitionsFlag),
+		DedupeInlineSchemas:       c.Bool(dedupeInlineFlag),
+		InlineSingleUse:           c.Bool(inlineSingleUseFlag),
+		ResolveInterfaces:         c.Bool(resolveIfacesFlag),
+		IncludeHidden:             c.Bool(includeHiddenFlag),
+		PrefixAnnotation:          c.Bool(routerPrefixFlag),
+		ContinueOnError:           c.Bool(continueOnErrorFlag),
+		InferResponses:            c.Bool(inferResponsesFlag),
+		RequiredByDefault:         c.Bool(requiredDefaultFlag),
+		Int64AsString:             c.Bool(int64AsStringFlag),
+		SwagDirectiveStyle:        c.Bool(swagDirectiveFlag),
+		LockFile:                  c.Bool(lockFileFlag),
+		DiffAgainst:               c.String(diffAgainstFlag),
+		FailOnBreakingChange:      c.Bool(failOnBreakingFlag),
+		CollectionFormat:          c.String(collectionFormatFlag),
+		DurationType:              c.String(durationTypeFlag),
+		Locale:                    c.String(localeFlag),
+		Parallelism:               c.Int(parallelismFlag),
+		Debugger:                  swag.NewLogger(swag.LogLevelInfo),
+	}
+	if c.Bool(quietFlag) {
+		config.Debugger = swag.NewQuietLogger()
+	}
+
+	if c.Bool(checkDriftFlag) {
+		return gen.New().CheckDrift(config)
+	}
+	return gen.New().Build(config)
 }
 
 func main() {
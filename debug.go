@@ -28,3 +28,81 @@ func Printf(format string, v ...interface{}) {
 		log.Printf(format, v...)
 	}
 }
+
+// Debugger is the interface the parser and the generator log their progress through.
+type Debugger interface {
+	Errorf(format string, v ...interface{})
+	Warnf(format string, v ...interface{})
+	Infof(format string, v ...interface{})
+}
+
+// LogLevel is the most verbose level of messages written by a Logger.
+type LogLevel int
+
+const (
+	// LogLevelError writes errors only
+	LogLevelError LogLevel = iota
+	// LogLevelWarn writes errors and warnings
+	LogLevelWarn
+	// LogLevelInfo writes errors, warnings and progress messages
+	LogLevelInfo
+)
+
+// Logger is a Debugger writing the messages up to Level to the standard logger.
+type Logger struct {
+	Level LogLevel
+}
+
+// NewLogger creates a Logger writing the messages up to level.
+func NewLogger(level LogLevel) *Logger {
+	return &Logger{Level: level}
+}
+
+// Errorf writes an error message.
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.printf(LogLevelError, "error: "+format, v...)
+}
+
+// Warnf writes a warning message.
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	l.printf(LogLevelWarn, "warning: "+format, v...)
+}
+
+// Infof writes a progress message.
+func (l *Logger) Infof(format string, v ...interface{}) {
+	l.printf(LogLevelInfo, format, v...)
+}
+
+func (l *Logger) printf(level LogLevel, format string, v ...interface{}) {
+	if level <= l.Level {
+		Printf(format, v...)
+	}
+}
+
+// quietLogger is a Debugger writing nothing.
+type quietLogger struct{}
+
+// NewQuietLogger creates a Debugger writing nothing, silencing the parser and the generator.
+func NewQuietLogger() Debugger {
+	return quietLogger{}
+}
+
+func (quietLogger) Errorf(format string, v ...interface{}) {}
+
+func (quietLogger) Warnf(format string, v ...interface{}) {}
+
+func (quietLogger) Infof(format string, v ...interface{}) {}
+
+// debugf writes an info message to debugger, a nil debugger writes nothing.
+func debugf(debugger Debugger, format string, v ...interface{}) {
+	if debugger != nil {
+		debugger.Infof(format, v...)
+	}
+}
+
+// warnf writes a warning message to debugger, a nil debugger writes nothing.
+func warnf(debugger Debugger, format string, v ...interface{}) {
+	if debugger != nil {
+		debugger.Warnf(format, v...)
+	}
+}
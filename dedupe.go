@@ -0,0 +1,137 @@
+package swag
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// inlineSchemaPrefix starts the names of the definitions hoisted from identical inline objects
+const inlineSchemaPrefix = "InlineSchema"
+
+// dedupeInlineSchemas hoists the inline objects found more than once into shared definitions
+func (parser *Parser) dedupeInlineSchemas() {
+	for _, name := range DedupeInlineSchemas(parser.swagger) {
+		debugf(parser.debug, "Hoisting the identical inline objects into %s", name)
+	}
+}
+
+// DedupeInlineSchemas replaces the structurally identical inline objects of the operations and the definitions of
+// swagger, found more than once, by a $ref to a definition named InlineSchema1, InlineSchema2... and returns the
+// names of these definitions.
+func DedupeInlineSchemas(swagger *spec.Swagger) []string {
+	var hoisted []string
+	for {
+		counts := make(map[string]int)
+		rangeInlineObjects(swagger, func(schema *spec.Schema, key string) {
+			counts[key]++
+		})
+
+		// a single object is hoisted at a time, the largest one since the objects nested in it may be found only
+		// once after it's hoisted
+		var repeated string
+		for key, count := range counts {
+			if count > 1 && (len(key) > len(repeated) || len(key) == len(repeated) && key < repeated) {
+				repeated = key
+			}
+		}
+		if repeated == "" {
+			return hoisted
+		}
+
+		name := nextInlineSchemaName(swagger)
+		var definition spec.Schema
+		rangeInlineObjects(swagger, func(schema *spec.Schema, key string) {
+			if key == repeated {
+				definition = *schema
+				*schema = *RefSchema(name)
+			}
+		})
+		if swagger.Definitions == nil {
+			swagger.Definitions = make(spec.Definitions)
+		}
+		swagger.Definitions[name] = definition
+		hoisted = append(hoisted, name)
+	}
+}
+
+// nextInlineSchemaName returns the first name InlineSchemaN not given to a definition
+func nextInlineSchemaName(swagger *spec.Swagger) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s%d", inlineSchemaPrefix, i)
+		if _, ok := swagger.Definitions[name]; !ok {
+			return name
+		}
+	}
+}
+
+// rangeInlineObjects calls fn with the inline objects of the operations and the definitions of swagger, in a stable
+// order, along with their json as a key. The roots of the definitions are not inline objects
+func rangeInlineObjects(swagger *spec.Swagger, fn func(schema *spec.Schema, key string)) {
+	visit := func(root *spec.Schema, skipRoot bool) {
+		_ = walkSchema(root, func(schema *spec.Schema) error {
+			if skipRoot && schema == root || !isInlineObject(schema) {
+				return nil
+			}
+			if b, err := json.Marshal(schema); err == nil {
+				fn(schema, string(b))
+			}
+			return nil
+		})
+	}
+
+	names := make([]string, 0, len(swagger.Definitions))
+	for name := range swagger.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		definition := swagger.Definitions[name]
+		visit(&definition, true)
+		swagger.Definitions[name] = definition
+	}
+
+	if swagger.Paths == nil {
+		return
+	}
+	paths := make([]string, 0, len(swagger.Paths.Paths))
+	for path := range swagger.Paths.Paths {
+		if strings.HasPrefix(path, "/") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := swagger.Paths.Paths[path]
+		for _, operation := range []*spec.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch} {
+			if operation == nil {
+				continue
+			}
+			for _, param := range operation.Parameters {
+				visit(param.Schema, false)
+			}
+			if operation.Responses == nil {
+				continue
+			}
+			if operation.Responses.Default != nil {
+				visit(operation.Responses.Default.Schema, false)
+			}
+			codes := make([]int, 0, len(operation.Responses.StatusCodeResponses))
+			for code := range operation.Responses.StatusCodeResponses {
+				codes = append(codes, code)
+			}
+			sort.Ints(codes)
+			for _, code := range codes {
+				visit(operation.Responses.StatusCodeResponses[code].Schema, false)
+			}
+		}
+	}
+}
+
+// isInlineObject tells whether schema is an object described by its properties rather than a $ref
+func isInlineObject(schema *spec.Schema) bool {
+	return schema.Ref.String() == "" && len(schema.Type) == 1 && schema.Type[0] == OBJECT && len(schema.Properties) > 0
+}
@@ -0,0 +1,298 @@
+package swag
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// EnumValue a constant declared with a named type, which makes the type an enum
+type EnumValue struct {
+	//Key name of the constant
+	Key string
+
+	//Value evaluated value of the constant
+	Value interface{}
+
+	//Comment text of the comment next to the constant
+	Comment string
+}
+
+// parseEnums collects the constants of every named type declared in const blocks of parsed files
+func (pkgs *PackagesDefinitions) parseEnums() {
+	files := make([]*AstFileInfo, 0, len(pkgs.files))
+	for _, info := range pkgs.files {
+		files = append(files, info)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	for _, info := range files {
+		pd, ok := pkgs.packages[info.PackagePath]
+		if !ok {
+			continue
+		}
+		for _, astDeclaration := range info.File.Decls {
+			if generalDeclaration, ok := astDeclaration.(*ast.GenDecl); ok && generalDeclaration.Tok == token.CONST {
+				pd.parseConstDecl(generalDeclaration)
+			}
+		}
+	}
+}
+
+func (pd *PackageDefinitions) parseConstDecl(decl *ast.GenDecl) {
+	if pd.constValues == nil {
+		pd.constValues = make(map[string]interface{})
+	}
+
+	var lastType ast.Expr
+	var lastValues []ast.Expr
+	for iota, astSpec := range decl.Specs {
+		valueSpec, ok := astSpec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		// an empty spec repeats the type and values of the previous one
+		if valueSpec.Type != nil || len(valueSpec.Values) > 0 {
+			lastType = valueSpec.Type
+			lastValues = valueSpec.Values
+		}
+
+		comment := ""
+		if valueSpec.Comment != nil {
+			comment = strings.TrimSpace(valueSpec.Comment.Text())
+		} else if valueSpec.Doc != nil {
+			comment = strings.TrimSpace(valueSpec.Doc.Text())
+		}
+
+		for i, name := range valueSpec.Names {
+			if i >= len(lastValues) {
+				break
+			}
+			value, ok := pd.evalConstExpr(lastValues[i], iota)
+			if !ok {
+				continue
+			}
+			pd.constValues[name.Name] = value
+
+			typeExpr := lastType
+			if call, ok := lastValues[i].(*ast.CallExpr); ok && typeExpr == nil {
+				// const A = Status("a")
+				typeExpr = call.Fun
+			}
+			typeIdent, ok := typeExpr.(*ast.Ident)
+			if !ok || name.Name == "_" {
+				continue
+			}
+			if typeSpecDef, ok := pd.TypeDefinitions[typeIdent.Name]; ok {
+				typeSpecDef.Enums = append(typeSpecDef.Enums, EnumValue{
+					Key:     name.Name,
+					Value:   value,
+					Comment: comment,
+				})
+			}
+		}
+	}
+}
+
+// evalConstExpr evaluates integer, float and string constant expressions, iota included
+func (pd *PackageDefinitions) evalConstExpr(expr ast.Expr, iota int) (interface{}, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		switch expr.Kind {
+		case token.INT:
+			v, err := strconv.ParseInt(expr.Value, 0, 64)
+			return int(v), err == nil
+		case token.FLOAT:
+			v, err := strconv.ParseFloat(expr.Value, 64)
+			return v, err == nil
+		case token.STRING, token.CHAR:
+			v, err := strconv.Unquote(expr.Value)
+			if err != nil {
+				return nil, false
+			}
+			if expr.Kind == token.CHAR {
+				return int([]rune(v)[0]), true
+			}
+			return v, true
+		}
+	case *ast.Ident:
+		if expr.Name == "iota" {
+			return iota, true
+		}
+		value, ok := pd.constValues[expr.Name]
+		return value, ok
+	case *ast.ParenExpr:
+		return pd.evalConstExpr(expr.X, iota)
+	case *ast.CallExpr:
+		// type conversion, like Status(1)
+		if len(expr.Args) == 1 {
+			return pd.evalConstExpr(expr.Args[0], iota)
+		}
+	case *ast.UnaryExpr:
+		x, ok := pd.evalConstExpr(expr.X, iota)
+		if !ok {
+			return nil, false
+		}
+		switch x := x.(type) {
+		case int:
+			switch expr.Op {
+			case token.SUB:
+				return -x, true
+			case token.ADD:
+				return x, true
+			case token.XOR:
+				return ^x, true
+			}
+		case float64:
+			switch expr.Op {
+			case token.SUB:
+				return -x, true
+			case token.ADD:
+				return x, true
+			}
+		}
+	case *ast.BinaryExpr:
+		x, ok := pd.evalConstExpr(expr.X, iota)
+		if !ok {
+			return nil, false
+		}
+		y, ok := pd.evalConstExpr(expr.Y, iota)
+		if !ok {
+			return nil, false
+		}
+		return evalBinaryConstExpr(expr.Op, x, y)
+	}
+	return nil, false
+}
+
+func evalBinaryConstExpr(op token.Token, x, y interface{}) (interface{}, bool) {
+	switch x := x.(type) {
+	case string:
+		if y, ok := y.(string); ok && op == token.ADD {
+			return x + y, true
+		}
+	case int:
+		switch y := y.(type) {
+		case int:
+			switch op {
+			case token.ADD:
+				return x + y, true
+			case token.SUB:
+				return x - y, true
+			case token.MUL:
+				return x * y, true
+			case token.QUO:
+				if y != 0 {
+					return x / y, true
+				}
+			case token.REM:
+				if y != 0 {
+					return x % y, true
+				}
+			case token.SHL:
+				return x << uint(y), true
+			case token.SHR:
+				return x >> uint(y), true
+			case token.OR:
+				return x | y, true
+			case token.AND:
+				return x & y, true
+			case token.XOR:
+				return x ^ y, true
+			}
+		case float64:
+			return evalBinaryConstExpr(op, float64(x), y)
+		}
+	case float64:
+		var fy float64
+		switch y := y.(type) {
+		case int:
+			fy = float64(y)
+		case float64:
+			fy = y
+		default:
+			return nil, false
+		}
+		switch op {
+		case token.ADD:
+			return x + fy, true
+		case token.SUB:
+			return x - fy, true
+		case token.MUL:
+			return x * fy, true
+		case token.QUO:
+			if fy != 0 {
+				return x / fy, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// enumComments returns the comments of the constants by name and in the order of the enum values, nil if no
+// constant is commented
+func enumComments(enums []EnumValue) (map[string]string, []string) {
+	var comments map[string]string
+	descriptions := make([]string, 0, len(enums))
+	for _, enum := range enums {
+		if enum.Comment != "" {
+			if comments == nil {
+				comments = make(map[string]string)
+			}
+			comments[enum.Key] = enum.Comment
+		}
+		descriptions = append(descriptions, enum.Comment)
+	}
+	if comments == nil {
+		return nil, nil
+	}
+	return comments, descriptions
+}
+
+// setEnums sets the values of constants declared with a named primitive type as the enum of its schema
+func (parser *Parser) setEnums(typeSpecDef *TypeSpecDef, schema *spec.Schema) {
+	if len(typeSpecDef.Enums) == 0 || len(schema.Type) == 0 || !IsSimplePrimitiveType(schema.Type[0]) {
+		return
+	}
+
+	schema.Enum = nil
+	varNames := make([]string, 0, len(typeSpecDef.Enums))
+	msEnumValues := make([]map[string]interface{}, 0, len(typeSpecDef.Enums))
+	for _, enum := range typeSpecDef.Enums {
+		value := enum.Value
+		if f, ok := value.(int); ok && schema.Type[0] == NUMBER {
+			value = float64(f)
+		}
+		schema.Enum = append(schema.Enum, value)
+		varNames = append(varNames, enum.Key)
+
+		msEnumValue := map[string]interface{}{"value": value, "name": enum.Key}
+		if enum.Comment != "" {
+			msEnumValue["description"] = enum.Comment
+		}
+		msEnumValues = append(msEnumValues, msEnumValue)
+	}
+
+	if schema.Extensions == nil {
+		schema.Extensions = spec.Extensions{}
+	}
+	schema.Extensions["x-enum-varnames"] = varNames
+	if comments, descriptions := enumComments(typeSpecDef.Enums); comments != nil {
+		schema.Extensions["x-enum-comments"] = comments
+		schema.Extensions["x-enum-descriptions"] = descriptions
+	}
+
+	if parser.EmitMsEnum {
+		schema.Extensions["x-ms-enum"] = map[string]interface{}{
+			"name":          typeSpecDef.Name(),
+			"modelAsString": true,
+			"values":        msEnumValues,
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package swag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-openapi/spec"
+)
+
+// externalRefPrefix starts the data type of an annotation referencing a definition of an external spec,
+// like external://common.yaml#/definitions/Address
+const externalRefPrefix = "external://"
+
+// getExternalSchema copies the definition referenced by refType into the definitions, along with the definitions
+// it references, and returns a $ref to it
+func (parser *Parser) getExternalSchema(refType string) (*spec.Schema, error) {
+	ref := strings.TrimPrefix(refType, externalRefPrefix)
+	specName, pointer := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		specName, pointer = ref[:i], ref[i+1:]
+	}
+	name, err := parser.importExternalDefinition(specName, pointer)
+	if err != nil {
+		return nil, err
+	}
+	return RefSchema(name), nil
+}
+
+// importExternalDefinition copies the definition found at pointer in the external spec specName, the name of
+// the copy is prefixed by the name of the spec to avoid conflicts
+func (parser *Parser) importExternalDefinition(specName, pointer string) (string, error) {
+	swagger, err := parser.loadExternalSpec(specName)
+	if err != nil {
+		return "", err
+	}
+
+	definitionName := strings.TrimPrefix(pointer, "/definitions/")
+	schema, ok := swagger.Definitions[definitionName]
+	if definitionName == pointer || !ok {
+		return "", fmt.Errorf("can't resolve %s in external spec %s (%s)", pointer, specName, parser.ExternalSpecs[specName])
+	}
+
+	name := fullTypeName(strings.TrimSuffix(filepath.Base(specName), filepath.Ext(specName)), definitionName)
+	if _, ok := parser.importedSchemas[name]; ok {
+		return name, nil
+	}
+	parser.importedSchemas[name] = true
+
+	// the definition is copied since its references are rewritten
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	var definition spec.Schema
+	if err = json.Unmarshal(b, &definition); err != nil {
+		return "", err
+	}
+	err = walkSchema(&definition, func(schema *spec.Schema) error {
+		url := schema.Ref.GetURL()
+		if url == nil {
+			return nil
+		}
+		if url.Path != "" || url.Host != "" {
+			return fmt.Errorf("can't resolve %s referenced in external spec %s, only local references are supported",
+				url.String(), specName)
+		}
+		nestedName, err := parser.importExternalDefinition(specName, url.Fragment)
+		if err != nil {
+			return err
+		}
+		schema.Ref = spec.MustCreateRef("#/definitions/" + nestedName)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	parser.swagger.Definitions[name] = definition
+	return name, nil
+}
+
+// loadExternalSpec reads the external spec specName declared in ExternalSpecs, either json or yaml
+func (parser *Parser) loadExternalSpec(specName string) (*spec.Swagger, error) {
+	if swagger, ok := parser.externalSpecs[specName]; ok {
+		return swagger, nil
+	}
+
+	fileName, ok := parser.ExternalSpecs[specName]
+	if !ok {
+		return nil, fmt.Errorf("external spec %s is not declared in ExternalSpecs", specName)
+	}
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(parser.searchDir, fileName)
+	}
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read external spec %s: %s", specName, err)
+	}
+	if b, err = yaml.YAMLToJSON(b); err != nil {
+		return nil, fmt.Errorf("failed to parse external spec %s: %s", specName, err)
+	}
+	swagger := &spec.Swagger{}
+	if err = json.Unmarshal(b, swagger); err != nil {
+		return nil, fmt.Errorf("failed to parse external spec %s: %s", specName, err)
+	}
+	parser.externalSpecs[specName] = swagger
+	return swagger, nil
+}
+
+// walkSchema calls fn on schema and the schemas nested in it, the changes made by fn are kept
+func walkSchema(schema *spec.Schema, fn func(*spec.Schema) error) error {
+	if schema == nil {
+		return nil
+	}
+	if err := fn(schema); err != nil {
+		return err
+	}
+	if schema.Items != nil {
+		if err := walkSchema(schema.Items.Schema, fn); err != nil {
+			return err
+		}
+		for i := range schema.Items.Schemas {
+			if err := walkSchema(&schema.Items.Schemas[i], fn); err != nil {
+				return err
+			}
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		if err := walkSchema(schema.AdditionalProperties.Schema, fn); err != nil {
+			return err
+		}
+	}
+	for _, schemas := range [][]spec.Schema{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for i := range schemas {
+			if err := walkSchema(&schemas[i], fn); err != nil {
+				return err
+			}
+		}
+	}
+	if err := walkSchema(schema.Not, fn); err != nil {
+		return err
+	}
+	for _, extension := range alternativesExtensions {
+		schemas, _ := schema.Extensions[extension].([]spec.Schema)
+		for i := range schemas {
+			if err := walkSchema(&schemas[i], fn); err != nil {
+				return err
+			}
+		}
+	}
+	for name, property := range schema.Properties {
+		if err := walkSchema(&property, fn); err != nil {
+			return err
+		}
+		schema.Properties[name] = property
+	}
+	return nil
+}
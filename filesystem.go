@@ -0,0 +1,109 @@
+package swag
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fsPath returns name as a path of an fs.FS, slash separated and without ./ or trailing slash
+func fsPath(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
+
+// readFile reads the file name from FileSystem when it's set, from the disk otherwise
+func (parser *Parser) readFile(name string) ([]byte, error) {
+	if parser.FileSystem != nil {
+		return fs.ReadFile(parser.FileSystem, fsPath(name))
+	}
+	return ioutil.ReadFile(name)
+}
+
+// getAllGoFileInfoFromFS parses the Go source files of searchDir in FileSystem, the package path of their
+// directory being found through the nearest go.mod of FileSystem
+func (parser *Parser) getAllGoFileInfoFromFS(searchDir string) error {
+	packageDir, err := fsPackagePath(parser.FileSystem, searchDir)
+	if err != nil {
+		warnf(parser.debug, "failed to read the go.mod of %s: %s", searchDir, err)
+	}
+
+	var files []goFile
+	err = fs.WalkDir(parser.FileSystem, searchDir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		f, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := parser.Skip(name, f); err != nil {
+			return err
+		}
+
+		relPath := strings.TrimPrefix(strings.TrimPrefix(name, searchDir), "/")
+		if searchDir == "." {
+			relPath = name
+		}
+		if relPath != "" && parser.isExcluded(relPath) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.HasSuffix(name, ".go") {
+			return nil
+		}
+
+		src, err := fs.ReadFile(parser.FileSystem, name)
+		if err != nil {
+			return err
+		}
+		pkgPath := path.Dir(path.Join(packageDir, relPath))
+		files = append(files, goFile{packageDir: vendoredPackagePath(pkgPath), path: name, src: src})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return parser.parseFiles(files)
+}
+
+// fsPackagePath returns the package path of dir in fsys, given by the module path of the nearest go.mod, an empty
+// string if there's none
+func fsPackagePath(fsys fs.FS, dir string) (string, error) {
+	for modDir := dir; ; modDir = path.Dir(modDir) {
+		f, err := fsys.Open(path.Join(modDir, "go.mod"))
+		if err == nil {
+			mod, err := readGoMod(f, modDir)
+			_ = f.Close()
+			if err != nil || mod.path == "" {
+				return "", err
+			}
+			if modDir == "." {
+				return path.Join(mod.path, dir), nil
+			}
+			return path.Join(mod.path, strings.TrimPrefix(dir, modDir)), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		if modDir == "." || modDir == "/" {
+			return "", nil
+		}
+	}
+}
+
+// resolveMainAPIFileFS returns the path of mainAPIFile in fsys, relative to searchDir unless it's found as is
+func resolveMainAPIFileFS(fsys fs.FS, searchDir, mainAPIFile string) (string, error) {
+	attempts := []string{path.Join(searchDir, fsPath(mainAPIFile)), fsPath(mainAPIFile)}
+	for _, attempt := range attempts {
+		if _, err := fs.Stat(fsys, attempt); err == nil {
+			return attempt, nil
+		}
+	}
+	return attempts[0], fmt.Errorf("cannot find the general API info file %s, tried %s", mainAPIFile, strings.Join(attempts, " and "))
+}
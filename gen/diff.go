@@ -0,0 +1,292 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// diffFileName is the name of the file summarizing the changes of the spec since the one of DiffAgainst
+const diffFileName = "swagger.diff.txt"
+
+// SpecChange presents a change of an operation or a definition from a spec to another.
+type SpecChange struct {
+	// Kind is added, removed or changed
+	Kind string
+
+	// Location is the operation, like operation GET /users, or the definition, like definition model.User
+	Location string
+
+	// Detail describes what changed in the operation or the definition
+	Detail string
+
+	// Breaking whether the clients written for the former spec may fail with the new one
+	Breaking bool
+}
+
+// String describes the change on a line, like "removed operation GET /users (breaking)".
+func (c SpecChange) String() string {
+	s := c.Kind + " " + c.Location
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	if c.Breaking {
+		s += " (breaking)"
+	}
+	return s
+}
+
+// DiffSpecs returns the operations and the definitions added, removed or changed from the spec old to the spec new,
+// sorted by location. Removing an operation, a definition, a response or a property, requiring a parameter or a
+// property, and changing the type of any of them are breaking changes.
+func DiffSpecs(old, new *spec.Swagger) []SpecChange {
+	var changes []SpecChange
+
+	oldOperations, newOperations := specOperations(old), specOperations(new)
+	for _, location := range unionKeys(oldOperations, newOperations) {
+		oldOperation, newOperation := oldOperations[location], newOperations[location]
+		switch {
+		case newOperation == nil:
+			changes = append(changes, SpecChange{Kind: "removed", Location: location, Breaking: true})
+		case oldOperation == nil:
+			changes = append(changes, SpecChange{Kind: "added", Location: location})
+		default:
+			changes = append(changes, diffOperation(location, oldOperation, newOperation)...)
+		}
+	}
+
+	for _, name := range unionKeys(old.Definitions, new.Definitions) {
+		location := "definition " + name
+		oldSchema, inOld := old.Definitions[name]
+		newSchema, inNew := new.Definitions[name]
+		switch {
+		case !inNew:
+			changes = append(changes, SpecChange{Kind: "removed", Location: location, Breaking: true})
+		case !inOld:
+			changes = append(changes, SpecChange{Kind: "added", Location: location})
+		default:
+			changes = append(changes, diffSchema(location, &oldSchema, &newSchema)...)
+		}
+	}
+	return changes
+}
+
+// diffOperation returns the changes of the parameters and the responses of an operation
+func diffOperation(location string, old, new *spec.Operation) []SpecChange {
+	var changes []SpecChange
+
+	oldParams, newParams := paramsOf(old), paramsOf(new)
+	for _, key := range unionKeys(oldParams, newParams) {
+		oldParam, newParam := oldParams[key], newParams[key]
+		switch {
+		case newParam == nil:
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "removed parameter " + key})
+		case oldParam == nil:
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "added parameter " + key, Breaking: newParam.Required})
+		case !sameParamType(oldParam, newParam):
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "changed the type of parameter " + key, Breaking: true})
+		case newParam.Required && !oldParam.Required:
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "required parameter " + key, Breaking: true})
+		}
+	}
+
+	oldResponses, newResponses := responsesOf(old), responsesOf(new)
+	for _, code := range unionKeys(oldResponses, newResponses) {
+		oldResponse, newResponse := oldResponses[code], newResponses[code]
+		switch {
+		case newResponse == nil:
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "removed response " + code, Breaking: true})
+		case oldResponse == nil:
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "added response " + code})
+		case !sameSchemaType(oldResponse.Schema, newResponse.Schema):
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "changed the schema of response " + code, Breaking: true})
+		}
+	}
+	return changes
+}
+
+// diffSchema returns the changes of the type and the properties of a definition
+func diffSchema(location string, old, new *spec.Schema) []SpecChange {
+	if !sameType(old, new) {
+		return []SpecChange{{Kind: "changed", Location: location, Detail: "changed the type", Breaking: true}}
+	}
+
+	var changes []SpecChange
+	oldRequired, newRequired := stringSet(old.Required), stringSet(new.Required)
+	for _, name := range unionKeys(old.Properties, new.Properties) {
+		oldProperty, inOld := old.Properties[name]
+		newProperty, inNew := new.Properties[name]
+		switch {
+		case !inNew:
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "removed property " + name, Breaking: true})
+		case !inOld:
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "added property " + name, Breaking: newRequired[name]})
+		case !sameSchemaType(&oldProperty, &newProperty):
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "changed the type of property " + name, Breaking: true})
+		case newRequired[name] && !oldRequired[name]:
+			changes = append(changes, SpecChange{Kind: "changed", Location: location, Detail: "required property " + name, Breaking: true})
+		}
+	}
+	return changes
+}
+
+// sameSchemaType tells whether two schemas, either of them possibly nil, have the same type, format and $ref, down
+// to their items and additional properties
+func sameSchemaType(a, b *spec.Schema) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if !sameType(a, b) {
+		return false
+	}
+
+	var aItems, bItems *spec.Schema
+	if a.Items != nil {
+		aItems = a.Items.Schema
+	}
+	if b.Items != nil {
+		bItems = b.Items.Schema
+	}
+	var aValues, bValues *spec.Schema
+	if a.AdditionalProperties != nil {
+		aValues = a.AdditionalProperties.Schema
+	}
+	if b.AdditionalProperties != nil {
+		bValues = b.AdditionalProperties.Schema
+	}
+	return sameSchemaType(aItems, bItems) && sameSchemaType(aValues, bValues)
+}
+
+// sameType tells whether two schemas have the same type, format and $ref
+func sameType(a, b *spec.Schema) bool {
+	return a.Ref.String() == b.Ref.String() && strings.Join(a.Type, ",") == strings.Join(b.Type, ",") &&
+		a.Format == b.Format
+}
+
+// sameParamType tells whether two parameters have the same type, format and schema
+func sameParamType(a, b *spec.Parameter) bool {
+	return a.Type == b.Type && a.Format == b.Format && sameSchemaType(a.Schema, b.Schema) &&
+		(a.Items == nil) == (b.Items == nil) && (a.Items == nil || a.Items.Type == b.Items.Type)
+}
+
+// specOperations returns the operations of swagger by their location, like operation GET /users
+func specOperations(swagger *spec.Swagger) map[string]*spec.Operation {
+	operations := make(map[string]*spec.Operation)
+	if swagger.Paths == nil {
+		return operations
+	}
+	for path, item := range swagger.Paths.Paths {
+		if !strings.HasPrefix(path, "/") {
+			continue
+		}
+		for method, operation := range map[string]*spec.Operation{
+			"GET": item.Get, "PUT": item.Put, "POST": item.Post, "DELETE": item.Delete,
+			"OPTIONS": item.Options, "HEAD": item.Head, "PATCH": item.Patch,
+		} {
+			if operation != nil {
+				operations[fmt.Sprintf("operation %s %s", method, path)] = operation
+			}
+		}
+	}
+	return operations
+}
+
+// paramsOf returns the parameters of an operation by their location and name, like query limit
+func paramsOf(operation *spec.Operation) map[string]*spec.Parameter {
+	params := make(map[string]*spec.Parameter, len(operation.Parameters))
+	for i := range operation.Parameters {
+		params[operation.Parameters[i].In+" "+operation.Parameters[i].Name] = &operation.Parameters[i]
+	}
+	return params
+}
+
+// responsesOf returns the responses of an operation by their status code or default
+func responsesOf(operation *spec.Operation) map[string]*spec.Response {
+	responses := make(map[string]*spec.Response)
+	if operation.Responses == nil {
+		return responses
+	}
+	if operation.Responses.Default != nil {
+		responses["default"] = operation.Responses.Default
+	}
+	for code, response := range operation.Responses.StatusCodeResponses {
+		response := response
+		responses[fmt.Sprint(code)] = &response
+	}
+	return responses
+}
+
+// stringSet returns the set of values
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// unionKeys returns the keys of maps keyed by strings, sorted and without duplicates
+func unionKeys(maps ...interface{}) []string {
+	set := make(map[string]bool)
+	for _, m := range maps {
+		for _, key := range reflect.ValueOf(m).MapKeys() {
+			set[key.String()] = true
+		}
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffAgainst compares swagger with the spec of the file DiffAgainst, ok is false when the file doesn't exist yet
+func diffAgainst(config *Config, swagger *spec.Swagger) (changes []SpecChange, ok bool, err error) {
+	b, err := ioutil.ReadFile(config.DiffAgainst)
+	if os.IsNotExist(err) {
+		config.debugger().Infof("%s not found, there's no former spec to diff against", config.DiffAgainst)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var old spec.Swagger
+	if err = json.Unmarshal(b, &old); err != nil {
+		return nil, false, fmt.Errorf("cannot read the spec to diff against %s: %v", config.DiffAgainst, err)
+	}
+	return DiffSpecs(&old, swagger), true, nil
+}
+
+// diffContent lists the changes one per line
+func diffContent(changes []SpecChange) []byte {
+	if len(changes) == 0 {
+		return []byte("no changes\n")
+	}
+	var buf strings.Builder
+	for _, change := range changes {
+		buf.WriteString(change.String())
+		buf.WriteByte('\n')
+	}
+	return []byte(buf.String())
+}
+
+// breakingChangesError returns an error listing the breaking changes, nil if there's none
+func breakingChangesError(config *Config, changes []SpecChange) error {
+	var breaking []string
+	for _, change := range changes {
+		if change.Breaking {
+			breaking = append(breaking, change.String())
+		}
+	}
+	if len(breaking) == 0 {
+		return nil
+	}
+	return fmt.Errorf("breaking changes since %s: %s", config.DiffAgainst, strings.Join(breaking, ", "))
+}
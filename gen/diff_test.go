@@ -0,0 +1,66 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSpecs(t *testing.T) {
+	user := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       []string{"object"},
+		Required:   []string{"id"},
+		Properties: spec.SchemaProperties{"id": *spec.Int64Property(), "name": *spec.StringProperty()},
+	}}
+	getUser := spec.NewOperation("").
+		AddParam(spec.PathParam("id").Typed("integer", "")).
+		RespondsWith(200, spec.NewResponse().WithSchema(spec.RefSchema("#/definitions/model.User")))
+	old := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/users/{id}": {PathItemProps: spec.PathItemProps{Get: getUser, Delete: spec.NewOperation("")}},
+		}},
+		Definitions: spec.Definitions{"model.User": user, "model.Error": *spec.StringProperty()},
+	}}
+
+	newUser := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:     []string{"object"},
+		Required: []string{"id", "name"},
+		Properties: spec.SchemaProperties{
+			"id":    *spec.StringProperty(),
+			"name":  *spec.StringProperty(),
+			"email": *spec.StringProperty(),
+		},
+	}}
+	newGetUser := spec.NewOperation("").
+		AddParam(spec.PathParam("id").Typed("integer", "")).
+		AddParam(spec.QueryParam("fields").Typed("string", "")).
+		AddParam(spec.HeaderParam("X-Tenant").Typed("string", "").AsRequired()).
+		RespondsWith(200, spec.NewResponse().WithSchema(spec.RefSchema("#/definitions/model.User"))).
+		RespondsWith(404, spec.NewResponse())
+	new := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/users/{id}": {PathItemProps: spec.PathItemProps{Get: newGetUser}},
+			"/users":      {PathItemProps: spec.PathItemProps{Post: spec.NewOperation("")}},
+		}},
+		Definitions: spec.Definitions{"model.User": newUser},
+	}}
+
+	var lines []string
+	for _, change := range DiffSpecs(old, new) {
+		lines = append(lines, change.String())
+	}
+	assert.Equal(t, []string{
+		"removed operation DELETE /users/{id} (breaking)",
+		"changed operation GET /users/{id}: added parameter header X-Tenant (breaking)",
+		"changed operation GET /users/{id}: added parameter query fields",
+		"changed operation GET /users/{id}: added response 404",
+		"added operation POST /users",
+		"removed definition model.Error (breaking)",
+		"changed definition model.User: added property email",
+		"changed definition model.User: changed the type of property id (breaking)",
+		"changed definition model.User: required property name (breaking)",
+	}, lines)
+
+	assert.Empty(t, DiffSpecs(old, old))
+}
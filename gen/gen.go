@@ -2,13 +2,20 @@ package gen
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/format"
 	"io"
-	"log"
+	"io/fs"
+	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -20,15 +27,15 @@ import (
 
 // Gen presents a generate tool for swag.
 type Gen struct {
-	jsonIndent func(data interface{}) ([]byte, error)
+	jsonIndent func(data interface{}, indent string) ([]byte, error)
 	jsonToYAML func(data []byte) ([]byte, error)
 }
 
 // New creates a new Gen.
 func New() *Gen {
 	return &Gen{
-		jsonIndent: func(data interface{}) ([]byte, error) {
-			return json.MarshalIndent(data, "", "    ")
+		jsonIndent: func(data interface{}, indent string) ([]byte, error) {
+			return marshalIndent(data, "", indent)
 		},
 		jsonToYAML: yaml.JSONToYAML,
 	}
@@ -36,22 +43,44 @@ func New() *Gen {
 
 // Config presents Gen configurations.
 type Config struct {
-	// SearchDir the swag would be parse
+	// SearchDir the swag would be parse, comma separated if multiple. MainAPIFile is relative to the first one
 	SearchDir string
 
-	// excludes dirs and files in SearchDir,comma separated
+	// Excludes dirs and files to skip, comma separated paths or glob patterns relative to SearchDir
 	Excludes string
 
+	// Tags filters the operations by their tags, comma separated. Only the operations having one of the tags are kept,
+	// a tag starting with ! removes the operations having it instead. The definitions no longer used are removed too
+	Tags string
+
 	// OutputDir represents the output directory for all the generated files
 	OutputDir string
 
-	// MainAPIFile the Go file path in which 'swagger general API Info' is written
+	// MainAPIFile the Go file path in which 'swagger general API Info' is written. A relative path is looked for in
+	// the first SearchDir, then in the working directory
 	MainAPIFile string
 
 	// PropNamingStrategy represents property naming strategy like snakecase,camelcase,pascalcase
 	PropNamingStrategy string
 
-	// ParseVendor whether swag should be parse vendor folder
+	// NameResolver returns the property name of a struct field given its name and its tag, overriding the json tag
+	// and PropNamingStrategy when set. An empty name skips the field
+	NameResolver func(fieldName string, tag reflect.StructTag) string
+
+	// DefinitionNaming names the definitions like short, model.User, or full, github.com_org_app_model.User.
+	// When it's empty, the definitions of the same name are renamed like full ones
+	DefinitionNaming string
+
+	// ModelNamer returns the definition name of a type given the import path of its package, overriding
+	// DefinitionNaming
+	ModelNamer func(pkgPath, typeName string) string
+
+	// FileSystem the sources are read from instead of the disk when it's set, the search dirs and the main api file
+	// being paths within it, like the @description.file and the code sample files. The package paths come from its
+	// go.mod files, ParseDependency isn't supported
+	FileSystem fs.FS
+
+	// ParseVendor whether swag should parse the vendor folder, resolving the types of the vendored packages
 	ParseVendor bool
 
 	// ParseDependencies whether swag should be parse outside dependency folder
@@ -60,98 +89,612 @@ type Config struct {
 	// ParseInternal whether swag should parse internal packages
 	ParseInternal bool
 
+	// ParseGoList whether swag should find the dependencies with go list, parsing only the packages imported
+	// by the main package, instead of resolving the import tree up to ParseDepth
+	ParseGoList bool
+
+	// ParseGeneralInfoAcrossDir whether swag should look for the general api info in every file of the search dirs
+	// instead of MainAPIFile only
+	ParseGeneralInfoAcrossDir bool
+
+	// SwagDirectiveStyle whether swag should also recognize annotations written as //swag:xxx directives
+	SwagDirectiveStyle bool
+
+	// EmitMsEnum whether swag should add the x-ms-enum extension of AutoRest to enums of named types
+	EmitMsEnum bool
+
+	// Int64AsString whether swag should document int64 and uint64 as strings, since large json numbers lose
+	// precision in some clients
+	Int64AsString bool
+
+	// RequiredByDefault whether swag should mark every field required unless it's tagged optional, like with
+	// json:",omitempty", binding:"-" or validate:"omitempty"
+	RequiredByDefault bool
+
+	// Nullable whether swag should add the x-nullable extension to pointer fields
+	Nullable bool
+
+	// PruneUnusedDefinitions whether swag should remove the definitions not referenced by any operation, directly or
+	// through other definitions
+	PruneUnusedDefinitions bool
+
+	// DedupeInlineSchemas whether swag should replace the identical inline objects found more than once by a $ref
+	// to a shared definition named InlineSchema1, InlineSchema2...
+	DedupeInlineSchemas bool
+
+	// InlineSingleUse whether swag should replace the $ref of the definitions referenced exactly once by
+	// their schema and remove them, except the recursive ones
+	InlineSingleUse bool
+
+	// ResolveInterfaces whether swag should document the interface types as one of the types implementing them in
+	// the scanned packages, listed by the x-oneOf extension, rather than as any value
+	ResolveInterfaces bool
+
+	// IncludeHidden whether swag should keep the operations annotated with @Hidden, which are left out by default
+	// along with the definitions only they use
+	IncludeHidden bool
+
+	// DefaultSchemes are the schemes of the spec when the general api info lacks @schemes
+	DefaultSchemes []string
+
+	// DefaultConsumes are the mime types, or their aliases like json, consumed by the spec and by the operations
+	// lacking @Accept
+	DefaultConsumes []string
+
+	// DefaultProduces are the mime types, or their aliases like json, produced by the spec and by the operations
+	// lacking @Produce
+	DefaultProduces []string
+
+	// Locale selects the summary and the description written by @Summary[locale] and @Description[locale], like ja,
+	// as the ones of the operations having them. They're kept as the x-summary-<locale> and x-description-<locale>
+	// extensions of the operations either way
+	Locale string
+
+	// Parallelism is the number of the Go source files parsed at once, GOMAXPROCS when it's zero or less. The spec
+	// doesn't depend on it
+	Parallelism int
+
+	// InferResponses whether swag should infer the 2xx responses of the Gin handlers lacking them from their
+	// c.JSON(code, obj) calls, on a best-effort basis
+	InferResponses bool
+
+	// ContinueOnError whether swag should skip the files failing to parse, logging their error with Debugger,
+	// instead of failing. An error listing them is returned only if no file could be parsed
+	ContinueOnError bool
+
+	// PrefixAnnotation whether swag should prepend the path of the file level @RouterPrefix annotation to the
+	// @Router paths of the file
+	PrefixAnnotation bool
+
+	// SkipDefaultOperationID whether swag should leave operationId empty when @ID is absent, instead of using the
+	// name of the handler function
+	SkipDefaultOperationID bool
+
 	// MarkdownFilesDir used to find markdownfiles, which can be used for tag descriptions
 	MarkdownFilesDir string
 
 	// GeneratedTime whether swag should generate the timestamp at the top of docs.go
 	GeneratedTime bool
 
+	// Template the file of the text/template generating docs.go instead of the default one, it's given the same
+	// data, like Doc holding the json spec, PackageName and GeneratedTime
+	Template string
+
 	// CodeExampleFilesDir used to find code example files, which can be used for x-codeSamples
 	CodeExampleFilesDir string
 
 	// ParseDepth dependency parse depth
 	ParseDepth int
+
+	// ValidateSemver whether swag should check that @version is a valid semantic version
+	ValidateSemver bool
+
+	// SkipValidateRefs whether swag should skip checking that the definitions referenced by $ref exist
+	SkipValidateRefs bool
+
+	// VersionPattern regular expression used instead of semantic versioning to validate @version when ValidateSemver is set
+	VersionPattern string
+
+	// LockFile whether swag should write swagger.lock holding a hash of the generated spec
+	LockFile bool
+
+	// DiffAgainst the path of a former swagger.json, like the committed one, to compare the generated spec with. The
+	// operations and definitions added, removed or changed since are listed in swagger.diff.txt
+	DiffAgainst string
+
+	// FailOnBreakingChange whether swag should fail, once the docs are written, when the spec has breaking changes
+	// since the one of DiffAgainst, like a removed operation or property
+	FailOnBreakingChange bool
+
+	// FilePerm the permission of the generated files, set regardless of the umask. The files are created like
+	// os.Create does when it's zero
+	FilePerm os.FileMode
+
+	// DirPerm the permission of OutputDir when swag creates it, set regardless of the umask. The directory is
+	// created like os.MkdirAll with os.ModePerm does when it's zero
+	DirPerm os.FileMode
+
+	// OutputTypes define types of files which should be generated, any of go,json,yaml,insomnia.
+	// Defaults to go,json,yaml when empty
+	OutputTypes []string
+
+	// SplitByTag whether swag should also write a swagger.<tag>.json file per tag, holding only the operations having
+	// the tag and the definitions they use. The operations without tag go into swagger.default.json
+	SplitByTag bool
+
+	// EmitJSONSchema whether swag should also write schemas.json, holding the definitions as JSON Schema draft-07
+	EmitJSONSchema bool
+
+	// JSONIndent indents the json of the generated files, like "    " or "\t", the json is minified when empty.
+	// The swag command indents with 4 spaces by default
+	JSONIndent string
+
+	// OutputMode either files, the default writing a file per output type, or single writing only docs.go
+	// which embeds the json spec as well
+	OutputMode string
+
+	// DurationType the type documenting time.Duration, either integer holding nanoseconds, the default, or string
+	DurationType string
+
+	// AliasTypes maps full type names like decimal.Decimal to the type documented instead, written like the
+	// swaggertype tag with an optional format after a colon, like primitive,number:double
+	AliasTypes map[string]string
+
+	// TypeOverrides maps type names to the schema documenting them, consulted before anything else. A type is looked
+	// up by the import path of its package followed by its name, like github.com/google/uuid.UUID, then by its
+	// package name followed by its name, like uuid.UUID
+	TypeOverrides map[string]*spec.Schema
+
+	// ExternalSpecs maps the names used in external://name#/definitions/Type to the json or yaml file of the spec,
+	// relative to the first search dir. The referenced definitions are copied into the generated spec
+	ExternalSpecs map[string]string
+
+	// CollectionFormat the default collectionFormat of array params in query, any of csv,ssv,tsv,pipes,multi
+	CollectionFormat string
+
+	// SpecProcessors are run in order on the parsed spec before it is written, an error aborts the generation
+	SpecProcessors []func(*spec.Swagger) error
+
+	// Debugger logs the progress of parsing and generating, an Info logger writing to the console when it's nil.
+	// swag.NewQuietLogger() silences them
+	Debugger swag.Debugger
 }
 
+// semverPattern matches semantic versions as defined by https://semver.org, with an optional v prefix
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+var defaultOutputTypes = []string{"go", "json", "yaml"}
+
+const (
+	// OutputModeFiles writes a file for every output type
+	OutputModeFiles = "files"
+	// OutputModeSingle writes docs.go only, embedding the json spec next to the doc template
+	OutputModeSingle = "single"
+)
+
+// outputFileNames maps every supported output type to the name of the file it produces
+var outputFileNames = map[string]string{
+	"go":       "docs.go",
+	"json":     "swagger.json",
+	"yaml":     "swagger.yaml",
+	"insomnia": "insomnia.json",
+}
+
+// lockFileName is the name of the file holding the hash of the generated spec
+const lockFileName = "swagger.lock"
+
+// generatedTimePattern matches the timestamp written at the top of docs.go when GeneratedTime is set
+var generatedTimePattern = regexp.MustCompile(`(?m)^(// This file was generated by swaggo/swag) at\n// .*$`)
+
 // Build builds swagger json file  for given searchDir and mainAPIFile. Returns json
 func (g *Gen) Build(config *Config) error {
-	if _, err := os.Stat(config.SearchDir); os.IsNotExist(err) {
-		return fmt.Errorf("dir: %s is not exist", config.SearchDir)
+	return g.generate(config, func(fileName string, content []byte) error {
+		if err := makeOutputDir(config.OutputDir, config.DirPerm); err != nil {
+			return err
+		}
+		if err := g.writeFile(content, fileName, config.FilePerm); err != nil {
+			return err
+		}
+		config.debugger().Infof("create %s at %+v", filepath.Base(fileName), fileName)
+		return nil
+	})
+}
+
+// BuildToBuffers generates the docs like Build but returns the content of every file by its path in OutputDir
+// instead of writing them, to compare them with the committed ones for instance.
+func (g *Gen) BuildToBuffers(config *Config) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := g.generate(config, func(fileName string, content []byte) error {
+		files[fileName] = content
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return files, nil
+}
 
-	log.Println("Generate swagger docs....")
-	p := swag.New(swag.SetMarkdownFileDirectory(config.MarkdownFilesDir),
-		swag.SetExcludedDirsAndFiles(config.Excludes),
-		swag.SetCodeExamplesDirectory(config.CodeExampleFilesDir))
-	p.PropNamingStrategy = config.PropNamingStrategy
-	p.ParseVendor = config.ParseVendor
-	p.ParseDependency = config.ParseDependency
-	p.ParseInternal = config.ParseInternal
+// generate parses the sources and calls emit with the path in OutputDir and the content of every generated file,
+// as soon as it's rendered
+func (g *Gen) generate(config *Config, emit func(fileName string, content []byte) error) error {
+	outputTypes, err := getOutputTypes(config)
+	if err != nil {
+		return err
+	}
 
-	if err := p.ParseAPI(config.SearchDir, config.MainAPIFile, config.ParseDepth); err != nil {
+	// an invalid template fails before anything is written
+	if _, err := goDocTemplate(config); err != nil {
 		return err
 	}
-	swagger := p.GetSwagger()
 
-	b, err := g.jsonIndent(swagger)
+	swagger, err := g.parseSwagger(config)
 	if err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll(config.OutputDir, os.ModePerm); err != nil {
-		return err
+	// the former spec is read before the new one may overwrite it
+	var changes []SpecChange
+	diffed := false
+	if config.DiffAgainst != "" {
+		if changes, diffed, err = diffAgainst(config, swagger); err != nil {
+			return err
+		}
 	}
 
-	absOutputDir, err := filepath.Abs(config.OutputDir)
+	b, err := g.jsonIndent(swagger, config.JSONIndent)
 	if err != nil {
 		return err
 	}
-	packageName := filepath.Base(absOutputDir)
-	docFileName := filepath.Join(config.OutputDir, "docs.go")
-	jsonFileName := filepath.Join(config.OutputDir, "swagger.json")
-	yamlFileName := filepath.Join(config.OutputDir, "swagger.yaml")
 
-	docs, err := os.Create(docFileName)
+	packageName, err := getPackageName(config.OutputDir)
 	if err != nil {
 		return err
 	}
-	defer docs.Close()
 
-	err = g.writeFile(b, jsonFileName)
+	for _, outputType := range outputTypes {
+		content, err := g.render(outputType, packageName, swagger, b, config)
+		if err != nil {
+			return err
+		}
+		if err := emit(filepath.Join(config.OutputDir, outputFileNames[outputType]), content); err != nil {
+			return err
+		}
+	}
+
+	if config.SplitByTag {
+		files, err := g.splitByTag(swagger, config)
+		if err != nil {
+			return err
+		}
+		for _, name := range sortedKeys(files) {
+			if err := emit(filepath.Join(config.OutputDir, name), files[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if config.EmitJSONSchema {
+		doc, err := ToJSONSchema(swagger)
+		if err != nil {
+			return err
+		}
+		content, err := g.jsonIndent(doc, config.JSONIndent)
+		if err != nil {
+			return err
+		}
+		if err := emit(filepath.Join(config.OutputDir, jsonSchemaFileName), content); err != nil {
+			return err
+		}
+	}
+
+	if config.LockFile {
+		if err := emit(filepath.Join(config.OutputDir, lockFileName), lockContent(b)); err != nil {
+			return err
+		}
+	}
+
+	if diffed {
+		if err := emit(filepath.Join(config.OutputDir, diffFileName), diffContent(changes)); err != nil {
+			return err
+		}
+		if config.FailOnBreakingChange {
+			return breakingChangesError(config, changes)
+		}
+	}
+	return nil
+}
+
+// CheckDrift regenerates the docs in memory and returns an error if they differ from the files in OutputDir,
+// which tells that the docs were not regenerated after a change of the annotated sources.
+func (g *Gen) CheckDrift(config *Config) error {
+	expected, err := g.BuildToBuffers(config)
 	if err != nil {
 		return err
 	}
 
-	y, err := g.jsonToYAML(b)
-	if err != nil {
-		return fmt.Errorf("cannot convert json to yaml error: %s", err)
+	var drifted []string
+	for _, fileName := range sortedKeys(expected) {
+		actual, err := ioutil.ReadFile(fileName)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		content := expected[fileName]
+		if filepath.Base(fileName) == outputFileNames["go"] {
+			content = generatedTimePattern.ReplaceAll(content, []byte("$1"))
+			actual = generatedTimePattern.ReplaceAll(actual, []byte("$1"))
+		}
+		if !bytes.Equal(content, actual) {
+			drifted = append(drifted, fileName)
+		}
+	}
+	if len(drifted) > 0 {
+		return fmt.Errorf("generated docs are out of date, run swag init to regenerate: %s", strings.Join(drifted, ", "))
+	}
+	return nil
+}
+
+// debugger returns Debugger, falling back on an Info logger
+func (config *Config) debugger() swag.Debugger {
+	if config.Debugger == nil {
+		return swag.NewLogger(swag.LogLevelInfo)
+	}
+	return config.Debugger
+}
+
+func (g *Gen) parseSwagger(config *Config) (*spec.Swagger, error) {
+	searchDirs := strings.Split(config.SearchDir, ",")
+	for _, searchDir := range searchDirs {
+		var err error
+		if config.FileSystem != nil {
+			_, err = fs.Stat(config.FileSystem, path.Clean(filepath.ToSlash(searchDir)))
+		} else {
+			_, err = os.Stat(searchDir)
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("dir: %s is not exist", searchDir)
+		}
 	}
 
-	err = g.writeFile(y, yamlFileName)
+	if config.CollectionFormat != "" && swag.TransToValidCollectionFormat(config.CollectionFormat) == "" {
+		return nil, fmt.Errorf("collection format %s is not one of csv, ssv, tsv, pipes, multi", config.CollectionFormat)
+	}
+	switch config.DurationType {
+	case "", swag.INTEGER, swag.STRING:
+	default:
+		return nil, fmt.Errorf("duration type %s is not one of integer, string", config.DurationType)
+	}
+
+	config.debugger().Infof("Generate swagger docs....")
+	p := swag.New(swag.SetDebugger(config.debugger()),
+		swag.SetCollectionFormat(config.CollectionFormat),
+		swag.SetMarkdownFileDirectory(config.MarkdownFilesDir),
+		swag.SetExcludedDirsAndFiles(config.Excludes),
+		swag.SetTags(config.Tags),
+		swag.SetCodeExamplesDirectory(config.CodeExampleFilesDir))
+	p.PropNamingStrategy = config.PropNamingStrategy
+	p.NameResolver = config.NameResolver
+	p.DefinitionNaming = config.DefinitionNaming
+	p.ModelNamer = config.ModelNamer
+	p.ParseVendor = config.ParseVendor
+	p.FileSystem = config.FileSystem
+	p.ParseDependency = config.ParseDependency
+	p.ParseInternal = config.ParseInternal
+	p.ParseGoList = config.ParseGoList
+	p.DefaultOperationID = !config.SkipDefaultOperationID
+	p.EmitMsEnum = config.EmitMsEnum
+	p.Nullable = config.Nullable
+	p.PruneUnusedDefinitions = config.PruneUnusedDefinitions
+	p.DedupeInlineSchemas = config.DedupeInlineSchemas
+	p.InlineSingleUse = config.InlineSingleUse
+	p.ResolveInterfaces = config.ResolveInterfaces
+	p.IncludeHidden = config.IncludeHidden
+	p.DefaultSchemes = config.DefaultSchemes
+	p.DefaultConsumes = config.DefaultConsumes
+	p.DefaultProduces = config.DefaultProduces
+	p.Locale = config.Locale
+	p.Parallelism = config.Parallelism
+	p.PrefixAnnotation = config.PrefixAnnotation
+	p.ContinueOnError = config.ContinueOnError
+	p.InferResponses = config.InferResponses
+	p.RequiredByDefault = config.RequiredByDefault
+	p.Int64AsString = config.Int64AsString
+	p.SwagDirectiveStyle = config.SwagDirectiveStyle
+	p.ParseGeneralInfoAcrossDir = config.ParseGeneralInfoAcrossDir
+	p.AliasTypes = config.AliasTypes
+	p.TypeOverrides = config.TypeOverrides
+	p.ExternalSpecs = config.ExternalSpecs
+	p.DurationType = config.DurationType
+
+	if err := p.ParseAPIMultiSearchDir(searchDirs, config.MainAPIFile, config.ParseDepth); err != nil {
+		return nil, err
+	}
+	swagger := p.GetSwagger()
+
+	if config.ValidateSemver {
+		if err := validateVersion(swagger.Info.Version, config.VersionPattern); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, process := range config.SpecProcessors {
+		if err := process(swagger); err != nil {
+			return nil, err
+		}
+	}
+
+	if !config.SkipValidateRefs {
+		if err := validateRefs(swagger); err != nil {
+			return nil, err
+		}
+	}
+
+	return swagger, nil
+}
+
+func getOutputTypes(config *Config) ([]string, error) {
+	switch config.OutputMode {
+	case "", OutputModeFiles:
+	case OutputModeSingle:
+		return []string{"go"}, nil
+	default:
+		return nil, fmt.Errorf("output mode %s is not supported", config.OutputMode)
+	}
+
+	outputTypes := config.OutputTypes
+	if len(outputTypes) == 0 {
+		outputTypes = defaultOutputTypes
+	}
+	for _, outputType := range outputTypes {
+		if _, ok := outputFileNames[outputType]; !ok {
+			return nil, fmt.Errorf("output type %s is not supported", outputType)
+		}
+	}
+	return outputTypes, nil
+}
+
+func getPackageName(outputDir string) (string, error) {
+	absOutputDir, err := filepath.Abs(outputDir)
 	if err != nil {
-		return err
+		return "", err
+	}
+	return filepath.Base(absOutputDir), nil
+}
+
+// render generates the content of the file of outputType, b being the indented json of swagger
+func (g *Gen) render(outputType, packageName string, swagger *spec.Swagger, b []byte, config *Config) ([]byte, error) {
+	switch outputType {
+	case "go":
+		var buf bytes.Buffer
+		if err := g.writeGoDoc(packageName, &buf, swagger, config); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case "yaml":
+		y, err := g.jsonToYAML(b)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert json to yaml error: %s", err)
+		}
+		return y, nil
+	case "insomnia":
+		return g.jsonIndent(ToInsomnia(swagger), config.JSONIndent)
+	}
+	return b, nil
+}
+
+// defaultTag names the file of the operations without tag when splitting the spec by tag
+const defaultTag = "default"
+
+// splitByTag returns the json of a spec per tag, by file name, each one holding the operations having the tag and
+// the definitions they use
+func (g *Gen) splitByTag(swagger *spec.Swagger, config *Config) (map[string][]byte, error) {
+	tags := map[string]bool{}
+	for path, itm := range swagger.Paths.Paths {
+		if !strings.HasPrefix(path, "/") {
+			continue
+		}
+		for _, operation := range []*spec.Operation{itm.Get, itm.Put, itm.Post, itm.Delete, itm.Options, itm.Head, itm.Patch} {
+			if operation == nil {
+				continue
+			}
+			if len(operation.Tags) == 0 {
+				tags[defaultTag] = true
+			}
+			for _, tag := range operation.Tags {
+				tags[tag] = true
+			}
+		}
 	}
 
-	// Write doc
-	err = g.writeGoDoc(packageName, docs, swagger, config)
+	b, err := json.Marshal(swagger)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	files := make(map[string][]byte, len(tags))
+	for tag := range tags {
+		tagged := &spec.Swagger{}
+		if err := json.Unmarshal(b, tagged); err != nil {
+			return nil, err
+		}
+		swag.FilterOperations(tagged, func(tags []string) bool {
+			if tag == defaultTag && len(tags) == 0 {
+				return true
+			}
+			for _, name := range tags {
+				if name == tag {
+					return true
+				}
+			}
+			return false
+		})
+		content, err := g.jsonIndent(tagged, config.JSONIndent)
+		if err != nil {
+			return nil, err
+		}
+		files["swagger."+strings.ReplaceAll(tag, string(filepath.Separator), "_")+".json"] = content
 	}
+	return files, nil
+}
 
-	log.Printf("create docs.go at %+v", docFileName)
-	log.Printf("create swagger.json at %+v", jsonFileName)
-	log.Printf("create swagger.yaml at %+v", yamlFileName)
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lockContent is the content of swagger.lock, the sha256 hash of the json spec b
+func lockContent(b []byte) []byte {
+	return []byte(fmt.Sprintf("sha256:%x\n", sha256.Sum256(b)))
+}
+
+// validateVersion checks version against pattern, or against semantic versioning when pattern is empty
+func validateVersion(version, pattern string) error {
+	re := semverPattern
+	if pattern != "" {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid version pattern %s: %s", pattern, err)
+		}
+	}
 
+	if !re.MatchString(version) {
+		return fmt.Errorf("@version %q does not match %s", version, re.String())
+	}
 	return nil
 }
 
-func (g *Gen) writeFile(b []byte, file string) error {
+// makeOutputDir creates dir with its parents unless it exists, perm being os.ModePerm when zero
+func makeOutputDir(dir string, perm os.FileMode) error {
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+	if perm == 0 {
+		return os.MkdirAll(dir, os.ModePerm)
+	}
+	if err := os.MkdirAll(dir, perm); err != nil {
+		return err
+	}
+	return os.Chmod(dir, perm)
+}
+
+// writeFile writes b to file, setting its permission to perm unless it's zero
+func (g *Gen) writeFile(b []byte, file string, perm os.FileMode) error {
 	f, err := os.Create(file)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
+	if perm != 0 {
+		if err = f.Chmod(perm); err != nil {
+			return err
+		}
+	}
 	_, err = f.Write(b)
 	return err
 }
@@ -164,15 +707,31 @@ func (g *Gen) formatSource(src []byte) []byte {
 	return code
 }
 
-func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swagger, config *Config) error {
+// goDocTemplate parses the template of docs.go, packageTemplate unless Config.Template is set
+func goDocTemplate(config *Config) (*template.Template, error) {
+	text := packageTemplate
+	if config.Template != "" {
+		b, err := ioutil.ReadFile(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %s", config.Template, err)
+		}
+		text = string(b)
+	}
+
 	generator, err := template.New("swagger_info").Funcs(template.FuncMap{
 		"printDoc": func(v string) string {
-			// Add schemes
-			v = "{\n    \"schemes\": {{ marshal .Schemes }}," + v[1:]
 			// Sanitize backticks
 			return strings.Replace(v, "`", "`+\"`\"+`", -1)
 		},
-	}).Parse(packageTemplate)
+	}).Parse(text)
+	if err != nil && config.Template != "" {
+		return nil, fmt.Errorf("invalid template %s: %s", config.Template, err)
+	}
+	return generator, err
+}
+
+func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swagger, config *Config) error {
+	generator, err := goDocTemplate(config)
 	if err != nil {
 		return err
 	}
@@ -209,16 +768,30 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 	}
 
 	// crafted docs.json
-	buf, err := g.jsonIndent(swaggerSpec)
+	buf, err := g.jsonIndent(swaggerSpec, config.JSONIndent)
 	if err != nil {
 		return err
 	}
+	// Add schemes
+	schemes := "\"schemes\":{{ marshal .Schemes }},"
+	if config.JSONIndent != "" {
+		schemes = "\n" + config.JSONIndent + "\"schemes\": {{ marshal .Schemes }},"
+	}
+	doc := "{" + schemes + string(buf[1:])
+
+	var rawSpec []byte
+	if config.OutputMode == OutputModeSingle {
+		if rawSpec, err = g.jsonIndent(swagger, config.JSONIndent); err != nil {
+			return err
+		}
+	}
 
 	buffer := &bytes.Buffer{}
 	err = generator.Execute(buffer, struct {
 		Timestamp     time.Time
 		GeneratedTime bool
 		Doc           string
+		Spec          string
 		Host          string
 		PackageName   string
 		BasePath      string
@@ -229,7 +802,8 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 	}{
 		Timestamp:     time.Now(),
 		GeneratedTime: config.GeneratedTime,
-		Doc:           string(buf),
+		Doc:           doc,
+		Spec:          string(rawSpec),
 		Host:          swagger.Host,
 		PackageName:   packageName,
 		BasePath:      swagger.BasePath,
@@ -264,7 +838,10 @@ import (
 )
 
 var doc = ` + "`{{ printDoc .Doc}}`" + `
-
+{{ if .Spec }}
+// SwaggerJSON holds the generated spec as it would be written to swagger.json
+const SwaggerJSON = ` + "`{{ printDoc .Spec}}`" + `
+{{ end }}
 type swaggerInfo struct {
 	Version     string
 	Host        string
@@ -1,14 +1,21 @@
 package gen
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"go/format"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/go-openapi/spec"
 	"github.com/stretchr/testify/assert"
+	"github.com/swaggo/swag"
 )
 
 func TestGen_Build(t *testing.T) {
@@ -35,6 +42,44 @@ func TestGen_Build(t *testing.T) {
 	}
 }
 
+type recordingDebugger struct {
+	messages []string
+}
+
+func (d *recordingDebugger) Errorf(format string, v ...interface{}) {}
+
+func (d *recordingDebugger) Warnf(format string, v ...interface{}) {}
+
+func (d *recordingDebugger) Infof(format string, v ...interface{}) {
+	d.messages = append(d.messages, fmt.Sprintf(format, v...))
+}
+
+func TestGen_BuildDebugger(t *testing.T) {
+	debugger := &recordingDebugger{}
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json"},
+		Debugger:    debugger,
+	}
+	assert.NoError(t, New().Build(config))
+	defer os.Remove(filepath.Join(config.OutputDir, "swagger.json"))
+
+	assert.Contains(t, debugger.messages, "Generate swagger docs....")
+	assert.Contains(t, debugger.messages, "create swagger.json at "+filepath.Join(config.OutputDir, "swagger.json"))
+	assert.Contains(t, debugger.messages, "Generating web.Pet")
+}
+
+func TestGen_DefaultDebugger(t *testing.T) {
+	// the console output is kept unless a Debugger is given
+	config := &Config{}
+	assert.Equal(t, swag.NewLogger(swag.LogLevelInfo), config.debugger())
+
+	config.Debugger = swag.NewQuietLogger()
+	assert.Equal(t, swag.NewQuietLogger(), config.debugger())
+}
+
 func TestGen_BuildSnakecase(t *testing.T) {
 	searchDir := "../testdata/simple2"
 	config := &Config{
@@ -93,7 +138,7 @@ func TestGen_jsonIndent(t *testing.T) {
 		PropNamingStrategy: "",
 	}
 	gen := New()
-	gen.jsonIndent = func(data interface{}) ([]byte, error) {
+	gen.jsonIndent = func(data interface{}, indent string) ([]byte, error) {
 		return nil, errors.New("fail")
 	}
 	assert.Error(t, gen.Build(config))
@@ -345,6 +390,39 @@ func TestGen_GeneratedDoc(t *testing.T) {
 	}
 }
 
+func TestGen_OutputModeSingle(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json", "yaml"},
+		OutputMode:  OutputModeSingle,
+		JSONIndent:  "    ",
+	}
+	assert.NoError(t, New().Build(config))
+	defer os.Remove(filepath.Join(config.OutputDir, "docs.go"))
+
+	for _, fileName := range []string{"swagger.json", "swagger.yaml"} {
+		_, err := os.Stat(filepath.Join(config.OutputDir, fileName))
+		assert.True(t, os.IsNotExist(err), fileName)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(config.OutputDir, "docs.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "const SwaggerJSON = `{\n    \"swagger\": \"2.0\",")
+	assert.Contains(t, string(b), "swag.Register(swag.Name, &s{})")
+
+	gocmd, err := exec.LookPath("go")
+	assert.NoError(t, err)
+	cmd := exec.Command(gocmd, "build", filepath.Join(config.OutputDir, "docs.go"))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	assert.NoError(t, cmd.Run())
+
+	config.OutputMode = "zip"
+	assert.EqualError(t, New().Build(config), "output mode zip is not supported")
+}
+
 func TestGen_cgoImports(t *testing.T) {
 	searchDir := "../testdata/simple_cgo"
 
@@ -370,3 +448,551 @@ func TestGen_cgoImports(t *testing.T) {
 		os.Remove(expectedFile)
 	}
 }
+
+func TestGen_BuildOutputTypes(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json", "insomnia"},
+	}
+
+	assert.NoError(t, New().Build(config))
+
+	expectedFiles := []string{
+		filepath.Join(config.OutputDir, "swagger.json"),
+		filepath.Join(config.OutputDir, "insomnia.json"),
+	}
+	for _, expectedFile := range expectedFiles {
+		if _, err := os.Stat(expectedFile); os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		os.Remove(expectedFile)
+	}
+
+	for _, unexpectedFile := range []string{"docs.go", "swagger.yaml"} {
+		_, err := os.Stat(filepath.Join(config.OutputDir, unexpectedFile))
+		assert.True(t, os.IsNotExist(err))
+	}
+
+	config.OutputTypes = []string{"go", "xml"}
+	assert.EqualError(t, New().Build(config), "output type xml is not supported")
+}
+
+func TestGen_OperationsWithoutRouter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swag")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// AnonymousField and Pet2 of simple3 lack @Router
+	config := &Config{
+		SearchDir:   "../testdata/simple3",
+		MainAPIFile: "./main.go",
+		OutputDir:   dir,
+		OutputTypes: []string{"go", "json", "yaml"},
+	}
+	assert.NoError(t, New().Build(config))
+
+	for _, fileName := range []string{"docs.go", "swagger.json", "swagger.yaml"} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(b), `"":`, fileName)
+		assert.Contains(t, string(b), "/testapi/get-string-by-int/{some_id}", fileName)
+	}
+}
+
+func TestGen_JSONIndent(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"go", "json", "yaml"},
+	}
+	defer func() {
+		for _, fileName := range []string{"docs.go", "swagger.json", "swagger.yaml"} {
+			_ = os.Remove(filepath.Join(config.OutputDir, fileName))
+		}
+	}()
+
+	assert.NoError(t, New().Build(config))
+	b, err := ioutil.ReadFile(filepath.Join(config.OutputDir, "swagger.json"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(b), `{"swagger":"2.0","info":{"description":`))
+	assert.NotContains(t, string(b), "\n")
+	b, err = ioutil.ReadFile(filepath.Join(config.OutputDir, "docs.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "var doc = `{\"schemes\":{{ marshal .Schemes }},\"swagger\":\"2.0\",")
+	minifiedYAML, err := ioutil.ReadFile(filepath.Join(config.OutputDir, "swagger.yaml"))
+	assert.NoError(t, err)
+
+	config.JSONIndent = "\t"
+	assert.NoError(t, New().Build(config))
+	b, err = ioutil.ReadFile(filepath.Join(config.OutputDir, "swagger.json"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(b), "{\n\t\"swagger\": \"2.0\",\n\t\"info\": {\n\t\t\"description\":"))
+	b, err = ioutil.ReadFile(filepath.Join(config.OutputDir, "docs.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "var doc = `{\n\t\"schemes\": {{ marshal .Schemes }},\n\t\"swagger\": \"2.0\",")
+	indentedYAML, err := ioutil.ReadFile(filepath.Join(config.OutputDir, "swagger.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(minifiedYAML), string(indentedYAML))
+}
+
+func TestGen_SplitByTag(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/tags_filter",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/tags_filter/docs",
+		OutputTypes: []string{"json"},
+		SplitByTag:  true,
+	}
+	defer os.RemoveAll(config.OutputDir)
+	assert.NoError(t, New().Build(config))
+
+	for fileName, expected := range map[string]struct {
+		paths       []string
+		definitions []string
+	}{
+		"swagger.public.json":     {[]string{"/users", "/users/{id}"}, []string{"main.Address", "main.User"}},
+		"swagger.deprecated.json": {[]string{"/users"}, []string{"main.Address", "main.User"}},
+		"swagger.internal.json":   {[]string{"/audit", "/users/{id}"}, []string{"main.AuditLog"}},
+		"swagger.default.json":    {[]string{"/health"}, nil},
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(config.OutputDir, fileName))
+		assert.NoError(t, err)
+		swagger := &spec.Swagger{}
+		assert.NoError(t, json.Unmarshal(b, swagger))
+
+		var paths, definitions []string
+		for path := range swagger.Paths.Paths {
+			paths = append(paths, path)
+		}
+		for name := range swagger.Definitions {
+			definitions = append(definitions, name)
+		}
+		assert.ElementsMatch(t, expected.paths, paths, fileName)
+		assert.ElementsMatch(t, expected.definitions, definitions, fileName)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(config.OutputDir, "swagger.internal.json"))
+	assert.NoError(t, err)
+	swagger := &spec.Swagger{}
+	assert.NoError(t, json.Unmarshal(b, swagger))
+	assert.Nil(t, swagger.Paths.Paths["/users/{id}"].Get)
+	assert.NotNil(t, swagger.Paths.Paths["/users/{id}"].Delete)
+	assert.Equal(t, []spec.Tag{spec.NewTag("internal", "Operations of the back office", nil)}, swagger.Tags)
+
+	assert.NoError(t, New().CheckDrift(config))
+}
+
+func TestGen_ValidateSemver(t *testing.T) {
+	config := &Config{
+		SearchDir:      "../testdata/simple",
+		MainAPIFile:    "./main.go",
+		OutputDir:      "../testdata/simple/docs",
+		OutputTypes:    []string{"json"},
+		ValidateSemver: true,
+	}
+	assert.Error(t, New().Build(config))
+	_, err := os.Stat(filepath.Join(config.OutputDir, "swagger.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	config.VersionPattern = `^\d+\.\d+$`
+	assert.NoError(t, New().Build(config))
+	assert.NoError(t, os.Remove(filepath.Join(config.OutputDir, "swagger.json")))
+}
+
+func TestGen_CollectionFormat(t *testing.T) {
+	config := &Config{
+		SearchDir:        "../testdata/simple",
+		MainAPIFile:      "./main.go",
+		OutputDir:        "../testdata/simple/docs",
+		OutputTypes:      []string{"json"},
+		CollectionFormat: "comma",
+	}
+	assert.EqualError(t, New().Build(config), "collection format comma is not one of csv, ssv, tsv, pipes, multi")
+
+	config.CollectionFormat = "multi"
+	assert.NoError(t, New().Build(config))
+	assert.NoError(t, os.Remove(filepath.Join(config.OutputDir, "swagger.json")))
+}
+
+func TestGen_DurationType(t *testing.T) {
+	config := &Config{
+		SearchDir:    "../testdata/simple",
+		MainAPIFile:  "./main.go",
+		OutputDir:    "../testdata/simple/docs",
+		OutputTypes:  []string{"json"},
+		DurationType: "number",
+	}
+	assert.EqualError(t, New().Build(config), "duration type number is not one of integer, string")
+}
+
+func TestGen_SpecProcessors(t *testing.T) {
+	var calls []string
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json"},
+		JSONIndent:  "    ",
+		SpecProcessors: []func(*spec.Swagger) error{
+			func(swagger *spec.Swagger) error {
+				calls = append(calls, "title")
+				swagger.Info.Title = "Processed API"
+				return nil
+			},
+			func(swagger *spec.Swagger) error {
+				calls = append(calls, "version")
+				swagger.Info.Version = swagger.Info.Title + " 2.0"
+				return nil
+			},
+		},
+	}
+	assert.NoError(t, New().Build(config))
+
+	b, err := ioutil.ReadFile(filepath.Join(config.OutputDir, "swagger.json"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(filepath.Join(config.OutputDir, "swagger.json")))
+	assert.Contains(t, string(b), `"title": "Processed API"`)
+	assert.Contains(t, string(b), `"version": "Processed API 2.0"`)
+	assert.Equal(t, []string{"title", "version"}, calls)
+
+	config.SpecProcessors = append(config.SpecProcessors, func(swagger *spec.Swagger) error {
+		return errors.New("rejected")
+	})
+	assert.EqualError(t, New().Build(config), "rejected")
+	_, err = os.Stat(filepath.Join(config.OutputDir, "swagger.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGen_ValidateRefs(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json"},
+		SpecProcessors: []func(*spec.Swagger) error{
+			func(swagger *spec.Swagger) error {
+				swagger.Definitions["web.Pet"] = spec.Schema{SchemaProps: spec.SchemaProps{
+					Properties: map[string]spec.Schema{"owner": *spec.RefSchema("#/definitions/web.Uesr")},
+				}}
+				operation := swagger.Paths.Paths["/testapi/get-string-by-int/{some_id}"].Get
+				operation.Responses.StatusCodeResponses[200] = spec.Response{ResponseProps: spec.ResponseProps{
+					Schema: spec.RefSchema("#/definitions/web.Uesr"),
+				}}
+				return nil
+			},
+		},
+	}
+	assert.EqualError(t, New().Build(config), "missing definitions referenced by: "+
+		"#/definitions/web.Uesr in definition web.Pet, "+
+		"#/definitions/web.Uesr in operation GET /testapi/get-string-by-int/{some_id}")
+
+	config.SkipValidateRefs = true
+	assert.NoError(t, New().Build(config))
+	assert.NoError(t, os.Remove(filepath.Join(config.OutputDir, "swagger.json")))
+
+	config.SkipValidateRefs = false
+	config.SpecProcessors = nil
+	assert.NoError(t, New().Build(config))
+	assert.NoError(t, os.Remove(filepath.Join(config.OutputDir, "swagger.json")))
+}
+
+func TestGen_DefaultOperationID(t *testing.T) {
+	var ids []string
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json"},
+		SpecProcessors: []func(*spec.Swagger) error{
+			func(swagger *spec.Swagger) error {
+				ids = []string{
+					swagger.Paths.Paths["/testapi/get-string-by-int/{some_id}"].Get.ID,
+					swagger.Paths.Paths["/Pet2"].Get.ID,
+				}
+				return nil
+			},
+		},
+	}
+	assert.NoError(t, New().Build(config))
+	assert.Equal(t, []string{"get-string-by-int", "Pet2"}, ids)
+
+	config.SkipDefaultOperationID = true
+	assert.NoError(t, New().Build(config))
+	assert.Equal(t, []string{"get-string-by-int", ""}, ids)
+	assert.NoError(t, os.Remove(filepath.Join(config.OutputDir, "swagger.json")))
+}
+
+func TestValidateVersion(t *testing.T) {
+	for _, version := range []string{"1.0.0", "v1.2.3", "1.0.0-alpha.1", "1.0.0+build.5", "10.20.30-rc.1+001"} {
+		assert.NoError(t, validateVersion(version, ""), version)
+	}
+	for _, version := range []string{"", "1.0", "v1..2", "01.0.0", "1.0.0-", "1.0.0 beta"} {
+		assert.Error(t, validateVersion(version, ""), version)
+	}
+
+	assert.NoError(t, validateVersion("2021-03", `^\d{4}-\d{2}$`))
+	assert.Error(t, validateVersion("2021-03", `(`))
+}
+
+func TestGen_BuildMultiSearchDir(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/multi_search_dir/main,../testdata/multi_search_dir/user",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/multi_search_dir/docs",
+		OutputTypes: []string{"json"},
+	}
+	assert.NoError(t, New().Build(config))
+	assert.NoError(t, os.RemoveAll(config.OutputDir))
+
+	config.SearchDir = "../testdata/multi_search_dir/main,../testdata/multi_search_dir/none"
+	assert.EqualError(t, New().Build(config), "dir: ../testdata/multi_search_dir/none is not exist")
+}
+
+func TestGen_BuildLockFile(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json"},
+		LockFile:    true,
+	}
+	lockFile := filepath.Join(config.OutputDir, "swagger.lock")
+
+	assert.NoError(t, New().Build(config))
+	first, err := ioutil.ReadFile(lockFile)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}\n$`, string(first))
+
+	assert.NoError(t, New().Build(config))
+	second, err := ioutil.ReadFile(lockFile)
+	assert.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+
+	assert.NoError(t, os.RemoveAll(config.OutputDir))
+}
+
+func TestGen_DiffAgainst(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json"},
+		DiffAgainst: "../testdata/simple/docs/swagger.json",
+	}
+	defer os.RemoveAll(config.OutputDir)
+	diffFile := filepath.Join(config.OutputDir, "swagger.diff.txt")
+
+	// nothing to diff against yet
+	assert.NoError(t, New().Build(config))
+	_, err := os.Stat(diffFile)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, New().Build(config))
+	diff, err := ioutil.ReadFile(diffFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "no changes\n", string(diff))
+
+	// the former spec had a nickname and a numeric name
+	b, err := ioutil.ReadFile(config.DiffAgainst)
+	assert.NoError(t, err)
+	var swagger spec.Swagger
+	assert.NoError(t, json.Unmarshal(b, &swagger))
+	pet := swagger.Definitions["web.Pet"]
+	pet.Properties["nickname"] = *spec.StringProperty()
+	pet.Properties["name"] = *spec.Int64Property()
+	swagger.Definitions["web.Pet"] = pet
+	b, err = json.Marshal(swagger)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(config.DiffAgainst, b, 0644))
+
+	config.FailOnBreakingChange = true
+	assert.EqualError(t, New().Build(config), "breaking changes since ../testdata/simple/docs/swagger.json: "+
+		"changed definition web.Pet: changed the type of property name (breaking), "+
+		"changed definition web.Pet: removed property nickname (breaking)")
+	diff, err = ioutil.ReadFile(diffFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "changed definition web.Pet: changed the type of property name (breaking)\n"+
+		"changed definition web.Pet: removed property nickname (breaking)\n", string(diff))
+}
+
+func TestGen_StableGoDoc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swag")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the functions lacking @Router of simple3 are parsed in no particular order
+	config := &Config{
+		SearchDir:   "../testdata/simple3",
+		MainAPIFile: "./main.go",
+		OutputDir:   dir,
+		OutputTypes: []string{"go"},
+	}
+	var first []byte
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, New().Build(config))
+		docs, err := ioutil.ReadFile(filepath.Join(dir, "docs.go"))
+		assert.NoError(t, err)
+		if first == nil {
+			first = docs
+			continue
+		}
+		assert.Equal(t, string(first), string(docs))
+	}
+	assert.NotContains(t, string(first), `"":`)
+}
+
+func TestGen_FilePerm(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swag")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   filepath.Join(dir, "docs"),
+		FilePerm:    0664,
+		DirPerm:     0775,
+	}
+	assert.NoError(t, New().Build(config))
+
+	info, err := os.Stat(config.OutputDir)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0775), info.Mode().Perm())
+	for _, fileName := range []string{"docs.go", "swagger.json", "swagger.yaml"} {
+		info, err := os.Stat(filepath.Join(config.OutputDir, fileName))
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0664), info.Mode().Perm(), fileName)
+	}
+}
+
+func TestGen_Template(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swag")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	templateFile := filepath.Join(dir, "docs.go.tmpl")
+	assert.NoError(t, ioutil.WriteFile(templateFile, []byte(`package {{.PackageName}}
+
+const doc = `+"`{{ printDoc .Doc }}`"+`
+`), 0644))
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   filepath.Join(dir, "docs"),
+		OutputTypes: []string{"go"},
+		Template:    templateFile,
+	}
+	assert.NoError(t, New().Build(config))
+	b, err := ioutil.ReadFile(filepath.Join(config.OutputDir, "docs.go"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(b), "package docs\n\nconst doc = `{"))
+
+	config.OutputDir = filepath.Join(dir, "invalid")
+	assert.NoError(t, ioutil.WriteFile(templateFile, []byte(`{{ .Doc `), 0644))
+	assert.Error(t, New().Build(config))
+	_, err = os.Stat(config.OutputDir)
+	assert.True(t, os.IsNotExist(err))
+
+	config.Template = filepath.Join(dir, "missing.tmpl")
+	assert.Error(t, New().Build(config))
+}
+
+func TestGen_FileSystem(t *testing.T) {
+	config := &Config{
+		SearchDir:   "api",
+		MainAPIFile: "main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: []string{"json"},
+		FileSystem: fstest.MapFS{
+			"api/main.go": {Data: []byte("package main\n\n// @title In memory API\n// @version 1.0\nfunc main() {}\n")},
+		},
+	}
+	files, err := New().BuildToBuffers(config)
+	assert.NoError(t, err)
+	assert.Contains(t, string(files[filepath.Join(config.OutputDir, "swagger.json")]), "In memory API")
+
+	config.SearchDir = "missing"
+	_, err = New().BuildToBuffers(config)
+	assert.EqualError(t, err, "dir: missing is not exist")
+}
+
+func TestGen_MainAPIFileResolution(t *testing.T) {
+	absMainAPIFile, err := filepath.Abs("../testdata/simple/main.go")
+	assert.NoError(t, err)
+	// relative to SearchDir, relative to the working directory like with go:generate, and absolute
+	for _, mainAPIFile := range []string{"./main.go", "../testdata/simple/main.go", absMainAPIFile} {
+		files, err := New().BuildToBuffers(&Config{
+			SearchDir:   "../testdata/simple",
+			MainAPIFile: mainAPIFile,
+			OutputDir:   "../testdata/simple/docs",
+			OutputTypes: []string{"json"},
+		})
+		assert.NoError(t, err, mainAPIFile)
+		assert.Contains(t, string(files[filepath.Join("../testdata/simple/docs", "swagger.json")]), "Swagger Example API", mainAPIFile)
+	}
+
+	_, err = New().BuildToBuffers(&Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "missing.go",
+		OutputDir:   "../testdata/simple/docs",
+	})
+	assert.EqualError(t, err, "cannot find the general API info file missing.go, tried ../testdata/simple/missing.go and missing.go")
+}
+
+func TestGen_BuildToBuffers(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		JSONIndent:  "    ",
+		LockFile:    true,
+	}
+	defer os.RemoveAll(config.OutputDir)
+
+	files, err := New().BuildToBuffers(config)
+	assert.NoError(t, err)
+	_, err = os.Stat(config.OutputDir)
+	assert.True(t, os.IsNotExist(err))
+
+	var fileNames []string
+	for fileName := range files {
+		fileNames = append(fileNames, filepath.Base(fileName))
+	}
+	assert.ElementsMatch(t, []string{"docs.go", "swagger.json", "swagger.yaml", "swagger.lock"}, fileNames)
+
+	docs := files[filepath.Join(config.OutputDir, "docs.go")]
+	formatted, err := format.Source(docs)
+	assert.NoError(t, err)
+	assert.Equal(t, string(formatted), string(docs))
+
+	assert.NoError(t, New().Build(config))
+	for fileName, content := range files {
+		written, err := ioutil.ReadFile(fileName)
+		assert.NoError(t, err)
+		assert.Equal(t, string(written), string(content))
+	}
+}
+
+func TestGen_CheckDrift(t *testing.T) {
+	config := &Config{
+		SearchDir:     "../testdata/simple",
+		MainAPIFile:   "./main.go",
+		OutputDir:     "../testdata/simple/docs",
+		GeneratedTime: true,
+		LockFile:      true,
+	}
+	defer os.RemoveAll(config.OutputDir)
+
+	assert.Error(t, New().CheckDrift(config))
+
+	assert.NoError(t, New().Build(config))
+	assert.NoError(t, New().CheckDrift(config))
+
+	jsonFile := filepath.Join(config.OutputDir, "swagger.json")
+	assert.NoError(t, ioutil.WriteFile(jsonFile, []byte("{}"), 0644))
+	assert.EqualError(t, New().CheckDrift(config),
+		"generated docs are out of date, run swag init to regenerate: "+jsonFile)
+}
@@ -0,0 +1,388 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	insomniaWorkspaceID   = "wrk_swag"
+	insomniaEnvironmentID = "env_swag"
+)
+
+// InsomniaExport presents an Insomnia v4 export document.
+type InsomniaExport struct {
+	Type         string        `json:"_type"`
+	ExportFormat int           `json:"__export_format"`
+	ExportSource string        `json:"__export_source"`
+	Resources    []interface{} `json:"resources"`
+}
+
+type insomniaWorkspace struct {
+	ID          string  `json:"_id"`
+	Type        string  `json:"_type"`
+	ParentID    *string `json:"parentId"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Scope       string  `json:"scope"`
+}
+
+type insomniaEnvironment struct {
+	ID       string            `json:"_id"`
+	Type     string            `json:"_type"`
+	ParentID string            `json:"parentId"`
+	Name     string            `json:"name"`
+	Data     map[string]string `json:"data"`
+}
+
+type insomniaRequestGroup struct {
+	ID          string `json:"_id"`
+	Type        string `json:"_type"`
+	ParentID    string `json:"parentId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type insomniaRequest struct {
+	ID             string            `json:"_id"`
+	Type           string            `json:"_type"`
+	ParentID       string            `json:"parentId"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Body           insomniaBody      `json:"body"`
+	Parameters     []insomniaPair    `json:"parameters"`
+	Headers        []insomniaPair    `json:"headers"`
+	Authentication map[string]string `json:"authentication"`
+}
+
+type insomniaBody struct {
+	MimeType string         `json:"mimeType,omitempty"`
+	Text     string         `json:"text,omitempty"`
+	Params   []insomniaPair `json:"params,omitempty"`
+}
+
+type insomniaPair struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+var insomniaVarPattern = regexp.MustCompile(`[^\w]+`)
+
+// insomniaVar builds the Insomnia template for an environment variable named after the given schema name
+func insomniaVar(name string) string {
+	return "{{ _." + insomniaVarName(name) + " }}"
+}
+
+func insomniaVarName(name string) string {
+	return strings.Trim(insomniaVarPattern.ReplaceAllString(name, "_"), "_")
+}
+
+// ToInsomnia converts swagger to an Insomnia v4 export. Operations are grouped into request groups by their
+// first tag, the base url and credentials of the security definitions become environment variables.
+func ToInsomnia(swagger *spec.Swagger) *InsomniaExport {
+	title := ""
+	description := ""
+	if swagger.Info != nil {
+		title = swagger.Info.Title
+		description = swagger.Info.Description
+	}
+
+	export := &InsomniaExport{
+		Type:         "export",
+		ExportFormat: 4,
+		ExportSource: "swag",
+	}
+
+	environment := &insomniaEnvironment{
+		ID:       insomniaEnvironmentID,
+		Type:     "environment",
+		ParentID: insomniaWorkspaceID,
+		Name:     "Base Environment",
+		Data:     map[string]string{"base_url": insomniaBaseURL(swagger)},
+	}
+
+	export.Resources = append(export.Resources,
+		&insomniaWorkspace{
+			ID:          insomniaWorkspaceID,
+			Type:        "workspace",
+			Name:        title,
+			Description: description,
+			Scope:       "collection",
+		},
+		environment,
+	)
+
+	if swagger.Paths == nil {
+		return export
+	}
+
+	paths := make([]string, 0, len(swagger.Paths.Paths))
+	for path := range swagger.Paths.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	groups := map[string]string{}
+	for _, path := range paths {
+		item := swagger.Paths.Paths[path]
+		for _, op := range []struct {
+			method    string
+			operation *spec.Operation
+		}{
+			{http.MethodGet, item.Get},
+			{http.MethodPost, item.Post},
+			{http.MethodPut, item.Put},
+			{http.MethodPatch, item.Patch},
+			{http.MethodDelete, item.Delete},
+			{http.MethodHead, item.Head},
+			{http.MethodOptions, item.Options},
+		} {
+			if op.operation == nil {
+				continue
+			}
+
+			parentID := insomniaWorkspaceID
+			if len(op.operation.Tags) > 0 {
+				tag := op.operation.Tags[0]
+				groupID, ok := groups[tag]
+				if !ok {
+					groupID = fmt.Sprintf("fld_%d", len(groups)+1)
+					groups[tag] = groupID
+					export.Resources = append(export.Resources, &insomniaRequestGroup{
+						ID:          groupID,
+						Type:        "request_group",
+						ParentID:    insomniaWorkspaceID,
+						Name:        tag,
+						Description: insomniaTagDescription(swagger, tag),
+					})
+				}
+				parentID = groupID
+			}
+
+			request := insomniaNewRequest(swagger, op.method, path, op.operation, environment.Data)
+			request.ID = fmt.Sprintf("req_%d", len(export.Resources))
+			request.ParentID = parentID
+			export.Resources = append(export.Resources, request)
+		}
+	}
+
+	return export
+}
+
+func insomniaBaseURL(swagger *spec.Swagger) string {
+	if swagger.Host == "" {
+		return swagger.BasePath
+	}
+
+	scheme := "http"
+	if len(swagger.Schemes) > 0 && swagger.Schemes[0] != "" {
+		scheme = swagger.Schemes[0]
+	}
+	return scheme + "://" + swagger.Host + swagger.BasePath
+}
+
+func insomniaTagDescription(swagger *spec.Swagger, name string) string {
+	for _, tag := range swagger.Tags {
+		if tag.Name == name {
+			return tag.Description
+		}
+	}
+	return ""
+}
+
+func insomniaNewRequest(swagger *spec.Swagger, method, path string, operation *spec.Operation, env map[string]string) *insomniaRequest {
+	name := operation.Summary
+	if name == "" {
+		name = method + " " + path
+	}
+
+	request := &insomniaRequest{
+		Type:           "request",
+		Name:           name,
+		Description:    operation.Description,
+		Method:         method,
+		URL:            "{{ _.base_url }}" + path,
+		Parameters:     []insomniaPair{},
+		Headers:        []insomniaPair{},
+		Authentication: map[string]string{},
+	}
+
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+
+	for _, param := range operation.Parameters {
+		switch param.In {
+		case "query":
+			request.Parameters = append(request.Parameters, insomniaParam(param))
+		case "header":
+			request.Headers = append(request.Headers, insomniaParam(param))
+		case "formData":
+			request.Body.MimeType = "multipart/form-data"
+			for _, mimeType := range consumes {
+				if mimeType == "application/x-www-form-urlencoded" {
+					request.Body.MimeType = mimeType
+				}
+			}
+			request.Body.Params = append(request.Body.Params, insomniaParam(param))
+		case "body":
+			request.Body.MimeType = "application/json"
+			if len(consumes) > 0 {
+				request.Body.MimeType = consumes[0]
+			}
+			if example := insomniaExample(param.Schema, swagger.Definitions, map[string]bool{}); example != nil {
+				text, err := json.MarshalIndent(example, "", "    ")
+				if err == nil {
+					request.Body.Text = string(text)
+				}
+			}
+		}
+	}
+	if request.Body.MimeType != "" {
+		request.Headers = append(request.Headers, insomniaPair{Name: "Content-Type", Value: request.Body.MimeType})
+	}
+
+	security := operation.Security
+	if security == nil {
+		security = swagger.Security
+	}
+	if len(security) > 0 {
+		names := make([]string, 0, len(security[0]))
+		for name := range security[0] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			scheme, ok := swagger.SecurityDefinitions[name]
+			if !ok {
+				continue
+			}
+			insomniaApplySecurity(request, name, scheme, env)
+		}
+	}
+
+	return request
+}
+
+func insomniaApplySecurity(request *insomniaRequest, name string, scheme *spec.SecurityScheme, env map[string]string) {
+	varName := insomniaVarName(name)
+	switch scheme.Type {
+	case "apiKey":
+		env[varName] = ""
+		pair := insomniaPair{Name: scheme.Name, Value: insomniaVar(name)}
+		if scheme.In == "query" {
+			request.Parameters = append(request.Parameters, pair)
+		} else {
+			request.Headers = append(request.Headers, pair)
+		}
+	case "basic":
+		env[varName+"_username"] = ""
+		env[varName+"_password"] = ""
+		request.Authentication = map[string]string{
+			"type":     "basic",
+			"username": insomniaVar(name + "_username"),
+			"password": insomniaVar(name + "_password"),
+		}
+	case "oauth2":
+		env[varName+"_token"] = ""
+		request.Authentication = map[string]string{
+			"type":  "bearer",
+			"token": insomniaVar(name + "_token"),
+		}
+	}
+}
+
+func insomniaParam(param spec.Parameter) insomniaPair {
+	value := param.Default
+	if value == nil {
+		value = param.Example
+	}
+	if value == nil && len(param.Enum) > 0 {
+		value = param.Enum[0]
+	}
+
+	pair := insomniaPair{Name: param.Name, Disabled: !param.Required}
+	if value != nil {
+		pair.Value = fmt.Sprint(value)
+	}
+	return pair
+}
+
+// insomniaExample builds an example value for schema from the examples and defaults of its properties
+func insomniaExample(schema *spec.Schema, definitions spec.Definitions, seen map[string]bool) interface{} {
+	if schema == nil {
+		return nil
+	}
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+
+	if ref := schema.Ref.String(); ref != "" {
+		name := ref[strings.LastIndexByte(ref, '/')+1:]
+		definition, ok := definitions[name]
+		if !ok || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return insomniaExample(&definition, definitions, seen)
+	}
+
+	if len(schema.AllOf) > 0 {
+		result := map[string]interface{}{}
+		for i := range schema.AllOf {
+			if value, ok := insomniaExample(&schema.AllOf[i], definitions, seen).(map[string]interface{}); ok {
+				for k, v := range value {
+					result[k] = v
+				}
+			}
+		}
+		return result
+	}
+
+	if len(schema.Type) == 0 {
+		return nil
+	}
+
+	switch schema.Type[0] {
+	case "array":
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return []interface{}{}
+		}
+		return []interface{}{insomniaExample(schema.Items.Schema, definitions, seen)}
+	case "object":
+		result := map[string]interface{}{}
+		for name, property := range schema.Properties {
+			property := property
+			result[name] = insomniaExample(&property, definitions, seen)
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			result["key"] = insomniaExample(schema.AdditionalProperties.Schema, definitions, seen)
+		}
+		return result
+	case "string":
+		return "string"
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package gen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToInsomnia(t *testing.T) {
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Info: &spec.Info{
+				InfoProps: spec.InfoProps{Title: "Swagger Example API"},
+			},
+			Host:     "petstore.swagger.io",
+			BasePath: "/v2",
+			Schemes:  []string{"https"},
+			Tags: []spec.Tag{
+				{TagProps: spec.TagProps{Name: "pets", Description: "Everything about pets"}},
+			},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"ApiKeyAuth": spec.APIKeyAuth("Authorization", "header"),
+			},
+			Definitions: spec.Definitions{
+				"web.Pet": spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type: []string{"object"},
+						Properties: map[string]spec.Schema{
+							"id": {
+								SchemaProps:        spec.SchemaProps{Type: []string{"integer"}},
+								SwaggerSchemaProps: spec.SwaggerSchemaProps{Example: 1},
+							},
+							"name": *spec.StringProperty(),
+						},
+					},
+				},
+			},
+			Paths: &spec.Paths{
+				Paths: map[string]spec.PathItem{
+					"/pets": {
+						PathItemProps: spec.PathItemProps{
+							Get: &spec.Operation{
+								OperationProps: spec.OperationProps{
+									Summary:  "List pets",
+									Tags:     []string{"pets"},
+									Security: []map[string][]string{{"ApiKeyAuth": {}}},
+									Parameters: []spec.Parameter{
+										*spec.QueryParam("limit").Typed("integer", "").WithDefault(20),
+									},
+								},
+							},
+							Post: &spec.Operation{
+								OperationProps: spec.OperationProps{
+									Summary: "Add a pet",
+									Tags:    []string{"pets"},
+									Parameters: []spec.Parameter{
+										*spec.BodyParam("pet", spec.RefSchema("#/definitions/web.Pet")),
+									},
+								},
+							},
+						},
+					},
+					"/health": {
+						PathItemProps: spec.PathItemProps{
+							Get: &spec.Operation{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	export := ToInsomnia(swagger)
+	assert.Equal(t, "export", export.Type)
+	assert.Equal(t, 4, export.ExportFormat)
+	assert.Len(t, export.Resources, 6)
+
+	env := export.Resources[1].(*insomniaEnvironment)
+	assert.Equal(t, map[string]string{
+		"base_url":   "https://petstore.swagger.io/v2",
+		"ApiKeyAuth": "",
+	}, env.Data)
+
+	health := export.Resources[2].(*insomniaRequest)
+	assert.Equal(t, insomniaWorkspaceID, health.ParentID)
+	assert.Equal(t, "GET /health", health.Name)
+	assert.Equal(t, "{{ _.base_url }}/health", health.URL)
+
+	group := export.Resources[3].(*insomniaRequestGroup)
+	assert.Equal(t, "pets", group.Name)
+	assert.Equal(t, "Everything about pets", group.Description)
+
+	list := export.Resources[4].(*insomniaRequest)
+	assert.Equal(t, group.ID, list.ParentID)
+	assert.Equal(t, []insomniaPair{{Name: "limit", Value: "20", Disabled: true}}, list.Parameters)
+	assert.Equal(t, []insomniaPair{{Name: "Authorization", Value: "{{ _.ApiKeyAuth }}"}}, list.Headers)
+
+	add := export.Resources[5].(*insomniaRequest)
+	assert.Equal(t, "POST", add.Method)
+	assert.Equal(t, "application/json", add.Body.MimeType)
+	assert.JSONEq(t, `{"id":1,"name":"string"}`, add.Body.Text)
+
+	_, err := json.Marshal(export)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package gen
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	// jsonSchemaFileName is the name of the file holding the definitions as JSON Schema
+	jsonSchemaFileName = "schemas.json"
+
+	// jsonSchemaDraft07 identifies the version of JSON Schema of that file
+	jsonSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+)
+
+// ToJSONSchema converts the definitions of swagger into a JSON Schema draft-07 document holding them under $defs,
+// referenced by #/$defs/Name. x-nullable schemas also accept null and examples become a list.
+func ToJSONSchema(swagger *spec.Swagger) (map[string]interface{}, error) {
+	b, err := json.Marshal(swagger.Definitions)
+	if err != nil {
+		return nil, err
+	}
+	defs := map[string]interface{}{}
+	if err = json.Unmarshal(b, &defs); err != nil {
+		return nil, err
+	}
+	for name, schema := range defs {
+		defs[name] = toJSONSchema(schema)
+	}
+	return map[string]interface{}{
+		"$schema": jsonSchemaDraft07,
+		"$defs":   defs,
+	}, nil
+}
+
+// toJSONSchema converts a swagger schema decoded from json, along with the schemas nested in it
+func toJSONSchema(node interface{}) interface{} {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		// additionalProperties may be a boolean
+		return node
+	}
+
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		switch value := schema[key].(type) {
+		case map[string]interface{}:
+			schema[key] = toJSONSchema(value)
+		case []interface{}:
+			for i := range value {
+				value[i] = toJSONSchema(value[i])
+			}
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if schemas, ok := schema[key].([]interface{}); ok {
+			for i := range schemas {
+				schemas[i] = toJSONSchema(schemas[i])
+			}
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range properties {
+			properties[name] = toJSONSchema(property)
+		}
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		schema["$ref"] = "#/$defs/" + strings.TrimPrefix(ref, "#/definitions/")
+	}
+	if example, ok := schema["example"]; ok {
+		delete(schema, "example")
+		schema["examples"] = []interface{}{example}
+	}
+	nullable, _ := schema["x-nullable"].(bool)
+	for key := range schema {
+		if strings.HasPrefix(key, "x-") {
+			delete(schema, key)
+		}
+	}
+	if nullable {
+		return nullableSchema(schema)
+	}
+	return schema
+}
+
+// nullableSchema returns a schema accepting null besides the values of schema
+func nullableSchema(schema map[string]interface{}) interface{} {
+	switch schemaType := schema["type"].(type) {
+	case string:
+		schema["type"] = []interface{}{schemaType, "null"}
+		if enum, ok := schema["enum"].([]interface{}); ok {
+			schema["enum"] = append(enum, nil)
+		}
+		return schema
+	case nil:
+		// a $ref can't have siblings
+		return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+	}
+	return schema
+}
@@ -0,0 +1,125 @@
+package gen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToJSONSchema(t *testing.T) {
+	pet := *spec.StringProperty()
+	pet.Enum = []interface{}{"cat", "dog"}
+	pet.AddExtension("x-nullable", true)
+	pet.AddExtension("x-enum-varnames", []string{"Cat", "Dog"})
+	owner := *spec.RefSchema("#/definitions/web.User")
+	owner.AddExtension("x-nullable", true)
+	object := func() *spec.Schema {
+		return &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}}}
+	}
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Definitions: spec.Definitions{
+				"web.Pet": *object().
+					WithRequired("kind").
+					SetProperty("kind", pet).
+					SetProperty("owner", owner).
+					SetProperty("tags", *spec.ArrayProperty(spec.RefSchema("#/definitions/web.Tag"))).
+					SetProperty("x-name", *spec.StringProperty().WithExample("rex")),
+				"web.Tag":  *object().SetProperty("name", *spec.StringProperty()),
+				"web.User": *object(),
+			},
+		},
+	}
+
+	doc, err := ToJSONSchema(swagger)
+	assert.NoError(t, err)
+	b, err := json.MarshalIndent(doc, "", "    ")
+	assert.NoError(t, err)
+	expected := `{
+    "$defs": {
+        "web.Pet": {
+            "properties": {
+                "kind": {
+                    "enum": [
+                        "cat",
+                        "dog",
+                        null
+                    ],
+                    "type": [
+                        "string",
+                        "null"
+                    ]
+                },
+                "owner": {
+                    "anyOf": [
+                        {
+                            "$ref": "#/$defs/web.User"
+                        },
+                        {
+                            "type": "null"
+                        }
+                    ]
+                },
+                "tags": {
+                    "items": {
+                        "$ref": "#/$defs/web.Tag"
+                    },
+                    "type": "array"
+                },
+                "x-name": {
+                    "examples": [
+                        "rex"
+                    ],
+                    "type": "string"
+                }
+            },
+            "required": [
+                "kind"
+            ],
+            "type": "object"
+        },
+        "web.Tag": {
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            },
+            "type": "object"
+        },
+        "web.User": {
+            "type": "object"
+        }
+    },
+    "$schema": "http://json-schema.org/draft-07/schema#"
+}`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestGen_EmitJSONSchema(t *testing.T) {
+	config := &Config{
+		SearchDir:      "../testdata/simple",
+		MainAPIFile:    "./main.go",
+		OutputDir:      "../testdata/simple/docs",
+		OutputTypes:    []string{"json"},
+		EmitJSONSchema: true,
+	}
+	defer os.RemoveAll(config.OutputDir)
+	assert.NoError(t, New().Build(config))
+
+	b, err := ioutil.ReadFile(filepath.Join(config.OutputDir, "schemas.json"))
+	assert.NoError(t, err)
+	var doc struct {
+		Schema string                            `json:"$schema"`
+		Defs   map[string]map[string]interface{} `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(b, &doc))
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", doc.Schema)
+	assert.Contains(t, doc.Defs, "web.Pet")
+	assert.NotContains(t, string(b), "#/definitions/")
+	assert.Contains(t, string(b), "#/$defs/")
+}
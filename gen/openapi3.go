@@ -0,0 +1,311 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// OpenAPI3Server presents a server object of an OpenAPI 3 document.
+type OpenAPI3Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// ToOpenAPI3Servers converts the host, basePath and schemes of a Swagger 2.0 spec into the servers of an
+// OpenAPI 3 document, one server per scheme. Without a host the server is the relative url of basePath.
+// The servers of the x-servers extension, written by @server and the additional @host annotations, follow.
+func ToOpenAPI3Servers(swagger *spec.Swagger) []OpenAPI3Server {
+	basePath := swagger.BasePath
+	var servers []OpenAPI3Server
+	if swagger.Host == "" {
+		if basePath == "" {
+			basePath = "/"
+		}
+		servers = append(servers, OpenAPI3Server{URL: basePath})
+	} else {
+		servers = append(servers, hostServers(swagger, swagger.Host, "")...)
+	}
+
+	var extra []OpenAPI3Server
+	if b, err := json.Marshal(swagger.Extensions["x-servers"]); err == nil {
+		_ = json.Unmarshal(b, &extra)
+	}
+	for _, server := range extra {
+		if strings.Contains(server.URL, "://") || strings.HasPrefix(server.URL, "/") {
+			servers = append(servers, server)
+		} else {
+			servers = append(servers, hostServers(swagger, server.URL, server.Description)...)
+		}
+	}
+	return servers
+}
+
+// hostServers returns the servers of host, one per scheme of swagger
+func hostServers(swagger *spec.Swagger, host, description string) []OpenAPI3Server {
+	schemes := swagger.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	servers := make([]OpenAPI3Server, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, OpenAPI3Server{URL: scheme + "://" + host + swagger.BasePath, Description: description})
+	}
+	return servers
+}
+
+// OpenAPI3MediaType presents a media type object of an OpenAPI 3 document.
+type OpenAPI3MediaType struct {
+	Schema  *spec.Schema `json:"schema,omitempty"`
+	Example interface{}  `json:"example,omitempty"`
+}
+
+// OpenAPI3RequestBody presents a request body object of an OpenAPI 3 document.
+type OpenAPI3RequestBody struct {
+	Description string                       `json:"description,omitempty"`
+	Required    bool                         `json:"required,omitempty"`
+	Content     map[string]OpenAPI3MediaType `json:"content"`
+}
+
+// ToOpenAPI3RequestBody converts the body parameter of an operation of a Swagger 2.0 spec into the request body of
+// an OpenAPI 3 document, with an entry per content type the operation consumes, falling back on the ones of the
+// spec then on application/json. The references to definitions become references to components. It returns nil
+// when the operation has no body parameter.
+func ToOpenAPI3RequestBody(swagger *spec.Swagger, operation *spec.Operation) (*OpenAPI3RequestBody, error) {
+	for _, param := range operation.Parameters {
+		if param.In != "body" {
+			continue
+		}
+
+		schema, err := toOpenAPI3Schema(param.Schema)
+		if err != nil {
+			return nil, err
+		}
+		consumes := operation.Consumes
+		if len(consumes) == 0 {
+			consumes = swagger.Consumes
+		}
+		if len(consumes) == 0 {
+			consumes = []string{"application/json"}
+		}
+		content := make(map[string]OpenAPI3MediaType, len(consumes))
+		for _, mimeType := range consumes {
+			content[mimeType] = OpenAPI3MediaType{Schema: schema}
+		}
+		return &OpenAPI3RequestBody{
+			Description: param.Description,
+			Required:    param.Required,
+			Content:     content,
+		}, nil
+	}
+	return nil, nil
+}
+
+// OpenAPI3Parameter presents a parameter object of an OpenAPI 3 document.
+type OpenAPI3Parameter struct {
+	Name        string       `json:"name"`
+	In          string       `json:"in"`
+	Description string       `json:"description,omitempty"`
+	Required    bool         `json:"required,omitempty"`
+	Schema      *spec.Schema `json:"schema,omitempty"`
+}
+
+// ToOpenAPI3Parameters converts the path, query and header parameters of an operation of a Swagger 2.0 spec into the
+// parameters of an OpenAPI 3 document, followed by the cookie parameters of its x-cookie extension, which Swagger 2.0
+// lacks. The body and formData parameters go to the request body instead.
+func ToOpenAPI3Parameters(operation *spec.Operation) []OpenAPI3Parameter {
+	var cookies []spec.Parameter
+	if b, err := json.Marshal(operation.Extensions["x-cookie"]); err == nil {
+		_ = json.Unmarshal(b, &cookies)
+	}
+
+	var params []OpenAPI3Parameter
+	for _, param := range append(append([]spec.Parameter{}, operation.Parameters...), cookies...) {
+		switch param.In {
+		case "path", "query", "header", "cookie":
+			params = append(params, OpenAPI3Parameter{
+				Name:        param.Name,
+				In:          param.In,
+				Description: param.Description,
+				Required:    param.Required,
+				Schema:      simpleSchemaOf(&param),
+			})
+		}
+	}
+	return params
+}
+
+// simpleSchemaOf returns the schema of the type and the validations of a parameter other than a body one
+func simpleSchemaOf(param *spec.Parameter) *spec.Schema {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:             spec.StringOrArray{param.Type},
+			Format:           param.Format,
+			Default:          param.Default,
+			Enum:             param.Enum,
+			Maximum:          param.Maximum,
+			ExclusiveMaximum: param.ExclusiveMaximum,
+			Minimum:          param.Minimum,
+			ExclusiveMinimum: param.ExclusiveMinimum,
+			MaxLength:        param.MaxLength,
+			MinLength:        param.MinLength,
+			Pattern:          param.Pattern,
+			MaxItems:         param.MaxItems,
+			MinItems:         param.MinItems,
+			UniqueItems:      param.UniqueItems,
+			MultipleOf:       param.MultipleOf,
+		},
+	}
+	if param.Items != nil {
+		schema.Items = &spec.SchemaOrArray{Schema: &spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:   spec.StringOrArray{param.Items.Type},
+				Format: param.Items.Format,
+				Enum:   param.Items.Enum,
+			},
+		}}
+	}
+	return schema
+}
+
+// toOpenAPI3Schema copies schema, its references to definitions becoming references to components and the models
+// of a {oneOf} or {anyOf} response its oneOf or anyOf
+func toOpenAPI3Schema(schema *spec.Schema) (*spec.Schema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.ReplaceAll(b, []byte(`"#/definitions/`), []byte(`"#/components/schemas/`))
+	var converted spec.Schema
+	if err = json.Unmarshal(b, &converted); err != nil {
+		return nil, err
+	}
+	if err = toOpenAPI3Alternatives(&converted); err != nil {
+		return nil, err
+	}
+	return &converted, nil
+}
+
+// toOpenAPI3Alternatives turns the x-oneOf or x-anyOf extension listing the models of a {oneOf} or {anyOf} schema,
+// since Swagger 2.0 has neither, into its oneOf or anyOf. Its discriminator becomes an object naming the property.
+func toOpenAPI3Alternatives(schema *spec.Schema) error {
+	for _, extension := range []string{"x-oneOf", "x-anyOf"} {
+		value, ok := schema.Extensions[extension]
+		if !ok {
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		var schemas []spec.Schema
+		if err = json.Unmarshal(b, &schemas); err != nil {
+			return err
+		}
+		if extension == "x-oneOf" {
+			schema.OneOf = schemas
+		} else {
+			schema.AnyOf = schemas
+		}
+		delete(schema.Extensions, extension)
+		schema.Type = nil
+		if schema.Discriminator != "" {
+			if schema.ExtraProps == nil {
+				schema.ExtraProps = make(map[string]interface{})
+			}
+			schema.ExtraProps["discriminator"] = map[string]string{"propertyName": schema.Discriminator}
+			schema.Discriminator = ""
+		}
+	}
+	return nil
+}
+
+// OpenAPI3Response presents a response object of an OpenAPI 3 document.
+type OpenAPI3Response struct {
+	Description string                       `json:"description"`
+	Content     map[string]OpenAPI3MediaType `json:"content,omitempty"`
+}
+
+// ToOpenAPI3Responses converts the responses of an operation of a Swagger 2.0 spec into the responses of an
+// OpenAPI 3 document, keyed by status code or default. A response lists its schema under the mime types of its
+// x-produces extension, written by the produce(mimeType) attribute, or else the ones the operation produces, falling
+// back on the ones of the spec then on application/json. Among several mime types, the schema of a primitive type
+// goes to the text ones and the others to the rest, when there are some of each. The examples of a response go to
+// their mime type.
+func ToOpenAPI3Responses(swagger *spec.Swagger, operation *spec.Operation) (map[string]OpenAPI3Response, error) {
+	if operation.Responses == nil {
+		return nil, nil
+	}
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = swagger.Produces
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+
+	responses := make(map[string]OpenAPI3Response, len(operation.Responses.StatusCodeResponses)+1)
+	convert := func(key string, resp *spec.Response) error {
+		response := OpenAPI3Response{Description: resp.Description}
+		if resp.Schema != nil {
+			schema, err := toOpenAPI3Schema(resp.Schema)
+			if err != nil {
+				return err
+			}
+			mimeTypes := responseProduces(resp)
+			if len(mimeTypes) == 0 {
+				mimeTypes = mimeTypesOfSchema(produces, resp.Schema)
+			}
+			response.Content = make(map[string]OpenAPI3MediaType, len(mimeTypes))
+			for _, mimeType := range mimeTypes {
+				response.Content[mimeType] = OpenAPI3MediaType{Schema: schema, Example: resp.Examples[mimeType]}
+			}
+		}
+		responses[key] = response
+		return nil
+	}
+
+	if operation.Responses.Default != nil {
+		if err := convert("default", operation.Responses.Default); err != nil {
+			return nil, err
+		}
+	}
+	for code, resp := range operation.Responses.StatusCodeResponses {
+		resp := resp
+		if err := convert(strconv.Itoa(code), &resp); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+// responseProduces returns the mime types of the x-produces extension of a response
+func responseProduces(resp *spec.Response) []string {
+	var produces []string
+	if b, err := json.Marshal(resp.Extensions["x-produces"]); err == nil {
+		_ = json.Unmarshal(b, &produces)
+	}
+	return produces
+}
+
+// mimeTypesOfSchema returns the text mime types of produces for the schema of a primitive type and the other ones
+// for the rest, or all of produces when none fits
+func mimeTypesOfSchema(produces []string, schema *spec.Schema) []string {
+	primitive := schema.Ref.String() == "" && len(schema.Type) == 1 && schema.Type[0] != "object" && schema.Type[0] != "array"
+	var fitting []string
+	for _, mimeType := range produces {
+		if strings.HasPrefix(mimeType, "text/") == primitive {
+			fitting = append(fitting, mimeType)
+		}
+	}
+	if len(fitting) == 0 {
+		return produces
+	}
+	return fitting
+}
@@ -0,0 +1,263 @@
+package gen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggo/swag"
+)
+
+func TestToOpenAPI3Servers(t *testing.T) {
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Host:     "api.example.com",
+			BasePath: "/v1",
+			Schemes:  []string{"https"},
+		},
+	}
+	b, err := json.Marshal(ToOpenAPI3Servers(swagger))
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"url":"https://api.example.com/v1"}]`, string(b))
+
+	swagger.Schemes = []string{"http", "https"}
+	assert.Equal(t, []OpenAPI3Server{
+		{URL: "http://api.example.com/v1"},
+		{URL: "https://api.example.com/v1"},
+	}, ToOpenAPI3Servers(swagger))
+
+	swagger.Schemes = nil
+	assert.Equal(t, []OpenAPI3Server{{URL: "http://api.example.com/v1"}}, ToOpenAPI3Servers(swagger))
+
+	swagger.Host = ""
+	assert.Equal(t, []OpenAPI3Server{{URL: "/v1"}}, ToOpenAPI3Servers(swagger))
+
+	swagger.BasePath = ""
+	assert.Equal(t, []OpenAPI3Server{{URL: "/"}}, ToOpenAPI3Servers(swagger))
+}
+
+func TestToOpenAPI3ServersOfExtension(t *testing.T) {
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Host:     "api.example.com",
+			BasePath: "/v1",
+			Schemes:  []string{"https"},
+		},
+	}
+	swagger.AddExtension("x-servers", []interface{}{
+		map[string]interface{}{"url": "staging.example.com"},
+		map[string]interface{}{"url": "https://eu.example.com/v1", "description": "Europe"},
+	})
+	assert.Equal(t, []OpenAPI3Server{
+		{URL: "https://api.example.com/v1"},
+		{URL: "https://staging.example.com/v1"},
+		{URL: "https://eu.example.com/v1", Description: "Europe"},
+	}, ToOpenAPI3Servers(swagger))
+}
+
+func TestToOpenAPI3Parameters(t *testing.T) {
+	operation := swag.NewOperation(nil)
+	for _, comment := range []string{
+		`@Param id path int true "the id"`,
+		`@Param tags query []string false "the tags"`,
+		`@Param session cookie string true "session id"`,
+		`@Param pet body object true "the pet"`,
+	} {
+		assert.NoError(t, operation.ParseComment(comment, nil))
+	}
+
+	// Swagger 2.0 lacks cookie parameters
+	for _, param := range operation.Parameters {
+		assert.NotEqual(t, "cookie", param.In)
+	}
+
+	b, err := json.MarshalIndent(ToOpenAPI3Parameters(&operation.Operation), "", "    ")
+	assert.NoError(t, err)
+	expected := `[
+    {
+        "name": "id",
+        "in": "path",
+        "description": "the id",
+        "required": true,
+        "schema": {
+            "type": "integer"
+        }
+    },
+    {
+        "name": "tags",
+        "in": "query",
+        "description": "the tags",
+        "schema": {
+            "type": "array",
+            "items": {
+                "type": "string"
+            }
+        }
+    },
+    {
+        "name": "session",
+        "in": "cookie",
+        "description": "session id",
+        "required": true,
+        "schema": {
+            "type": "string"
+        }
+    }
+]`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestToOpenAPI3RequestBody(t *testing.T) {
+	operation := spec.NewOperation("").
+		WithConsumes("application/json", "text/xml").
+		AddParam(spec.BodyParam("user", spec.RefSchema("#/definitions/web.Pet")).WithDescription("the pet").AsRequired())
+	swagger := &spec.Swagger{}
+
+	requestBody, err := ToOpenAPI3RequestBody(swagger, operation)
+	assert.NoError(t, err)
+	b, err := json.MarshalIndent(requestBody, "", "    ")
+	assert.NoError(t, err)
+	expected := `{
+    "description": "the pet",
+    "required": true,
+    "content": {
+        "application/json": {
+            "schema": {
+                "$ref": "#/components/schemas/web.Pet"
+            }
+        },
+        "text/xml": {
+            "schema": {
+                "$ref": "#/components/schemas/web.Pet"
+            }
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+
+	operation.Consumes = nil
+	swagger.Consumes = []string{"application/xml"}
+	requestBody, err = ToOpenAPI3RequestBody(swagger, operation)
+	assert.NoError(t, err)
+	assert.Len(t, requestBody.Content, 1)
+	assert.Contains(t, requestBody.Content, "application/xml")
+
+	operation = spec.NewOperation("").AddParam(spec.QueryParam("id").Typed("integer", ""))
+	requestBody, err = ToOpenAPI3RequestBody(swagger, operation)
+	assert.NoError(t, err)
+	assert.Nil(t, requestBody)
+}
+
+func TestToOpenAPI3Responses(t *testing.T) {
+	operation := swag.NewOperation(nil)
+	for _, comment := range []string{
+		"// @Produce json,plain",
+		"// @Success 201 {array} string",
+		"// @Failure 400 {string} string",
+		`// @Failure 404 {string} string "not found" produce(json) example("missing")`,
+		"// @Failure default",
+	} {
+		assert.NoError(t, operation.ParseComment(comment, nil))
+	}
+	operation.AddResponse(200, spec.NewResponse().WithDescription("OK").WithSchema(spec.RefSchema("#/definitions/model.User")))
+
+	responses, err := ToOpenAPI3Responses(&spec.Swagger{}, &operation.Operation)
+	assert.NoError(t, err)
+	b, err := json.MarshalIndent(responses, "", "    ")
+	assert.NoError(t, err)
+	expected := `{
+    "200": {
+        "description": "OK",
+        "content": {
+            "application/json": {
+                "schema": {
+                    "$ref": "#/components/schemas/model.User"
+                }
+            }
+        }
+    },
+    "201": {
+        "description": "Created",
+        "content": {
+            "application/json": {
+                "schema": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        }
+    },
+    "400": {
+        "description": "Bad Request",
+        "content": {
+            "text/plain": {
+                "schema": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "404": {
+        "description": "not found",
+        "content": {
+            "application/json": {
+                "schema": {
+                    "type": "string"
+                },
+                "example": "missing"
+            }
+        }
+    },
+    "default": {
+        "description": ""
+    }
+}`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestToOpenAPI3ResponsesOfAlternatives(t *testing.T) {
+	p := swag.New()
+	assert.NoError(t, p.ParseAPI("../testdata/one_of", "main.go", 100))
+	swagger := p.GetSwagger()
+
+	responses, err := ToOpenAPI3Responses(swagger, swagger.Paths.Paths["/users/{id}/events/last"].Get)
+	assert.NoError(t, err)
+	b, err := json.MarshalIndent(responses, "", "    ")
+	assert.NoError(t, err)
+	expected := `{
+    "200": {
+        "description": "the event",
+        "content": {
+            "application/json": {
+                "schema": {
+                    "oneOf": [
+                        {
+                            "$ref": "#/components/schemas/main.Created"
+                        },
+                        {
+                            "$ref": "#/components/schemas/main.Renamed"
+                        },
+                        {
+                            "$ref": "#/components/schemas/main.Deleted"
+                        }
+                    ],
+                    "discriminator": {
+                        "propertyName": "type"
+                    }
+                }
+            }
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+
+	responses, err = ToOpenAPI3Responses(swagger, swagger.Paths.Paths["/users/{id}/events"].Get)
+	assert.NoError(t, err)
+	schema := responses["200"].Content["application/json"].Schema
+	assert.Len(t, schema.AnyOf, 2)
+	assert.Empty(t, schema.Type)
+	assert.Empty(t, schema.Extensions)
+}
@@ -0,0 +1,157 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// pathsPlaceholder stands in for the paths of a swagger while the rest of it is marshaled
+const pathsPlaceholder = "x-swag-ordered-paths"
+
+// orderedPathItemProps has the fields of spec.PathItemProps, operations in the conventional order of methods
+type orderedPathItemProps struct {
+	Get        *spec.Operation  `json:"get,omitempty"`
+	Post       *spec.Operation  `json:"post,omitempty"`
+	Put        *spec.Operation  `json:"put,omitempty"`
+	Patch      *spec.Operation  `json:"patch,omitempty"`
+	Delete     *spec.Operation  `json:"delete,omitempty"`
+	Head       *spec.Operation  `json:"head,omitempty"`
+	Options    *spec.Operation  `json:"options,omitempty"`
+	Parameters []spec.Parameter `json:"parameters,omitempty"`
+}
+
+// marshalIndent works like json.MarshalIndent, except that the operations of every path of a swagger
+// are emitted as GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS, and that the json is minified when both
+// prefix and indent are empty.
+func marshalIndent(data interface{}, prefix, indent string) ([]byte, error) {
+	minify := prefix == "" && indent == ""
+	swagger, ok := data.(*spec.Swagger)
+	if !ok || swagger.Paths == nil {
+		if minify {
+			return json.Marshal(data)
+		}
+		return json.MarshalIndent(data, prefix, indent)
+	}
+
+	b, err := marshalSwagger(swagger)
+	if err != nil || minify {
+		return b, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, prefix, indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalSwagger(swagger *spec.Swagger) ([]byte, error) {
+	placeholder, err := json.Marshal(map[string]bool{pathsPlaceholder: true})
+	if err != nil {
+		return nil, err
+	}
+
+	withoutPaths := *swagger
+	withoutPaths.Paths = &spec.Paths{
+		VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{pathsPlaceholder: true}},
+	}
+	b, err := json.Marshal(&withoutPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := marshalPaths(swagger.Paths)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Replace(b, placeholder, paths, 1), nil
+}
+
+// marshalPaths marshals the paths sorted by their key. Like spec.Paths does, only the keys starting with / are
+// written, the operations lacking @Router being stored under an empty path
+func marshalPaths(paths *spec.Paths) ([]byte, error) {
+	keys := make([]string, 0, len(paths.Paths))
+	for key := range paths.Paths {
+		if strings.HasPrefix(key, "/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		item, err := marshalPathItem(paths.Paths[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(item)
+	}
+	buf.WriteByte('}')
+
+	extensions, err := json.Marshal(paths.VendorExtensible)
+	if err != nil {
+		return nil, err
+	}
+	return concatJSON(extensions, buf.Bytes()), nil
+}
+
+func marshalPathItem(item spec.PathItem) ([]byte, error) {
+	ref, err := json.Marshal(item.Refable)
+	if err != nil {
+		return nil, err
+	}
+	extensions, err := json.Marshal(item.VendorExtensible)
+	if err != nil {
+		return nil, err
+	}
+	props, err := json.Marshal(orderedPathItemProps{
+		Get:        item.Get,
+		Post:       item.Post,
+		Put:        item.Put,
+		Patch:      item.Patch,
+		Delete:     item.Delete,
+		Head:       item.Head,
+		Options:    item.Options,
+		Parameters: item.Parameters,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return concatJSON(ref, extensions, props), nil
+}
+
+// concatJSON merges the keys of JSON objects into one object, skipping empty objects and nulls
+func concatJSON(objects ...[]byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, object := range objects {
+		object = bytes.TrimSpace(object)
+		if len(object) < 2 || bytes.Equal(object, []byte("null")) {
+			continue
+		}
+		inner := bytes.TrimSpace(object[1 : len(object)-1])
+		if len(inner) == 0 {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(inner)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
@@ -0,0 +1,87 @@
+package gen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalIndent(t *testing.T) {
+	operation := func(id string) *spec.Operation {
+		return &spec.Operation{OperationProps: spec.OperationProps{ID: id}}
+	}
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Swagger: "2.0",
+			Info:    &spec.Info{InfoProps: spec.InfoProps{Title: "paths"}},
+			Paths: &spec.Paths{
+				VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-paths": "ext"}},
+				Paths: map[string]spec.PathItem{
+					"/users": {
+						PathItemProps: spec.PathItemProps{
+							Delete: operation("delete"),
+							Put:    operation("put"),
+							Patch:  operation("patch"),
+							Post:   operation("post"),
+							Get:    operation("get"),
+						},
+					},
+					"/health": {
+						VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{"x-internal": true}},
+						PathItemProps: spec.PathItemProps{
+							Options: operation("options"),
+							Head:    operation("head"),
+						},
+					},
+				},
+			},
+			Definitions: spec.Definitions{},
+		},
+	}
+
+	expected := `{
+    "swagger": "2.0",
+    "info": {
+        "title": "paths"
+    },
+    "paths": {
+        "x-paths": "ext",
+        "/health": {
+            "x-internal": true,
+            "head": {
+                "operationId": "head"
+            },
+            "options": {
+                "operationId": "options"
+            }
+        },
+        "/users": {
+            "get": {
+                "operationId": "get"
+            },
+            "post": {
+                "operationId": "post"
+            },
+            "put": {
+                "operationId": "put"
+            },
+            "patch": {
+                "operationId": "patch"
+            },
+            "delete": {
+                "operationId": "delete"
+            }
+        }
+    }
+}`
+
+	b, err := marshalIndent(swagger, "", "    ")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(b))
+
+	standard, err := json.MarshalIndent(swagger, "", "    ")
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(standard), string(b))
+}
@@ -0,0 +1,73 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// definitionRefPrefix starts the $ref of the definitions of the spec itself
+const definitionRefPrefix = "#/definitions/"
+
+// validateRefs returns an error listing the $ref of swagger whose definition doesn't exist, along with the
+// operation or the definition they are found in
+func validateRefs(swagger *spec.Swagger) error {
+	b, err := json.Marshal(swagger)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err = json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	var dangling []string
+	walkRefs(doc, nil, func(ref string, keys []string) {
+		if !strings.HasPrefix(ref, definitionRefPrefix) {
+			return
+		}
+		if _, ok := swagger.Definitions[strings.TrimPrefix(ref, definitionRefPrefix)]; !ok {
+			dangling = append(dangling, fmt.Sprintf("%s in %s", ref, refLocation(keys)))
+		}
+	})
+	if len(dangling) > 0 {
+		return fmt.Errorf("missing definitions referenced by: %s", strings.Join(dangling, ", "))
+	}
+	return nil
+}
+
+// walkRefs calls fn with every $ref found in node, keys being the path of the object holding it
+func walkRefs(node interface{}, keys []string, fn func(ref string, keys []string)) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			fn(ref, keys)
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			walkRefs(value[name], append(keys[:len(keys):len(keys)], name), fn)
+		}
+	case []interface{}:
+		for i, item := range value {
+			walkRefs(item, append(keys[:len(keys):len(keys)], fmt.Sprint(i)), fn)
+		}
+	}
+}
+
+// refLocation describes where the object found at keys is, either an operation or a definition
+func refLocation(keys []string) string {
+	switch {
+	case len(keys) > 2 && keys[0] == "paths":
+		return fmt.Sprintf("operation %s %s", strings.ToUpper(keys[2]), keys[1])
+	case len(keys) > 1 && keys[0] == "definitions":
+		return "definition " + keys[1]
+	}
+	return strings.Join(keys, ".")
+}
@@ -4,6 +4,8 @@ require (
 	github.com/KyleBanks/depth v1.2.1
 	github.com/ghodss/yaml v1.0.0
 	github.com/go-openapi/spec v0.20.3
+	github.com/gofrs/uuid v4.4.0+incompatible
+	github.com/shopspring/decimal v1.4.0
 	github.com/stretchr/testify v1.7.0
 	github.com/urfave/cli/v2 v2.3.0
 	golang.org/x/tools v0.1.0
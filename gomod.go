@@ -0,0 +1,162 @@
+package swag
+
+import (
+	"bufio"
+	"go/build"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// goModule is the part of a go.mod file telling where the packages of an import path are on disk
+type goModule struct {
+	// path is the module path
+	path string
+	// dir is the absolute path of the directory holding go.mod
+	dir string
+	// replaces maps the module paths replaced by a local directory to the absolute path of that directory
+	replaces map[string]string
+}
+
+// findGoModule reads the go.mod file of dir or of its nearest parent directory, nil if there's none
+func findGoModule(dir string) (*goModule, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		modFile := filepath.Join(absDir, "go.mod")
+		if _, err := os.Stat(modFile); err == nil {
+			return parseGoMod(modFile)
+		}
+		parent := filepath.Dir(absDir)
+		if parent == absDir {
+			return nil, nil
+		}
+		absDir = parent
+	}
+}
+
+// parseGoMod reads the module path and the replace directives pointing to a local directory of a go.mod file
+func parseGoMod(modFile string) (*goModule, error) {
+	f, err := os.Open(modFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readGoMod(f, filepath.Dir(modFile))
+}
+
+// readGoMod reads the module path and the replace directives pointing to a local directory of the go.mod file in
+// dir, from r
+func readGoMod(r io.Reader, dir string) (*goModule, error) {
+	mod := &goModule{dir: dir, replaces: make(map[string]string)}
+	inReplaceBlock := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inReplaceBlock && fields[0] == ")":
+			inReplaceBlock = false
+		case inReplaceBlock:
+			mod.addReplace(fields)
+		case fields[0] == "module" && len(fields) > 1:
+			mod.path = strings.Trim(fields[1], `"`)
+		case fields[0] == "replace" && len(fields) > 1 && fields[1] == "(":
+			inReplaceBlock = true
+		case fields[0] == "replace":
+			mod.addReplace(fields[1:])
+		}
+	}
+	return mod, scanner.Err()
+}
+
+// addReplace records a replace directive like `example.com/foo [v1.0.0] => ./foo`, when it points to a local
+// directory
+func (mod *goModule) addReplace(fields []string) {
+	arrow := -1
+	for i, field := range fields {
+		if field == "=>" {
+			arrow = i
+			break
+		}
+	}
+	if arrow < 1 || arrow+1 >= len(fields) {
+		return
+	}
+	target := strings.Trim(fields[arrow+1], `"`)
+	if !strings.HasPrefix(target, "./") && !strings.HasPrefix(target, "../") && !filepath.IsAbs(target) {
+		return
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(mod.dir, target)
+	}
+	mod.replaces[strings.Trim(fields[0], `"`)] = filepath.Clean(target)
+}
+
+// dirOf returns the directory of the package importPath when it belongs to the module or to a module it replaces by
+// a local directory
+func (mod *goModule) dirOf(importPath string) (string, bool) {
+	var bestPath, bestDir string
+	for path, dir := range mod.replaces {
+		if len(path) > len(bestPath) && (importPath == path || strings.HasPrefix(importPath, path+"/")) {
+			bestPath, bestDir = path, dir
+		}
+	}
+	if bestPath == "" && mod.path != "" && (importPath == mod.path || strings.HasPrefix(importPath, mod.path+"/")) {
+		bestPath, bestDir = mod.path, mod.dir
+	}
+	if bestPath == "" {
+		return "", false
+	}
+	return filepath.Join(bestDir, filepath.FromSlash(strings.TrimPrefix(importPath, bestPath))), true
+}
+
+// replacedImportPath returns the import path of the package in dir when it belongs to a module replaced by a local
+// directory
+func (mod *goModule) replacedImportPath(dir string) (string, bool) {
+	var bestPath, bestDir string
+	for path, replaceDir := range mod.replaces {
+		if len(replaceDir) > len(bestDir) && (dir == replaceDir || strings.HasPrefix(dir, replaceDir+string(filepath.Separator))) {
+			bestPath, bestDir = path, replaceDir
+		}
+	}
+	if bestDir == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(bestDir, dir)
+	if err != nil {
+		return "", false
+	}
+	if rel == "." {
+		return bestPath, true
+	}
+	return bestPath + "/" + filepath.ToSlash(rel), true
+}
+
+// moduleImporter imports the packages of a module and of the modules it replaces by a local directory from their
+// directory, the other packages being imported by go/build
+type moduleImporter struct {
+	mod *goModule
+}
+
+// Import implements depth.Importer
+func (importer *moduleImporter) Import(name, srcDir string, mode build.ImportMode) (*build.Package, error) {
+	dir, ok := importer.mod.dirOf(name)
+	if !ok {
+		return build.Default.Import(name, srcDir, mode)
+	}
+	pkg, err := build.Default.ImportDir(dir, mode)
+	if err != nil {
+		return nil, err
+	}
+	pkg.ImportPath = name
+	return pkg, nil
+}
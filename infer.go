@@ -0,0 +1,186 @@
+package swag
+
+import (
+	"go/ast"
+	"go/token"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// ginPackage is the import path of Gin, whose Context.JSON calls are read by InferResponses
+const ginPackage = "github.com/gin-gonic/gin"
+
+// successStatusCodes maps the names of the 2xx status codes of net/http to their value
+var successStatusCodes = map[string]int{
+	"StatusOK":                   http.StatusOK,
+	"StatusCreated":              http.StatusCreated,
+	"StatusAccepted":             http.StatusAccepted,
+	"StatusNonAuthoritativeInfo": http.StatusNonAuthoritativeInfo,
+	"StatusNoContent":            http.StatusNoContent,
+	"StatusResetContent":         http.StatusResetContent,
+	"StatusPartialContent":       http.StatusPartialContent,
+	"StatusMultiStatus":          http.StatusMultiStatus,
+	"StatusAlreadyReported":      http.StatusAlreadyReported,
+	"StatusIMUsed":               http.StatusIMUsed,
+}
+
+// inferResponses adds to operation the 2xx responses written by the c.JSON(code, obj) calls of the Gin handler
+// funcDecl, the type of obj being found from a composite literal or the declaration of a local variable. Nothing is
+// inferred when a 2xx response is annotated.
+func (parser *Parser) inferResponses(operation *Operation, funcDecl *ast.FuncDecl, file *ast.File) {
+	if funcDecl.Body == nil || hasSuccessResponse(operation) {
+		return
+	}
+	ginName := importName(file, ginPackage)
+	if ginName == "" {
+		return
+	}
+	contexts := map[string]bool{}
+	for _, field := range funcDecl.Type.Params.List {
+		if star, ok := field.Type.(*ast.StarExpr); ok && isSelector(star.X, ginName, "Context") {
+			for _, name := range field.Names {
+				contexts[name.Name] = true
+			}
+		}
+	}
+	httpName := importName(file, "net/http")
+
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != "JSON" {
+			return true
+		}
+		if receiver, ok := selector.X.(*ast.Ident); !ok || !contexts[receiver.Name] {
+			return true
+		}
+		code, ok := statusCode(call.Args[0], httpName)
+		if !ok || code < http.StatusOK || code >= http.StatusMultipleChoices {
+			return true
+		}
+		if operation.Responses != nil {
+			if _, ok := operation.Responses.StatusCodeResponses[code]; ok {
+				// the first call writing the code wins
+				return true
+			}
+		}
+		schema, ok := parser.inferSchema(call.Args[1], file, ginName)
+		if !ok {
+			debugf(parser.debug, "Can't infer the %d response of %s", code, funcDecl.Name.Name)
+			return true
+		}
+		operation.AddResponse(code, spec.NewResponse().WithDescription(http.StatusText(code)).WithSchema(schema))
+		return true
+	})
+}
+
+// hasSuccessResponse tells whether a 2xx response of operation is annotated
+func hasSuccessResponse(operation *Operation) bool {
+	if operation.Responses == nil {
+		return false
+	}
+	for code := range operation.Responses.StatusCodeResponses {
+		if code >= http.StatusOK && code < http.StatusMultipleChoices {
+			return true
+		}
+	}
+	return false
+}
+
+// inferSchema returns the schema of the type of expr, either a composite literal, its address or a local variable
+// declared with its type or a value of which the type can be inferred
+func (parser *Parser) inferSchema(expr ast.Expr, file *ast.File, ginName string) (*spec.Schema, bool) {
+	switch expr := expr.(type) {
+	case *ast.UnaryExpr:
+		if expr.Op == token.AND {
+			return parser.inferSchema(expr.X, file, ginName)
+		}
+	case *ast.CompositeLit:
+		if expr.Type == nil {
+			return nil, false
+		}
+		if isSelector(expr.Type, ginName, "H") {
+			return &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{OBJECT}}}, true
+		}
+		schema, err := parser.parseTypeExpr(file, expr.Type, true)
+		if err != nil {
+			debugf(parser.debug, "Can't infer the type of a response: %s", err)
+			return nil, false
+		}
+		return schema, true
+	case *ast.Ident:
+		if expr.Obj == nil {
+			return nil, false
+		}
+		switch decl := expr.Obj.Decl.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range decl.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == expr.Name && len(decl.Rhs) == len(decl.Lhs) {
+					return parser.inferSchema(decl.Rhs[i], file, ginName)
+				}
+			}
+		case *ast.ValueSpec:
+			if decl.Type != nil {
+				schema, err := parser.parseTypeExpr(file, decl.Type, true)
+				if err != nil {
+					debugf(parser.debug, "Can't infer the type of a response: %s", err)
+					return nil, false
+				}
+				return schema, true
+			}
+			for i, name := range decl.Names {
+				if name.Name == expr.Name && i < len(decl.Values) {
+					return parser.inferSchema(decl.Values[i], file, ginName)
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// statusCode returns the value of an int literal or of a 2xx status code constant of net/http
+func statusCode(expr ast.Expr, httpName string) (int, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind == token.INT {
+			code, err := strconv.Atoi(expr.Value)
+			return code, err == nil
+		}
+	case *ast.SelectorExpr:
+		if x, ok := expr.X.(*ast.Ident); ok && httpName != "" && x.Name == httpName {
+			code, ok := successStatusCodes[expr.Sel.Name]
+			return code, ok
+		}
+	}
+	return 0, false
+}
+
+// importName returns the name under which file imports the package path, empty if it's not imported
+func importName(file *ast.File, path string) string {
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
+
+// isSelector tells whether expr is the selector x.sel
+func isSelector(expr ast.Expr, x, sel string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != sel {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	return ok && ident.Name == x
+}
@@ -0,0 +1,133 @@
+package swag
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// inlineSingleUseDefinitions replaces the references of the definitions used once by their schema
+func (parser *Parser) inlineSingleUseDefinitions() {
+	for _, name := range InlineSingleUseDefinitions(parser.swagger) {
+		debugf(parser.debug, "Inlining definition %s referenced once", name)
+	}
+}
+
+// InlineSingleUseDefinitions replaces the $ref of the definitions of swagger referenced exactly once, among its
+// operations and its definitions, by their schema, removes them and returns their names. Only the definitions used
+// by the operations are inlined, except the ones referencing themselves, directly or through other definitions.
+func InlineSingleUseDefinitions(swagger *spec.Swagger) []string {
+	counts := make(map[string]int)
+	rangeSchemaRoots(swagger, func(schema *spec.Schema) {
+		for _, ref := range schemaRefs(schema) {
+			if name, ok := definitionRefName(ref); ok {
+				counts[name]++
+			}
+		}
+	})
+
+	used := usedDefinitions(swagger)
+	inlined := make(map[string]spec.Schema)
+	for name, count := range counts {
+		if definition, ok := swagger.Definitions[name]; ok && count == 1 && used[name] && !isRecursiveDefinition(swagger, name) {
+			inlined[name] = definition
+		}
+	}
+	if len(inlined) == 0 {
+		return nil
+	}
+
+	// walkSchema goes on into the schema replacing a $ref, inlining the definitions nested in it at once
+	rangeSchemaRoots(swagger, func(schema *spec.Schema) {
+		_ = walkSchema(schema, func(schema *spec.Schema) error {
+			if name, ok := definitionRefName(schema.Ref); ok {
+				if definition, ok := inlined[name]; ok {
+					*schema = definition
+				}
+			}
+			return nil
+		})
+	})
+
+	names := make([]string, 0, len(inlined))
+	for name := range inlined {
+		names = append(names, name)
+		delete(swagger.Definitions, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rangeSchemaRoots calls fn with the schemas of the definitions and of the parameters and responses of the
+// operations of swagger, the changes made by fn are kept
+func rangeSchemaRoots(swagger *spec.Swagger, fn func(schema *spec.Schema)) {
+	for name, definition := range swagger.Definitions {
+		fn(&definition)
+		swagger.Definitions[name] = definition
+	}
+
+	if swagger.Paths == nil {
+		return
+	}
+	for path, itm := range swagger.Paths.Paths {
+		if !strings.HasPrefix(path, "/") {
+			continue
+		}
+		for _, operation := range []*spec.Operation{itm.Get, itm.Put, itm.Post, itm.Delete, itm.Options, itm.Head, itm.Patch} {
+			if operation == nil {
+				continue
+			}
+			for _, param := range operation.Parameters {
+				if param.Schema != nil {
+					fn(param.Schema)
+				}
+			}
+			if operation.Responses == nil {
+				continue
+			}
+			if operation.Responses.Default != nil && operation.Responses.Default.Schema != nil {
+				fn(operation.Responses.Default.Schema)
+			}
+			for _, response := range operation.Responses.StatusCodeResponses {
+				if response.Schema != nil {
+					fn(response.Schema)
+				}
+			}
+		}
+	}
+}
+
+// isRecursiveDefinition tells whether the definition name references itself, directly or through other definitions
+func isRecursiveDefinition(swagger *spec.Swagger, name string) bool {
+	seen := make(map[string]bool)
+	pending := []string{name}
+	for len(pending) > 0 {
+		definition := swagger.Definitions[pending[len(pending)-1]]
+		pending = pending[:len(pending)-1]
+		for _, ref := range schemaRefs(&definition) {
+			refName, ok := definitionRefName(ref)
+			if !ok {
+				continue
+			}
+			if refName == name {
+				return true
+			}
+			if !seen[refName] {
+				seen[refName] = true
+				pending = append(pending, refName)
+			}
+		}
+	}
+	return false
+}
+
+// definitionRefName returns the name of the definition of the spec itself referenced by ref, ok is false when ref
+// doesn't reference one
+func definitionRefName(ref spec.Ref) (name string, ok bool) {
+	u := ref.GetURL()
+	if u == nil || u.Path != "" || !strings.HasPrefix(u.Fragment, "/definitions/") {
+		return "", false
+	}
+	return strings.TrimPrefix(u.Fragment, "/definitions/"), true
+}
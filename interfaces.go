@@ -0,0 +1,87 @@
+package swag
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// interfaceSchema returns the schema of an interface type used in file, an empty schema accepting any value unless
+// ResolveInterfaces is set and types of the scanned packages implement it, then documented as one of them
+func (parser *Parser) interfaceSchema(file *ast.File, iface *ast.InterfaceType) (*spec.Schema, error) {
+	if !parser.ResolveInterfaces {
+		return &spec.Schema{}, nil
+	}
+
+	methods, ok := parser.interfaceMethods(file, iface)
+	if !ok || len(methods) == 0 {
+		return &spec.Schema{}, nil
+	}
+	implementers := parser.packages.findImplementers(methods)
+	if len(implementers) == 0 {
+		warnf(parser.debug, "no type of the scanned packages implements the interface with the methods %s, it is documented as any value",
+			strings.Join(methods, ", "))
+		return &spec.Schema{}, nil
+	}
+
+	schemas := make([]spec.Schema, 0, len(implementers))
+	for _, typeSpecDef := range implementers {
+		schema, err := parser.getTypeSchema(typeSpecDef.Name(), typeSpecDef.File, true)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, *schema)
+	}
+	schema := PrimitiveSchema(OBJECT)
+	// the extension is set directly since AddExtension lowercases its name
+	schema.Extensions = spec.Extensions{alternativesExtensions["oneOf"]: schemas}
+	return schema, nil
+}
+
+// interfaceMethods returns the sorted names of the methods of an interface type used in file, including the ones
+// of the interfaces it embeds, false if one of these can't be found
+func (parser *Parser) interfaceMethods(file *ast.File, iface *ast.InterfaceType) ([]string, bool) {
+	var methods []string
+	for _, field := range iface.Methods.List {
+		if len(field.Names) > 0 {
+			for _, name := range field.Names {
+				methods = append(methods, name.Name)
+			}
+			continue
+		}
+
+		typeName, err := getFieldType(field.Type)
+		if err != nil {
+			debugf(parser.debug, "Skipping the implementers of an interface embedding %T", field.Type)
+			return nil, false
+		}
+		if typeName == "error" {
+			methods = append(methods, "Error")
+			continue
+		}
+		typeSpecDef := parser.packages.FindTypeSpec(typeName, file)
+		if typeSpecDef == nil {
+			debugf(parser.debug, "Skipping the implementers of an interface embedding %s, its definition isn't found", typeName)
+			return nil, false
+		}
+		embedded, ok := typeSpecDef.TypeSpec.Type.(*ast.InterfaceType)
+		if !ok {
+			return nil, false
+		}
+		embeddedMethods, ok := parser.interfaceMethods(typeSpecDef.File, embedded)
+		if !ok {
+			return nil, false
+		}
+		methods = append(methods, embeddedMethods...)
+	}
+	sort.Strings(methods)
+	return methods, true
+}
+
+// isAnySchema tells whether schema is the empty schema accepting any value
+func isAnySchema(schema *spec.Schema) bool {
+	return schema.Ref.String() == "" && len(schema.Type) == 0 && len(schema.Properties) == 0 && schema.Items == nil &&
+		len(schema.Extensions) == 0
+}
@@ -6,11 +6,14 @@ import (
 	"go/ast"
 	goparser "go/parser"
 	"go/token"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -27,24 +30,65 @@ type Operation struct {
 
 	parser              *Parser
 	codeExampleFilesDir string
+
+	// inDescription tells that the lines without annotation being parsed continue a @Description
+	inDescription bool
+	// descriptionBlankLines counts the empty lines of a @Description block not added yet
+	descriptionBlankLines int
+	// annotationsFrom stores the functions whose annotations are being merged by @annotationsFrom
+	annotationsFrom []*ast.FuncDecl
+	// extension holds a x- annotation whose json value continues on the following lines
+	extension *pendingExtension
+	// codeSample holds a @x-codeSample annotation whose source is being read from the following lines
+	codeSample *pendingCodeSample
+	// hidden tells that @Hidden leaves the operation out of the spec unless IncludeHidden is set
+	hidden bool
+	// queryHints holds the keys of the query string following the path of @Router, like q for /search?q
+	queryHints []string
+	// responseHeaders holds the @Header annotations, checked against the responses once they're all parsed
+	responseHeaders []responseHeader
+}
+
+// responseHeader is a @Header annotation, codes being like 200,default or all
+type responseHeader struct {
+	codes       string
+	headers     map[string]spec.Header
+	commentLine string
+}
+
+// pendingExtension is a x- annotation whose json value is not complete yet
+type pendingExtension struct {
+	attribute string
+	value     string
+}
+
+// pendingCodeSample is a @x-codeSample annotation whose source is not complete yet
+type pendingCodeSample struct {
+	lang  string
+	lines []string
+	// fenced tells that the source is a fenced block, ending with ```, instead of the lines up to the next annotation
+	fenced bool
 }
 
 var mimeTypeAliases = map[string]string{
 	"json":                  "application/json",
 	"xml":                   "text/xml",
 	"plain":                 "text/plain",
+	"csv":                   "text/csv",
 	"html":                  "text/html",
 	"mpfd":                  "multipart/form-data",
 	"x-www-form-urlencoded": "application/x-www-form-urlencoded",
 	"json-api":              "application/vnd.api+json",
 	"json-stream":           "application/x-json-stream",
+	"event-stream":          "text/event-stream",
 	"octet-stream":          "application/octet-stream",
 	"png":                   "image/png",
 	"jpeg":                  "image/jpeg",
 	"gif":                   "image/gif",
 }
 
-var mimeTypePattern = regexp.MustCompile("^[^/]+/[^/]+$")
+// mimeTypePattern matches a type/subtype mime type, optionally followed by ;param=value parameters
+var mimeTypePattern = regexp.MustCompile(`^[^/\s;]+/[^/\s;]+(\s*;\s*[^/\s;=]+=[^/\s;]+)*$`)
 
 // NewOperation creates a new Operation with default properties.
 // map[int]Response
@@ -81,17 +125,42 @@ func SetCodeExampleFilesDirectory(directoryPath string) func(*Operation) {
 // ParseComment parses comment for given comment string and returns error if error occurs.
 func (operation *Operation) ParseComment(comment string, astFile *ast.File) error {
 	commentLine := strings.TrimSpace(strings.TrimLeft(comment, "//"))
+	if operation.codeSample != nil && operation.parseCodeSampleLine(comment, commentLine) {
+		return nil
+	}
+	if operation.extension != nil {
+		if strings.HasPrefix(commentLine, "@") {
+			return fmt.Errorf("annotation %s need a valid json value", operation.extension.attribute)
+		}
+		return operation.parseExtensionContinuation(commentLine)
+	}
 	if len(commentLine) == 0 {
+		if operation.inDescription {
+			operation.descriptionBlankLines++
+		}
+		return nil
+	}
+	if operation.inDescription && !strings.HasPrefix(commentLine, "@") {
+		operation.parseDescriptionContinuation(comment)
 		return nil
 	}
+	operation.inDescription = false
+	operation.descriptionBlankLines = 0
+
 	attribute := strings.Fields(commentLine)[0]
 	lineRemainder := strings.TrimSpace(commentLine[len(attribute):])
 	lowerAttribute := strings.ToLower(attribute)
 
+	if matches := localizedAttributePattern.FindStringSubmatch(lowerAttribute); matches != nil {
+		operation.parseLocalizedComment(matches[1], matches[2], lineRemainder)
+		return nil
+	}
+
 	var err error
 	switch lowerAttribute {
 	case "@description":
 		operation.ParseDescriptionComment(lineRemainder)
+		operation.inDescription = true
 	case "@description.markdown":
 		commentInfo, err := getMarkdownForTag(lineRemainder, operation.parser.markdownFileDir)
 		if err != nil {
@@ -116,12 +185,23 @@ func (operation *Operation) ParseComment(comment string, astFile *ast.File) erro
 		err = operation.ParseResponseHeaderComment(lineRemainder, astFile)
 	case "@router":
 		err = operation.ParseRouterComment(lineRemainder)
+	case "@annotationsfrom":
+		err = operation.ParseAnnotationsFromComment(lineRemainder, astFile)
 	case "@security":
 		err = operation.ParseSecurityComment(lineRemainder)
 	case "@deprecated":
 		operation.Deprecate()
+	case "@hidden":
+		operation.hidden = true
 	case "@x-codesamples":
 		err = operation.ParseCodeSample(attribute, commentLine, lineRemainder)
+	case "@x-codesample":
+		if lineRemainder == "" {
+			return fmt.Errorf("annotation %s need a language", attribute)
+		}
+		operation.codeSample = &pendingCodeSample{lang: lineRemainder}
+	case "@x-codesample.file":
+		err = operation.parseCodeSampleFile(attribute, lineRemainder)
 	default:
 		err = operation.ParseMetadata(attribute, lowerAttribute, lineRemainder)
 	}
@@ -150,6 +230,95 @@ func (operation *Operation) ParseCodeSample(attribute, commentLine, lineRemainde
 	return operation.ParseMetadata(attribute, strings.ToLower(attribute), lineRemainder)
 }
 
+// parseCodeSampleLine adds a line to the source of the pending @x-codeSample, it returns false when the line ends
+// the source and must be parsed as an annotation
+func (operation *Operation) parseCodeSampleLine(comment, commentLine string) bool {
+	sample := operation.codeSample
+	switch {
+	case strings.HasPrefix(commentLine, "```") && !sample.fenced && len(sample.lines) == 0:
+		sample.fenced = true
+		return true
+	case strings.HasPrefix(commentLine, "```") && sample.fenced:
+		operation.addCodeSample()
+		return true
+	case strings.HasPrefix(commentLine, "@") && !sample.fenced:
+		operation.addCodeSample()
+		return false
+	}
+	sample.lines = append(sample.lines, strings.TrimRight(strings.TrimPrefix(strings.TrimPrefix(comment, "//"), " "), " \t"))
+	return true
+}
+
+// parseCodeSampleFile adds a code sample read from a file of the code example files directory, named like
+// `lang name` for the file name.lang, or `name.lang` the language being then the extension
+func (operation *Operation) parseCodeSampleFile(attribute, lineRemainder string) error {
+	var lang, fileName string
+	switch fields := strings.Fields(lineRemainder); len(fields) {
+	case 1:
+		fileName = fields[0]
+		lang = strings.TrimPrefix(filepath.Ext(fileName), ".")
+		if lang == "" {
+			return fmt.Errorf("annotation %s need a language, before the file name or as its extension", attribute)
+		}
+	case 2:
+		lang, fileName = fields[0], fields[1]
+		if filepath.Ext(fileName) == "" {
+			fileName += "." + lang
+		}
+	default:
+		return fmt.Errorf("annotation %s need a file name, optionally preceded by a language", attribute)
+	}
+
+	path := filepath.Join(operation.codeExampleFilesDir, fileName)
+	var source []byte
+	var err error
+	if operation.parser != nil {
+		source, err = operation.parser.readFile(path)
+	} else {
+		source, err = ioutil.ReadFile(path)
+	}
+	if os.IsNotExist(err) {
+		return fmt.Errorf("cannot find the code sample file %s", path)
+	} else if err != nil {
+		return fmt.Errorf("failed to read the code sample file %s: %s", path, err)
+	}
+
+	lines := strings.Split(string(source), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	operation.codeSample = &pendingCodeSample{lang: lang, lines: lines}
+	operation.addCodeSample()
+	return nil
+}
+
+// addCodeSample adds the pending @x-codeSample to the x-codeSamples extension, without the indentation its lines
+// have in common
+func (operation *Operation) addCodeSample() {
+	sample := operation.codeSample
+	operation.codeSample = nil
+
+	indent := -1
+	for _, line := range sample.lines {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" && (indent == -1 || len(line)-len(trimmed) < indent) {
+			indent = len(line) - len(trimmed)
+		}
+	}
+	lines := make([]string, 0, len(sample.lines))
+	for _, line := range sample.lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		lines = append(lines, line)
+	}
+
+	samples, _ := operation.Extensions["x-codeSamples"].([]interface{})
+	operation.Extensions["x-codeSamples"] = append(samples, map[string]interface{}{
+		"lang":   sample.lang,
+		"source": strings.Trim(strings.Join(lines, "\n"), "\n"),
+	})
+}
+
 // ParseDescriptionComment godoc
 func (operation *Operation) ParseDescriptionComment(lineRemainder string) {
 	if operation.Description == "" {
@@ -159,6 +328,32 @@ func (operation *Operation) ParseDescriptionComment(lineRemainder string) {
 	operation.Description += "\n" + lineRemainder
 }
 
+// parseDescriptionContinuation appends a line following a @Description to the description, keeping its
+// indentation and the blank lines before it, so that markdown like code blocks and lists survives
+func (operation *Operation) parseDescriptionContinuation(comment string) {
+	line := strings.TrimRight(strings.TrimPrefix(strings.TrimPrefix(comment, "//"), " "), " \t")
+	if operation.Description == "" {
+		operation.Description = line
+	} else {
+		operation.Description += strings.Repeat("\n", operation.descriptionBlankLines+1) + line
+	}
+	operation.descriptionBlankLines = 0
+}
+
+// localizedAttributePattern matches the @Summary and @Description suffixed by a locale, like @Summary[ja] or
+// @Description[pt-BR]
+var localizedAttributePattern = regexp.MustCompile(`^@(summary|description)\[([a-z]{2,3}(?:[-_][a-z0-9]+)*)]$`)
+
+// parseLocalizedComment sets the x-summary-<locale> or x-description-<locale> extension of a localized @Summary or
+// @Description, the lines of a localized description being joined like the ones of @Description
+func (operation *Operation) parseLocalizedComment(field, locale, lineRemainder string) {
+	name := "x-" + field + "-" + locale
+	if previous, ok := operation.Extensions[name].(string); ok && field == "description" {
+		lineRemainder = previous + "\n" + lineRemainder
+	}
+	operation.AddExtension(name, lineRemainder)
+}
+
 // ParseMetadata godoc
 func (operation *Operation) ParseMetadata(attribute, lowerAttribute, lineRemainder string) error {
 	// parsing specific meta data extensions
@@ -169,6 +364,11 @@ func (operation *Operation) ParseMetadata(attribute, lowerAttribute, lineRemaind
 
 		var valueJSON interface{}
 		if err := json.Unmarshal([]byte(lineRemainder), &valueJSON); err != nil {
+			if isIncompleteJSON(lineRemainder) {
+				// the json value continues on the following lines
+				operation.extension = &pendingExtension{attribute: attribute, value: lineRemainder}
+				return nil
+			}
 			return fmt.Errorf("annotation %s need a valid json value", attribute)
 		}
 
@@ -177,6 +377,44 @@ func (operation *Operation) ParseMetadata(attribute, lowerAttribute, lineRemaind
 	return nil
 }
 
+// parseExtensionContinuation appends a line to the json value of a x- annotation, which is added to the
+// extensions as soon as it is complete
+func (operation *Operation) parseExtensionContinuation(commentLine string) error {
+	operation.extension.value += "\n" + commentLine
+
+	var valueJSON interface{}
+	if err := json.Unmarshal([]byte(operation.extension.value), &valueJSON); err != nil {
+		if isIncompleteJSON(operation.extension.value) {
+			return nil
+		}
+		return fmt.Errorf("annotation %s need a valid json value", operation.extension.attribute)
+	}
+	operation.Extensions[operation.extension.attribute[1:]] = valueJSON
+	operation.extension = nil
+	return nil
+}
+
+// isIncompleteJSON tells whether value is the valid beginning of a json value that ends early
+func isIncompleteJSON(value string) bool {
+	var valueJSON interface{}
+	return json.NewDecoder(strings.NewReader(value)).Decode(&valueJSON) == io.ErrUnexpectedEOF
+}
+
+// checkPendingExtension returns an error if the json value of a x- annotation is not complete at the end
+// of the comments of the operation
+func (operation *Operation) checkPendingExtension() error {
+	if operation.extension != nil {
+		return fmt.Errorf("annotation %s need a valid json value", operation.extension.attribute)
+	}
+	if operation.codeSample != nil {
+		if operation.codeSample.fenced {
+			return fmt.Errorf("code sample %s need a closing ```", operation.codeSample.lang)
+		}
+		operation.addCodeSample()
+	}
+	return nil
+}
+
 var paramPattern = regexp.MustCompile(`(\S+)[\s]+([\w]+)[\s]+([\S.]+)[\s]+([\w]+)[\s]+"([^"]+)"`)
 
 // ParseParamComment parses params return []string of param properties
@@ -203,14 +441,29 @@ func (operation *Operation) ParseParamComment(commentLine string, astFile *ast.F
 		objectType = PRIMITIVE
 	}
 
+	// a named type over a primitive type, like type UserID int64, is documented as that primitive
+	var format string
+	if objectType != PRIMITIVE && !IsPrimitiveType(refType) && paramType != "body" && operation.parser != nil {
+		if schema, err := operation.parser.getTypeSchema(refType, astFile, false); err == nil &&
+			len(schema.Type) == 1 && IsSimplePrimitiveType(schema.Type[0]) {
+			refType, format = schema.Type[0], schema.Format
+			if objectType == OBJECT {
+				objectType = PRIMITIVE
+			}
+		}
+	}
+
 	requiredText := strings.ToLower(matches[4])
 	required := requiredText == "true" || requiredText == "required"
 	description := matches[5]
 
 	param := createParameter(paramType, description, name, refType, required)
+	if objectType == PRIMITIVE {
+		param.Format = format
+	}
 
 	switch paramType {
-	case "path", "header":
+	case "path", "header", "cookie":
 		switch objectType {
 		case ARRAY, OBJECT:
 			return fmt.Errorf("%s is not supported type for %s", refType, paramType)
@@ -227,71 +480,16 @@ func (operation *Operation) ParseParamComment(commentLine string, astFile *ast.F
 			}
 			param.SimpleSchema.Items = &spec.Items{
 				SimpleSchema: spec.SimpleSchema{
-					Type: refType,
+					Type:   refType,
+					Format: format,
 				},
 			}
 		case OBJECT:
-			schema, err := operation.parser.getTypeSchema(refType, astFile, false)
+			params, err := operation.parseObjectParams(paramType, "", refType, astFile, nil)
 			if err != nil {
 				return err
 			}
-			if len(schema.Properties) == 0 {
-				return nil
-			}
-			find := func(arr []string, target string) bool {
-				for _, str := range arr {
-					if str == target {
-						return true
-					}
-				}
-				return false
-			}
-			items := schema.Properties.ToOrderedSchemaItems()
-			for _, item := range items {
-				name := item.Name
-				prop := item.Schema
-				if len(prop.Type) == 0 {
-					continue
-				}
-				if prop.Type[0] == ARRAY &&
-					prop.Items.Schema != nil &&
-					len(prop.Items.Schema.Type) > 0 &&
-					IsSimplePrimitiveType(prop.Items.Schema.Type[0]) {
-					param = createParameter(paramType, prop.Description, name, prop.Type[0], find(schema.Required, name))
-					param.SimpleSchema.Type = prop.Type[0]
-					if operation.parser != nil && operation.parser.collectionFormatInQuery != "" && param.CollectionFormat == "" {
-						param.CollectionFormat = TransToValidCollectionFormat(operation.parser.collectionFormatInQuery)
-					}
-					param.SimpleSchema.Items = &spec.Items{
-						SimpleSchema: spec.SimpleSchema{
-							Type: prop.Items.Schema.Type[0],
-						},
-					}
-				} else if IsSimplePrimitiveType(prop.Type[0]) {
-					param = createParameter(paramType, prop.Description, name, prop.Type[0], find(schema.Required, name))
-				} else {
-					Println(fmt.Sprintf("skip field [%s] in %s is not supported type for %s", name, refType, paramType))
-					continue
-				}
-				param.Nullable = prop.Nullable
-				param.Format = prop.Format
-				param.Default = prop.Default
-				param.Example = prop.Example
-				param.Extensions = prop.Extensions
-				param.CommonValidations.Maximum = prop.Maximum
-				param.CommonValidations.Minimum = prop.Minimum
-				param.CommonValidations.ExclusiveMaximum = prop.ExclusiveMaximum
-				param.CommonValidations.ExclusiveMinimum = prop.ExclusiveMinimum
-				param.CommonValidations.MaxLength = prop.MaxLength
-				param.CommonValidations.MinLength = prop.MinLength
-				param.CommonValidations.Pattern = prop.Pattern
-				param.CommonValidations.MaxItems = prop.MaxItems
-				param.CommonValidations.MinItems = prop.MinItems
-				param.CommonValidations.UniqueItems = prop.UniqueItems
-				param.CommonValidations.MultipleOf = prop.MultipleOf
-				param.CommonValidations.Enum = prop.Enum
-				operation.Operation.Parameters = append(operation.Operation.Parameters, param)
-			}
+			operation.Operation.Parameters = append(operation.Operation.Parameters, params...)
 			return nil
 		}
 	case "body":
@@ -304,33 +502,169 @@ func (operation *Operation) ParseParamComment(commentLine string, astFile *ast.F
 		return fmt.Errorf("%s is not supported paramType", paramType)
 	}
 
-	if err := operation.parseAndExtractionParamAttribute(commentLine, objectType, refType, &param); err != nil {
+	if err := operation.parseAndExtractionParamAttribute(commentLine, objectType, refType, &param, astFile); err != nil {
 		return err
 	}
+	if paramType == "cookie" {
+		operation.addCookieParam(param)
+		return nil
+	}
 	operation.Operation.Parameters = append(operation.Operation.Parameters, param)
 	return nil
 }
 
+// cookieParamsExtension lists the cookie parameters of an operation, which Swagger 2.0 lacks, converted to OpenAPI 3
+// parameters by gen.ToOpenAPI3Parameters
+const cookieParamsExtension = "x-cookie"
+
+// addCookieParam lists a cookie parameter in the x-cookie extension of the operation instead of its parameters
+func (operation *Operation) addCookieParam(param spec.Parameter) {
+	if operation.parser != nil {
+		debugf(operation.parser.debug, "Swagger 2.0 lacks cookie parameters, %s is listed in the %s extension", param.Name, cookieParamsExtension)
+	}
+	params, _ := operation.Extensions[cookieParamsExtension].([]spec.Parameter)
+	operation.AddExtension(cookieParamsExtension, append(params, param))
+}
+
 var regexAttributes = map[string]*regexp.Regexp{
 	// for Enums(A, B)
 	"enums": regexp.MustCompile(`(?i)\s+enums\(.*\)`),
+	// for enumType(model.Status)
+	"enumType": regexp.MustCompile(`(?i)\s+enumType\(.*\)`),
+	// for x-enum-descriptions(Apple, Banana), labelling the enums in the same order
+	"enumDescriptions": regexp.MustCompile(`(?i)\s+x-enum-descriptions\(.*\)`),
 	// for maximum(0)
-	"maximum": regexp.MustCompile(`(?i)\s+maxinum|maximum\(.*\)`),
+	"maximum": regexp.MustCompile(`(?i)\s+(?:maxinum|maximum)\(.*\)`),
 	// for minimum(0)
-	"minimum": regexp.MustCompile(`(?i)\s+mininum|minimum\(.*\)`),
+	"minimum": regexp.MustCompile(`(?i)\s+(?:mininum|minimum)\(.*\)`),
 	// for default(0)
 	"default": regexp.MustCompile(`(?i)\s+default\(.*\)`),
 	// for minlength(0)
 	"minlength": regexp.MustCompile(`(?i)\s+minlength\(.*\)`),
 	// for maxlength(0)
 	"maxlength": regexp.MustCompile(`(?i)\s+maxlength\(.*\)`),
+	// for example(UserCreateReq)
+	"example": regexp.MustCompile(`(?i)\s+example\(.*\)`),
 	// for format(email)
 	"format": regexp.MustCompile(`(?i)\s+format\(.*\)`),
 	// for collectionFormat(csv)
 	"collectionFormat": regexp.MustCompile(`(?i)\s+collectionFormat\(.*\)`),
 }
 
-func (operation *Operation) parseAndExtractionParamAttribute(commentLine, objectType, schemaType string, param *spec.Parameter) error {
+// parseObjectParams expands each field of the struct refType into a parameter of paramType. The name of a
+// parameter is given by the form tag, falling back on the json name, fields of nested structs are flattened
+// with dotted names like page.size
+func (operation *Operation) parseObjectParams(paramType, prefix, refType string, astFile *ast.File, parents []*TypeSpecDef) ([]spec.Parameter, error) {
+	schema, err := operation.parser.getTypeSchema(refType, astFile, false)
+	if err != nil {
+		return nil, err
+	}
+	typeSpecDef := operation.parser.packages.FindTypeSpec(refType, astFile)
+	for _, parent := range parents {
+		if parent == typeSpecDef {
+			return nil, fmt.Errorf("recursive type %s can't be expanded into %s parameters", refType, paramType)
+		}
+	}
+	fields := operation.parser.structFieldsByName(typeSpecDef)
+
+	var params []spec.Parameter
+	for _, item := range schema.Properties.ToOrderedSchemaItems() {
+		name := item.Name
+		prop := item.Schema
+		field := fields[name]
+		if field != nil && field.Tag != nil {
+			formTag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", "")).Get("form")
+			if formName := strings.TrimSpace(strings.Split(formTag, ",")[0]); formName == "-" {
+				continue
+			} else if formName != "" {
+				name = formName
+			}
+		}
+		required := false
+		for _, requiredName := range schema.Required {
+			if requiredName == item.Name {
+				required = true
+				break
+			}
+		}
+
+		var param spec.Parameter
+		switch {
+		case len(prop.Type) > 0 && prop.Type[0] == ARRAY &&
+			prop.Items.Schema != nil &&
+			len(prop.Items.Schema.Type) > 0 &&
+			IsSimplePrimitiveType(prop.Items.Schema.Type[0]):
+			param = createParameter(paramType, prop.Description, prefix+name, prop.Type[0], required)
+			param.SimpleSchema.Type = prop.Type[0]
+			if operation.parser.collectionFormatInQuery != "" && param.CollectionFormat == "" {
+				param.CollectionFormat = TransToValidCollectionFormat(operation.parser.collectionFormatInQuery)
+			}
+			param.SimpleSchema.Items = &spec.Items{
+				SimpleSchema: spec.SimpleSchema{
+					Type: prop.Items.Schema.Type[0],
+				},
+			}
+		case len(prop.Type) > 0 && IsSimplePrimitiveType(prop.Type[0]):
+			param = createParameter(paramType, prop.Description, prefix+name, prop.Type[0], required)
+		case field != nil && (prop.Ref.String() != "" || len(prop.Type) > 0 && prop.Type[0] == OBJECT):
+			fieldType, err := getFieldType(field.Type)
+			if err != nil {
+				warnf(operation.parser.debug, "skip field [%s] in %s is not supported type for %s", item.Name, refType, paramType)
+				continue
+			}
+			nested, err := operation.parseObjectParams(paramType, prefix+name+".", fieldType, typeSpecDef.File, append(parents, typeSpecDef))
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, nested...)
+			continue
+		default:
+			warnf(operation.parser.debug, "skip field [%s] in %s is not supported type for %s", item.Name, refType, paramType)
+			continue
+		}
+		param.Nullable = prop.Nullable
+		param.Format = prop.Format
+		param.Default = prop.Default
+		param.Example = prop.Example
+		param.Extensions = prop.Extensions
+		param.CommonValidations.Maximum = prop.Maximum
+		param.CommonValidations.Minimum = prop.Minimum
+		param.CommonValidations.ExclusiveMaximum = prop.ExclusiveMaximum
+		param.CommonValidations.ExclusiveMinimum = prop.ExclusiveMinimum
+		param.CommonValidations.MaxLength = prop.MaxLength
+		param.CommonValidations.MinLength = prop.MinLength
+		param.CommonValidations.Pattern = prop.Pattern
+		param.CommonValidations.MaxItems = prop.MaxItems
+		param.CommonValidations.MinItems = prop.MinItems
+		param.CommonValidations.UniqueItems = prop.UniqueItems
+		param.CommonValidations.MultipleOf = prop.MultipleOf
+		param.CommonValidations.Enum = prop.Enum
+		if field != nil && field.Tag != nil && prop.Type[0] != ARRAY {
+			// the scalar rules of the binding and validate tags, left out of the models, constrain the parameter
+			// without overriding the dedicated tags
+			structTag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", ""))
+			rules := &structField{
+				schemaType: prop.Type[0],
+				minimum:    prop.Minimum,
+				maximum:    prop.Maximum,
+				minLength:  prop.MinLength,
+				maxLength:  prop.MaxLength,
+				enums:      prop.Enum,
+			}
+			rules.parseValidationTag(structTag.Get("binding"), true)
+			rules.parseValidationTag(structTag.Get("validate"), true)
+			param.CommonValidations.Minimum = rules.minimum
+			param.CommonValidations.Maximum = rules.maximum
+			param.CommonValidations.MinLength = rules.minLength
+			param.CommonValidations.MaxLength = rules.maxLength
+			param.CommonValidations.Enum = rules.enums
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+func (operation *Operation) parseAndExtractionParamAttribute(commentLine, objectType, schemaType string, param *spec.Parameter, astFile *ast.File) error {
 	schemaType = TransToValidSchemeType(schemaType)
 	for attrKey, re := range regexAttributes {
 		attr, err := findAttr(re, commentLine)
@@ -343,6 +677,13 @@ func (operation *Operation) parseAndExtractionParamAttribute(commentLine, object
 			if err != nil {
 				return err
 			}
+		case "enumType":
+			err := operation.setEnumTypeParam(attr, schemaType, param, astFile)
+			if err != nil {
+				return err
+			}
+		case "enumDescriptions":
+			param.AddExtension("x-enum-descriptions", splitEnumDescriptions(attr))
 		case "maximum":
 			n, err := setNumberParam(attrKey, schemaType, attr, commentLine)
 			if err != nil {
@@ -358,7 +699,7 @@ func (operation *Operation) parseAndExtractionParamAttribute(commentLine, object
 		case "default":
 			value, err := defineType(schemaType, attr)
 			if err != nil {
-				return nil
+				return err
 			}
 			param.Default = value
 		case "maxlength":
@@ -374,18 +715,105 @@ func (operation *Operation) parseAndExtractionParamAttribute(commentLine, object
 			}
 			param.MinLength = &n
 		case "format":
-			param.Format = attr
+			if operation.parser != nil {
+				operation.parser.checkFormat(attr)
+			}
+			if param.Schema != nil {
+				param.Schema.Format = attr
+			} else {
+				param.Format = attr
+			}
 		case "collectionFormat":
 			n, err := setCollectionFormatParam(attrKey, objectType, attr, commentLine)
 			if err != nil {
 				return err
 			}
 			param.CollectionFormat = n
+		case "example":
+			value, err := operation.paramExample(attr, objectType, schemaType)
+			if err != nil {
+				return err
+			}
+			if param.Schema != nil {
+				param.Schema.Example = value
+			} else {
+				param.AddExtension("x-example", value)
+			}
 		}
 	}
+	if descriptions, ok := param.Extensions["x-enum-descriptions"].([]string); ok && len(descriptions) != len(param.Enum) {
+		return fmt.Errorf("x-enum-descriptions of param %s has %d descriptions for %d enums", param.Name, len(descriptions), len(param.Enum))
+	}
+	if isDeprecatedParam(commentLine) {
+		param.AddExtension("x-deprecated", true)
+	}
 	return nil
 }
 
+// splitEnumDescriptions splits the comma separated descriptions of the enums
+func splitEnumDescriptions(attr string) []string {
+	descriptions := strings.Split(attr, ",")
+	for i, description := range descriptions {
+		descriptions[i] = strings.TrimSpace(description)
+	}
+	return descriptions
+}
+
+// paramExample returns the example of a param, either the name of an example declared by @example.named or a
+// value of the param type, written as json unless the type is primitive
+func (operation *Operation) paramExample(attr, objectType, schemaType string) (interface{}, error) {
+	if value, err := operation.namedExample(attr); err == nil {
+		return value, nil
+	}
+	if objectType == PRIMITIVE {
+		return defineType(schemaType, attr)
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(attr), &value); err != nil {
+		return nil, fmt.Errorf("example %s is neither declared by @example.named nor a valid json value", attr)
+	}
+	return value, nil
+}
+
+// isDeprecatedParam tells whether the deprecated attribute follows the description of a param
+func isDeprecatedParam(commentLine string) bool {
+	for _, field := range strings.Fields(commentLine[strings.LastIndex(commentLine, `"`)+1:]) {
+		if strings.EqualFold(field, "deprecated") {
+			return true
+		}
+	}
+	return false
+}
+
+// responseExample returns the example of a response, either the name of an example declared by @example.named or
+// a json value
+func (operation *Operation) responseExample(attr string) (interface{}, error) {
+	value, err := operation.namedExample(attr)
+	if err == nil {
+		return value, nil
+	}
+	if jsonErr := json.Unmarshal([]byte(attr), &value); jsonErr != nil {
+		if exampleNamePattern.MatchString(attr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("example %s of the response is neither declared by @example.named nor a valid json value: %s", attr, jsonErr)
+	}
+	return value, nil
+}
+
+// exampleNamePattern matches the names given to the examples by @example.named
+var exampleNamePattern = regexp.MustCompile(`^[\w\-\.]+$`)
+
+// namedExample returns the value of the example declared by @example.named in the general api info
+func (operation *Operation) namedExample(name string) (interface{}, error) {
+	if operation.parser != nil {
+		if value, ok := operation.parser.namedExamples[name]; ok {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("example %s is not declared by @example.named", name)
+}
+
 func findAttr(re *regexp.Regexp, commentLine string) (string, error) {
 	attr := re.FindString(commentLine)
 	l := strings.Index(attr, "(")
@@ -431,11 +859,58 @@ func setEnumParam(attr, schemaType string, param *spec.Parameter) error {
 	return nil
 }
 
+// setEnumTypeParam sets the constants of the named type typeName as the enum of param
+func (operation *Operation) setEnumTypeParam(typeName, schemaType string, param *spec.Parameter, astFile *ast.File) error {
+	var typeSpecDef *TypeSpecDef
+	if operation.parser != nil {
+		typeSpecDef = operation.parser.packages.FindTypeSpec(typeName, astFile)
+	}
+	if typeSpecDef == nil {
+		return fmt.Errorf("can not find enum type %s", typeName)
+	}
+	if len(typeSpecDef.Enums) == 0 {
+		return fmt.Errorf("type %s has no enum values", typeName)
+	}
+
+	varNames := make([]string, 0, len(typeSpecDef.Enums))
+	for _, enum := range typeSpecDef.Enums {
+		value := enum.Value
+		switch v := value.(type) {
+		case string:
+			if schemaType != STRING {
+				return fmt.Errorf("enum type %s holds strings, but the param is %s", typeName, schemaType)
+			}
+		case int:
+			if schemaType == NUMBER {
+				value = float64(v)
+			} else if schemaType != INTEGER {
+				return fmt.Errorf("enum type %s holds numbers, but the param is %s", typeName, schemaType)
+			}
+		case float64:
+			if schemaType != NUMBER {
+				return fmt.Errorf("enum type %s holds numbers, but the param is %s", typeName, schemaType)
+			}
+		}
+		param.Enum = append(param.Enum, value)
+		varNames = append(varNames, enum.Key)
+	}
+	param.AddExtension("x-enum-varnames", varNames)
+	if comments, descriptions := enumComments(typeSpecDef.Enums); comments != nil {
+		param.AddExtension("x-enum-comments", comments)
+		param.AddExtension("x-enum-descriptions", descriptions)
+	}
+	return nil
+}
+
 func setCollectionFormatParam(name, schemaType, attr, commentLine string) (string, error) {
 	if schemaType != ARRAY {
 		return "", fmt.Errorf("%s is attribute to set to an array. comment=%s got=%s", name, commentLine, schemaType)
 	}
-	return TransToValidCollectionFormat(attr), nil
+	format := TransToValidCollectionFormat(attr)
+	if format == "" {
+		return "", fmt.Errorf("%s %s is not one of csv, ssv, tsv, pipes, multi. comment=%s", name, attr, commentLine)
+	}
+	return format, nil
 }
 
 // defineType enum value define the type (object and array unsupported)
@@ -469,12 +944,24 @@ func defineType(schemaType string, value string) (interface{}, error) {
 
 // ParseTagsComment parses comment for given `tag` comment string.
 func (operation *Operation) ParseTagsComment(commentLine string) {
-	tags := strings.Split(commentLine, ",")
-	for _, tag := range tags {
-		operation.Tags = append(operation.Tags, strings.TrimSpace(tag))
+	for _, tag := range strings.Split(commentLine, ",") {
+		// a tag given more than once keeps its first place
+		if tag = strings.TrimSpace(tag); tag != "" && !containsTag(operation.Tags, tag) {
+			operation.Tags = append(operation.Tags, tag)
+		}
 	}
 }
 
+// containsTag tells whether tags holds tag
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseAcceptComment parses comment for given `accept` comment string.
 func (operation *Operation) ParseAcceptComment(commentLine string) error {
 	return parseMimeTypeList(commentLine, &operation.Consumes, "%v accept type can't be accepted")
@@ -489,62 +976,127 @@ func (operation *Operation) ParseProduceComment(commentLine string) error {
 // `produce` (`Content-Type:` response header) or
 // `accept` (`Accept:` request header)
 func parseMimeTypeList(mimeTypeList string, typeList *[]string, format string) error {
-	mimeTypes := strings.Split(mimeTypeList, ",")
-	for _, typeName := range mimeTypes {
-		if mimeTypePattern.MatchString(typeName) {
-			*typeList = append(*typeList, typeName)
+	for _, typeName := range strings.Split(mimeTypeList, ",") {
+		// a mime type may carry parameters, like application/json; charset=utf-8
+		typeName = strings.TrimSpace(typeName)
+		if typeName == "" {
 			continue
 		}
-		if aliasMimeType, ok := mimeTypeAliases[typeName]; ok {
-			*typeList = append(*typeList, aliasMimeType)
+		mimeType := typeName
+		if i := strings.Index(typeName, ";"); i >= 0 {
+			if aliasMimeType, ok := mimeTypeAliases[strings.TrimSpace(typeName[:i])]; ok {
+				mimeType = aliasMimeType + typeName[i:]
+			}
+		} else if aliasMimeType, ok := mimeTypeAliases[typeName]; ok {
+			mimeType = aliasMimeType
+		}
+		if mimeTypePattern.MatchString(mimeType) {
+			*typeList = append(*typeList, mimeType)
 			continue
 		}
-		return fmt.Errorf(format, typeName)
+		return fmt.Errorf(format+", use a mime type like application/json or one of the aliases %s", typeName,
+			strings.Join(mimeTypeAliasNames(), ", "))
 	}
 	return nil
 }
 
-var routerPattern = regexp.MustCompile(`^(/[\w\.\/\-{}\+:]*)[[:blank:]]+\[(\w+)]`)
+// mimeTypeAliasNames returns the aliases of mimeTypeAliases, sorted
+func mimeTypeAliasNames() []string {
+	names := make([]string, 0, len(mimeTypeAliases))
+	for name := range mimeTypeAliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var routerPattern = regexp.MustCompile(`^(/[\w\.\/\-{}\+:]*)(\?\S*)?[[:blank:]]+\[(\w+)]`)
 
 // ParseRouterComment parses comment for gived `router` comment string.
 func (operation *Operation) ParseRouterComment(commentLine string) error {
 	var matches []string
 
-	if matches = routerPattern.FindStringSubmatch(commentLine); len(matches) != 3 {
+	if matches = routerPattern.FindStringSubmatch(commentLine); len(matches) != 4 {
 		return fmt.Errorf("can not parse router comment \"%s\"", commentLine)
 	}
 	path := matches[1]
-	httpMethod := matches[2]
+	httpMethod := matches[3]
 
 	operation.Path = path
 	operation.HTTPMethod = strings.ToUpper(httpMethod)
 
+	// the query string isn't part of the path, its keys are only checked against the @Param in query
+	operation.queryHints = nil
+	for _, pair := range strings.Split(strings.TrimPrefix(matches[2], "?"), "&") {
+		if key := strings.SplitN(pair, "=", 2)[0]; key != "" {
+			operation.queryHints = append(operation.queryHints, key)
+		}
+	}
+
+	return nil
+}
+
+// ParseAnnotationsFromComment merges the annotations of the function referenced by an `annotationsFrom` comment
+// into the operation, the types they use are looked up from the file in which that function is declared.
+func (operation *Operation) ParseAnnotationsFromComment(commentLine string, astFile *ast.File) error {
+	funcDecl, funcFile := operation.parser.packages.FindFuncDecl(commentLine, astFile)
+	if funcDecl == nil {
+		return fmt.Errorf("@annotationsFrom cannot find function: %s", commentLine)
+	}
+	for _, decl := range operation.annotationsFrom {
+		if decl == funcDecl {
+			return fmt.Errorf("@annotationsFrom %s references itself", commentLine)
+		}
+	}
+	if funcDecl.Doc == nil {
+		return nil
+	}
+
+	operation.annotationsFrom = append(operation.annotationsFrom, funcDecl)
+	defer func() {
+		operation.annotationsFrom = operation.annotationsFrom[:len(operation.annotationsFrom)-1]
+	}()
+	for _, comment := range operation.parser.rewriteSwagDirectives(funcDecl.Doc).List {
+		if err := operation.ParseComment(comment.Text, funcFile); err != nil {
+			return err
+		}
+	}
+	if err := operation.checkPendingExtension(); err != nil {
+		return err
+	}
+	// the lines following @annotationsFrom don't continue a @Description of the referenced function
+	operation.inDescription = false
+	operation.descriptionBlankLines = 0
 	return nil
 }
 
 // ParseSecurityComment parses comment for gived `security` comment string.
+// Schemes joined by && are all required, each @Security line adds an alternative, like `ApiKey && OAuth2[read,write]`.
 func (operation *Operation) ParseSecurityComment(commentLine string) error {
-	securitySource := commentLine[strings.Index(commentLine, "@Security")+1:]
-	l := strings.Index(securitySource, "[")
-	r := strings.Index(securitySource, "]")
-	// exists scope
-	if !(l == -1 && r == -1) {
-		scopes := securitySource[l+1 : r]
-		s := []string{}
-		for _, scope := range strings.Split(scopes, ",") {
-			scope = strings.TrimSpace(scope)
-			s = append(s, scope)
-		}
-		securityKey := securitySource[0:l]
-		securityMap := map[string][]string{}
-		securityMap[securityKey] = append(securityMap[securityKey], s...)
-		operation.Security = append(operation.Security, securityMap)
-	} else {
-		securityKey := strings.TrimSpace(securitySource)
-		securityMap := map[string][]string{}
-		securityMap[securityKey] = []string{}
-		operation.Security = append(operation.Security, securityMap)
+	securityMap := map[string][]string{}
+	for _, securitySource := range strings.Split(commentLine, "&&") {
+		securitySource = strings.TrimSpace(securitySource)
+		securityKey, scopes := securitySource, []string{}
+		if l := strings.Index(securitySource, "["); l != -1 {
+			if !strings.HasSuffix(securitySource, "]") {
+				return fmt.Errorf("can not parse security comment \"%s\"", commentLine)
+			}
+			securityKey = strings.TrimSpace(securitySource[:l])
+			for _, scope := range strings.Split(securitySource[l+1:len(securitySource)-1], ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+		if securityKey == "" || strings.ContainsAny(securityKey, " \t]") {
+			return fmt.Errorf("can not parse security comment \"%s\"", commentLine)
+		}
+		if _, ok := securityMap[securityKey]; !ok {
+			securityMap[securityKey] = []string{}
+		}
+		securityMap[securityKey] = append(securityMap[securityKey], scopes...)
 	}
+	operation.Security = append(operation.Security, securityMap)
 	return nil
 }
 
@@ -603,7 +1155,29 @@ func findTypeDef(importPath, typeName string) (*ast.TypeSpec, error) {
 	return nil, fmt.Errorf("type spec not found")
 }
 
-var responsePattern = regexp.MustCompile(`^([\w,]+)[\s]+([\w\{\}]+)[\s]+([\w\-\.\/\{\}=,\[\]]+)[^"]*(.*)?`)
+var responsePattern = regexp.MustCompile(`^([\w,]+)[\s]+([\w\{\}]+)[\s]+([\w\-\.\/\{\}=,\[\]:#]+)(.*)?`)
+
+// quotedDescription returns the quoted description of the remainder of a response comment, the text before the
+// first quote being ignored
+func quotedDescription(remainder string) string {
+	if i := strings.Index(remainder, "\""); i >= 0 {
+		return strings.Trim(strings.TrimSpace(remainder[i:]), "\"")
+	}
+	return ""
+}
+
+// discriminatorPattern matches the discriminator(type) attribute of a {oneOf} or {anyOf} response
+var discriminatorPattern = regexp.MustCompile(`(?i)\s+discriminator\([^)]*\)`)
+
+// producePattern matches the produce(mimeType) attribute of a response, overriding the @Produce mime types
+var producePattern = regexp.MustCompile(`(?i)\s+produce\([^)]*\)`)
+
+// alternativesExtensions maps the {oneOf} and {anyOf} data types to the extension listing their models, since
+// Swagger 2.0 has neither oneOf nor anyOf
+var alternativesExtensions = map[string]string{
+	"oneOf": "x-oneOf",
+	"anyOf": "x-anyOf",
+}
 
 //ResponseType{data1=Type1,data2=Type2}
 var combinedPattern = regexp.MustCompile(`^([\w\-\.\/\[\]]+)\{(.*)\}$`)
@@ -639,6 +1213,11 @@ func (operation *Operation) parseObjectSchema(refType string, astFile *ast.File)
 			return nil, err
 		}
 		return spec.MapProperty(schema), nil
+	case strings.HasPrefix(refType, externalRefPrefix):
+		if operation.parser == nil {
+			return nil, fmt.Errorf("can't resolve %s without a parser", refType)
+		}
+		return operation.parser.getExternalSchema(refType)
 	case strings.Contains(refType, "{"):
 		return operation.parseCombinedObjectSchema(refType, astFile)
 	default:
@@ -680,26 +1259,70 @@ func (operation *Operation) parseCombinedObjectSchema(refType string, astFile *a
 	}
 
 	fields := parseFields(matches[2])
+	baseProps := operation.definitionProperties(schema)
 	props := map[string]spec.Schema{}
 	for _, field := range fields {
-		if matches := strings.SplitN(field, "=", 2); len(matches) == 2 {
-			schema, err := operation.parseObjectSchema(matches[1], astFile)
-			if err != nil {
-				return nil, err
+		matches := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(matches) != 2 || matches[0] == "" || matches[1] == "" {
+			return nil, fmt.Errorf("invalid field %s of %s, it must be written name=type", field, refType)
+		}
+		if _, ok := baseProps[matches[0]]; len(baseProps) > 0 && !ok {
+			return nil, fmt.Errorf("field %s not found in %s", matches[0], refType)
+		}
+		fieldType, format := splitFieldFormat(matches[1])
+		schema, err := operation.parseObjectSchema(fieldType, astFile)
+		if err != nil {
+			return nil, err
+		}
+		if format != "" {
+			if operation.parser != nil {
+				operation.parser.checkFormat(format)
 			}
-			props[matches[0]] = *schema
+			schema = copySchemaShallow(schema)
+			schema.Format = format
 		}
+		props[matches[0]] = *schema
 	}
 
 	if len(props) == 0 {
 		return schema, nil
 	}
-	return spec.ComposedSchema(*schema, spec.Schema{
+	fieldsSchema := spec.Schema{
 		SchemaProps: spec.SchemaProps{
 			Type:       []string{OBJECT},
 			Properties: props,
 		},
-	}), nil
+	}
+	if refType == OBJECT {
+		// object{id=int} is an anonymous object having only these fields
+		return &fieldsSchema, nil
+	}
+	return spec.ComposedSchema(*schema, fieldsSchema), nil
+}
+
+// splitFieldFormat splits the type of a field of a combined type from its optional format written after a colon,
+// like id=string:uuid
+func splitFieldFormat(fieldType string) (string, string) {
+	if strings.HasPrefix(fieldType, externalRefPrefix) {
+		return fieldType, ""
+	}
+	if i := strings.LastIndex(fieldType, ":"); i > strings.LastIndexAny(fieldType, "}]") {
+		return fieldType[:i], fieldType[i+1:]
+	}
+	return fieldType, ""
+}
+
+// definitionProperties returns the properties of the definition referenced by schema, nil if schema is not a
+// reference or the definition has no properties to check the overridden fields of a combined type against
+func (operation *Operation) definitionProperties(schema *spec.Schema) map[string]spec.Schema {
+	if operation.parser == nil || schema.Ref.String() == "" {
+		return nil
+	}
+	name := strings.TrimPrefix(schema.Ref.String(), "#/definitions/")
+	if definition, ok := operation.parser.swagger.Definitions[name]; ok {
+		return definition.Properties
+	}
+	return nil
 }
 
 func (operation *Operation) parseAPIObjectSchema(schemaType, refType string, astFile *ast.File) (*spec.Schema, error) {
@@ -718,11 +1341,42 @@ func (operation *Operation) parseAPIObjectSchema(schemaType, refType string, ast
 		return spec.ArrayProperty(schema), nil
 	case PRIMITIVE:
 		return PrimitiveSchema(refType), nil
+	case "oneOf", "anyOf":
+		return operation.parseAlternativesSchema(schemaType, refType, astFile)
+	case "file":
+		// the content type of the file is given by @Produce
+		if refType != "binary" {
+			return nil, fmt.Errorf("{file} can't reference the model %s, its data type must be binary", refType)
+		}
+		return &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{STRING}, Format: "binary"}}, nil
 	default:
 		return PrimitiveSchema(schemaType), nil
 	}
 }
 
+// parseAlternativesSchema returns an object schema listing the models of refType, separated by commas, in the
+// extension of schemaType
+func (operation *Operation) parseAlternativesSchema(schemaType, refType string, astFile *ast.File) (*spec.Schema, error) {
+	var schemas []spec.Schema
+	for _, modelType := range strings.Split(refType, ",") {
+		if modelType == "" {
+			return nil, fmt.Errorf("invalid type: %s", refType)
+		}
+		schema, err := operation.parseObjectSchema(modelType, astFile)
+		if err != nil {
+			return nil, err
+		}
+		if schema.Ref.String() == "" {
+			return nil, fmt.Errorf("{%s} lists only models, %s has no definition", schemaType, modelType)
+		}
+		schemas = append(schemas, *schema)
+	}
+	schema := PrimitiveSchema(OBJECT)
+	// the extension is set directly since AddExtension lowercases its name
+	schema.Extensions = spec.Extensions{alternativesExtensions[schemaType]: schemas}
+	return schema, nil
+}
+
 // ParseResponseComment parses comment for given `response` comment string.
 func (operation *Operation) ParseResponseComment(commentLine string, astFile *ast.File) error {
 	var matches []string
@@ -735,25 +1389,73 @@ func (operation *Operation) ParseResponseComment(commentLine string, astFile *as
 		return err
 	}
 
-	responseDescription := strings.Trim(matches[4], "\"")
+	// the attributes are taken out of the remainder before the description, an unquoted example may hold quotes
+	description := matches[4]
+	var discriminator string
+	if loc := discriminatorPattern.FindStringIndex(description); loc != nil {
+		name, err := findAttr(discriminatorPattern, description)
+		if err != nil {
+			return err
+		}
+		discriminator = name
+		description = description[:loc[0]] + description[loc[1]:]
+	}
+	var produces []string
+	if loc := producePattern.FindStringIndex(description); loc != nil {
+		mimeTypes, err := findAttr(producePattern, description)
+		if err != nil {
+			return err
+		}
+		if err = parseMimeTypeList(mimeTypes, &produces, "%v produce type can't be accepted"); err != nil {
+			return err
+		}
+		description = description[:loc[0]] + description[loc[1]:]
+	}
+	var examples map[string]interface{}
+	if loc := regexAttributes["example"].FindStringIndex(description); loc != nil {
+		// the example is the last attribute, its json may hold parentheses
+		attr := description[loc[0]:loc[1]]
+		value, err := operation.responseExample(strings.TrimSpace(attr[strings.Index(attr, "(")+1 : len(attr)-1]))
+		if err != nil {
+			return err
+		}
+		mimeType := "application/json"
+		if len(produces) > 0 {
+			mimeType = produces[0]
+		} else if len(operation.Produces) > 0 {
+			mimeType = operation.Produces[0]
+		}
+		examples = map[string]interface{}{mimeType: value}
+		description = description[:loc[0]]
+	}
+	responseDescription := quotedDescription(description)
 	schemaType := strings.Trim(matches[2], "{}")
 	refType := matches[3]
 	schema, err := operation.parseAPIObjectSchema(schemaType, refType, astFile)
 	if err != nil {
 		return err
 	}
+	if discriminator != "" {
+		if _, ok := alternativesExtensions[schemaType]; !ok {
+			return fmt.Errorf("discriminator(%s) is only allowed on {oneOf} and {anyOf} responses", discriminator)
+		}
+		schema.Discriminator = discriminator
+	}
 
 	for _, codeStr := range strings.Split(matches[1], ",") {
 		if strings.EqualFold(codeStr, "default") {
 			operation.DefaultResponse().Schema = schema
 			operation.DefaultResponse().Description = responseDescription
+			operation.DefaultResponse().Examples = examples
+			setResponseProduces(operation.DefaultResponse(), produces)
 		} else if code, err := strconv.Atoi(codeStr); err == nil {
 			resp := &spec.Response{
-				ResponseProps: spec.ResponseProps{Schema: schema, Description: responseDescription},
+				ResponseProps: spec.ResponseProps{Schema: schema, Description: responseDescription, Examples: examples},
 			}
 			if resp.Description == "" {
 				resp.Description = http.StatusText(code)
 			}
+			setResponseProduces(resp, produces)
 			operation.AddResponse(code, resp)
 		} else {
 			return fmt.Errorf("can not parse response comment \"%s\"", commentLine)
@@ -763,6 +1465,17 @@ func (operation *Operation) ParseResponseComment(commentLine string, astFile *as
 	return nil
 }
 
+// responseProducesExtension lists the mime types of a response given by its produce(mimeType) attribute, since
+// Swagger 2.0 responses share the ones of the operation
+const responseProducesExtension = "x-produces"
+
+// setResponseProduces sets the mime types given by the produce(mimeType) attribute of a response
+func setResponseProduces(resp *spec.Response, produces []string) {
+	if len(produces) > 0 {
+		resp.AddExtension(responseProducesExtension, produces)
+	}
+}
+
 // ParseResponseHeaderComment parses comment for gived `response header` comment string.
 func (operation *Operation) ParseResponseHeaderComment(commentLine string, astFile *ast.File) error {
 	var matches []string
@@ -773,57 +1486,158 @@ func (operation *Operation) ParseResponseHeaderComment(commentLine string, astFi
 
 	schemaType := strings.Trim(matches[2], "{}")
 	headerKey := matches[3]
-	description := strings.Trim(matches[4], "\"")
+	description := quotedDescription(matches[4])
 	header := spec.Header{}
 	header.Description = description
 	header.Type = schemaType
+	headers := map[string]spec.Header{headerKey: header}
+	if schemaType == OBJECT {
+		var err error
+		if headers, err = operation.parseObjectHeaders(headerKey, astFile); err != nil {
+			return err
+		}
+	}
 
-	if strings.EqualFold(matches[1], "all") {
-		if operation.Responses.Default != nil {
-			if operation.Responses.Default.Headers == nil {
-				operation.Responses.Default.Headers = make(map[string]spec.Header)
-			}
-			operation.Responses.Default.Headers[headerKey] = header
+	// the responses may be declared after @Header or inherited from @failure.default, they're checked by
+	// applyResponseHeaders
+	for _, codeStr := range strings.Split(matches[1], ",") {
+		if _, err := strconv.Atoi(codeStr); err != nil && !strings.EqualFold(codeStr, "default") && !strings.EqualFold(codeStr, "all") {
+			return fmt.Errorf("can not parse response comment \"%s\"", commentLine)
 		}
-		if operation.Responses != nil && operation.Responses.StatusCodeResponses != nil {
-			for code, response := range operation.Responses.StatusCodeResponses {
-				if response.Headers == nil {
-					response.Headers = make(map[string]spec.Header)
-				}
-				response.Headers[headerKey] = header
-				operation.Responses.StatusCodeResponses[code] = response
-			}
+	}
+	responseHeader := responseHeader{codes: matches[1], headers: headers, commentLine: commentLine}
+	operation.responseHeaders = append(operation.responseHeaders, responseHeader)
+	if operation.Responses != nil {
+		// sets the headers on the responses already parsed right away, the missing ones failing later on
+		_ = operation.setResponseHeaders(responseHeader, nil)
+	}
+	return nil
+}
+
+// applyResponseHeaders sets the headers of the @Header annotations on the responses of the operation, taking the
+// ones of defaults it lacks. It fails when a @Header references a response neither the operation nor defaults have.
+func (operation *Operation) applyResponseHeaders(defaults *spec.Responses) error {
+	for _, responseHeader := range operation.responseHeaders {
+		if err := operation.setResponseHeaders(responseHeader, defaults); err != nil {
+			return err
 		}
-		return nil
 	}
+	return nil
+}
 
-	for _, codeStr := range strings.Split(matches[1], ",") {
+// setResponseHeaders sets the headers of a @Header annotation on the responses of the operation, taking the ones of
+// defaults it lacks, it fails when a response can't be found
+func (operation *Operation) setResponseHeaders(responseHeader responseHeader, defaults *spec.Responses) error {
+	if defaults == nil {
+		defaults = &spec.Responses{}
+	}
+	if operation.Responses == nil {
+		operation.Responses = &spec.Responses{}
+	}
+	if operation.Responses.StatusCodeResponses == nil {
+		operation.Responses.StatusCodeResponses = make(map[int]spec.Response)
+	}
+
+	codes := strings.Split(responseHeader.codes, ",")
+	if strings.EqualFold(responseHeader.codes, "all") {
+		codes = []string{"default"}
+		for code := range defaults.StatusCodeResponses {
+			codes = append(codes, strconv.Itoa(code))
+		}
+		for code := range operation.Responses.StatusCodeResponses {
+			codes = append(codes, strconv.Itoa(code))
+		}
+		if operation.Responses.Default == nil && defaults.Default == nil && len(codes) == 1 {
+			return fmt.Errorf("can not find any response for @Header \"%s\"", responseHeader.commentLine)
+		}
+	}
+
+	for _, codeStr := range codes {
 		if strings.EqualFold(codeStr, "default") {
-			if operation.Responses.Default != nil {
-				if operation.Responses.Default.Headers == nil {
-					operation.Responses.Default.Headers = make(map[string]spec.Header)
-				}
-				operation.Responses.Default.Headers[headerKey] = header
+			if operation.Responses.Default == nil && defaults.Default != nil {
+				response := *defaults.Default
+				operation.Responses.Default = &response
 			}
-		} else if code, err := strconv.Atoi(codeStr); err == nil {
-			if operation.Responses != nil && operation.Responses.StatusCodeResponses != nil {
-				if response, responseExist := operation.Responses.StatusCodeResponses[code]; responseExist {
-					if response.Headers == nil {
-						response.Headers = make(map[string]spec.Header)
-					}
-					response.Headers[headerKey] = header
-
-					operation.Responses.StatusCodeResponses[code] = response
+			if operation.Responses.Default == nil {
+				if strings.EqualFold(responseHeader.codes, "all") {
+					continue
 				}
+				return fmt.Errorf("can not find default response for @Header \"%s\"", responseHeader.commentLine)
 			}
-		} else {
-			return fmt.Errorf("can not parse response comment \"%s\"", commentLine)
+			operation.Responses.Default.Headers = withHeaders(operation.Responses.Default.Headers, responseHeader.headers)
+			continue
 		}
+		code, _ := strconv.Atoi(codeStr)
+		response, ok := operation.Responses.StatusCodeResponses[code]
+		if !ok {
+			if response, ok = defaults.StatusCodeResponses[code]; !ok {
+				return fmt.Errorf("can not find response %d for @Header \"%s\"", code, responseHeader.commentLine)
+			}
+		}
+		response.Headers = withHeaders(response.Headers, responseHeader.headers)
+		operation.Responses.StatusCodeResponses[code] = response
 	}
-
 	return nil
 }
 
+// withHeaders returns a copy of headers with the added ones, the headers of a response may be shared with the
+// default responses
+func withHeaders(headers, added map[string]spec.Header) map[string]spec.Header {
+	merged := make(map[string]spec.Header, len(headers)+len(added))
+	for name, header := range headers {
+		merged[name] = header
+	}
+	for name, header := range added {
+		merged[name] = header
+	}
+	return merged
+}
+
+// parseObjectHeaders expands each field of the struct refType into a response header. The name of a header is
+// given by the header tag, falling back on the json name, and its description by the comment of the field
+func (operation *Operation) parseObjectHeaders(refType string, astFile *ast.File) (map[string]spec.Header, error) {
+	if operation.parser == nil {
+		return nil, fmt.Errorf("can't expand %s into headers without a parser", refType)
+	}
+	schema, err := operation.parser.getTypeSchema(refType, astFile, false)
+	if err != nil {
+		return nil, err
+	}
+	fields := operation.parser.structFieldsByName(operation.parser.packages.FindTypeSpec(refType, astFile))
+
+	headers := make(map[string]spec.Header)
+	for _, item := range schema.Properties.ToOrderedSchemaItems() {
+		name := item.Name
+		prop := item.Schema
+		if field := fields[name]; field != nil && field.Tag != nil {
+			headerTag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", "")).Get("header")
+			if headerName := strings.TrimSpace(strings.Split(headerTag, ",")[0]); headerName == "-" {
+				continue
+			} else if headerName != "" {
+				name = headerName
+			}
+		}
+		if len(prop.Type) == 0 || !IsSimplePrimitiveType(prop.Type[0]) {
+			return nil, fmt.Errorf("field %s of %s can't be a header, only the fields of a primitive type can", item.Name, refType)
+		}
+
+		header := spec.Header{}
+		header.Description = prop.Description
+		header.Type = prop.Type[0]
+		header.Format = prop.Format
+		header.Default = prop.Default
+		header.Example = prop.Example
+		header.Enum = prop.Enum
+		header.Maximum = prop.Maximum
+		header.Minimum = prop.Minimum
+		header.MaxLength = prop.MaxLength
+		header.MinLength = prop.MinLength
+		header.Pattern = prop.Pattern
+		headers[name] = header
+	}
+	return headers, nil
+}
+
 var emptyResponsePattern = regexp.MustCompile(`([\w,]+)[\s]+"(.*)"`)
 
 // ParseEmptyResponseComment parse only comment out status code and description,eg: @Success 200 "it's ok"
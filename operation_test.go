@@ -33,6 +33,15 @@ func TestParseTagsComment(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseTagsCommentWithIrregularSpacing(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	assert.NoError(t, operation.ParseComment("@Tags a, b ,c\t,, ,b", nil))
+	assert.NoError(t, operation.ParseComment("@Tags  d ,a", nil))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, operation.Tags)
+}
+
 func TestParseAcceptComment(t *testing.T) {
 	expected := `{
     "consumes": [
@@ -94,6 +103,50 @@ func TestParseProduceComment(t *testing.T) {
 	assert.JSONEq(t, expected, string(b))
 }
 
+func TestParseProduceCommentMultiValue(t *testing.T) {
+	operation := NewOperation(nil)
+	err := operation.ParseComment(`// @Produce json, text/csv`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`// @Accept json,octet-stream`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"application/json", "text/csv"}, operation.Produces)
+	assert.Equal(t, []string{"application/json", "application/octet-stream"}, operation.Consumes)
+
+	err = NewOperation(nil).ParseComment(`// @Produce json,jsn`, nil)
+	assert.EqualError(t, err, "jsn produce type can't be accepted"+mimeTypeAliasesHint)
+}
+
+func TestParseProduceCommentWithParameters(t *testing.T) {
+	operation := NewOperation(nil)
+	assert.NoError(t, operation.ParseComment(`// @Produce application/json; charset=utf-8, xml`, nil))
+	assert.NoError(t, operation.ParseComment(`// @Accept text/plain; charset=utf-8`, nil))
+	assert.Equal(t, []string{"application/json; charset=utf-8", "text/xml"}, operation.Produces)
+	assert.Equal(t, []string{"text/plain; charset=utf-8"}, operation.Consumes)
+
+	operation = NewOperation(nil)
+	assert.NoError(t, operation.ParseComment(`// @Produce json; charset=utf-8`, nil))
+	assert.Equal(t, []string{"application/json; charset=utf-8"}, operation.Produces)
+
+	err := NewOperation(nil).ParseComment(`// @Produce json text/csv`, nil)
+	assert.EqualError(t, err, "json text/csv produce type can't be accepted"+mimeTypeAliasesHint)
+	err = NewOperation(nil).ParseComment(`// @Produce application/json; charset`, nil)
+	assert.EqualError(t, err, "application/json; charset produce type can't be accepted"+mimeTypeAliasesHint)
+}
+
+// mimeTypeAliasesHint ends the errors of the unknown mime types
+const mimeTypeAliasesHint = ", use a mime type like application/json or one of the aliases csv, event-stream, gif, html, jpeg, json, json-api, json-stream, mpfd, octet-stream, plain, png, x-www-form-urlencoded, xml"
+
+func TestParseMimeTypeAliases(t *testing.T) {
+	operation := NewOperation(nil)
+	assert.NoError(t, operation.ParseComment(`// @Accept mpfd,x-www-form-urlencoded`, nil))
+	assert.NoError(t, operation.ParseComment(`// @Produce json-api,event-stream`, nil))
+	assert.Equal(t, []string{"multipart/form-data", "application/x-www-form-urlencoded"}, operation.Consumes)
+	assert.Equal(t, []string{"application/vnd.api+json", "text/event-stream"}, operation.Produces)
+
+	err := operation.ParseComment(`// @Accept form`, nil)
+	assert.EqualError(t, err, "form accept type can't be accepted"+mimeTypeAliasesHint)
+}
+
 func TestParseProduceCommentErr(t *testing.T) {
 	comment := `/@Produce foo`
 	operation := new(Operation)
@@ -110,6 +163,15 @@ func TestParseRouterComment(t *testing.T) {
 	assert.Equal(t, "GET", operation.HTTPMethod)
 }
 
+func TestParseRouterCommentWithQueryString(t *testing.T) {
+	operation := NewOperation(nil)
+	err := operation.ParseComment(`// @Router /search?q&page=1 [get]`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/search", operation.Path)
+	assert.Equal(t, "GET", operation.HTTPMethod)
+	assert.Equal(t, []string{"q", "page"}, operation.queryHints)
+}
+
 func TestParseRouterOnlySlash(t *testing.T) {
 	comment := `// @Router / [get]`
 	operation := NewOperation(nil)
@@ -513,6 +575,76 @@ func TestParseResponseCommentWithDeepNestedFields(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseResponseCommentWithEnvelope(t *testing.T) {
+	src := `
+package model
+
+type Envelope struct {
+	Code int         ` + "`json:\"code\"`" + `
+	Data interface{} ` + "`json:\"data\"`" + `
+}
+
+type Page struct {
+	Total int         ` + "`json:\"total\"`" + `
+	Items interface{} ` + "`json:\"items\"`" + `
+}
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	parser := New()
+	parser.packages.CollectAstFile("model", "model.go", f)
+	_, err = parser.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	operation := NewOperation(parser)
+	err = operation.ParseComment(`@Success 200 {object} model.Envelope{data=model.Page{items=[]model.User}}`, f)
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(operation.Responses.StatusCodeResponses[200].Schema, "", "    ")
+	expected := `{
+    "allOf": [
+        {
+            "$ref": "#/definitions/model.Envelope"
+        },
+        {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Page"
+                        },
+                        {
+                            "type": "object",
+                            "properties": {
+                                "items": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/model.User"
+                                    }
+                                }
+                            }
+                        }
+                    ]
+                }
+            }
+        }
+    ]
+}`
+	assert.Equal(t, expected, string(b))
+
+	err = operation.ParseComment(`@Success 200 {object} model.Envelope{payload=model.User}`, f)
+	assert.EqualError(t, err, "field payload not found in model.Envelope")
+
+	err = operation.ParseComment(`@Success 200 {object} model.Envelope{data=model.Page{users=[]model.User}}`, f)
+	assert.EqualError(t, err, "field users not found in model.Page")
+}
+
 func TestParseResponseCommentWithNestedArrayMapFields(t *testing.T) {
 	comment := `@Success 200 {object} []map[string]model.CommonHeader{data1=[]map[string]model.Payload,data2=map[string][]int} "Error message, if code != 200`
 	operation := NewOperation(nil)
@@ -573,6 +705,97 @@ func TestParseResponseCommentWithNestedArrayMapFields(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseParamCommentByFormat(t *testing.T) {
+	operation := NewOperation(New())
+	err := operation.ParseComment(`@Param id query string true "the id" format(uuid)`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Param email body string true "the email" format(email)`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Param site header string false "the site" format(uri)`, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "uuid", operation.Parameters[0].Format)
+	assert.Equal(t, "email", operation.Parameters[1].Schema.Format)
+	assert.Empty(t, operation.Parameters[1].Format)
+	assert.Equal(t, "uri", operation.Parameters[2].Format)
+}
+
+func TestParseResponseCommentWithInlineFormat(t *testing.T) {
+	operation := NewOperation(New())
+	err := operation.ParseComment(`@Success 200 {object} object{id=string:uuid,site=string:uri,user=object{email=string:email}} "ok"`, nil)
+	assert.NoError(t, err)
+
+	schema := operation.Responses.StatusCodeResponses[200].Schema
+	assert.Equal(t, "uuid", schema.Properties["id"].Format)
+	assert.Equal(t, "uri", schema.Properties["site"].Format)
+	assert.Equal(t, "email", schema.Properties["user"].Properties["email"].Format)
+}
+
+func TestParseResponseCommentWithInlineObject(t *testing.T) {
+	operation := NewOperation(nil)
+	err := operation.ParseComment(`@Success 200 {object} object{id=int,name=string,tags=[]string} "flat"`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Success 201 {object} object{user=object{id=int},users=[]object{id=int}} "nested"`, nil)
+	assert.NoError(t, err)
+	b, _ := json.MarshalIndent(operation, "", "    ")
+
+	expected := `{
+    "responses": {
+        "200": {
+            "description": "flat",
+            "schema": {
+                "type": "object",
+                "properties": {
+                    "id": {
+                        "type": "integer"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "tags": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "201": {
+            "description": "nested",
+            "schema": {
+                "type": "object",
+                "properties": {
+                    "user": {
+                        "type": "object",
+                        "properties": {
+                            "id": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "users": {
+                        "type": "array",
+                        "items": {
+                            "type": "object",
+                            "properties": {
+                                "id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+
+	err = operation.ParseComment(`@Success 200 {object} object{id=int,name} "invalid"`, nil)
+	assert.EqualError(t, err, "invalid field name of object, it must be written name=type")
+}
+
 func TestParseResponseCommentWithObjectTypeInSameFile(t *testing.T) {
 	comment := `@Success 200 {object} testOwner "Error message, if code != 200"`
 	operation := NewOperation(nil)
@@ -671,6 +894,87 @@ func TestParseResponseCommentWithBasicType(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseResponseCommentWithFile(t *testing.T) {
+	operation := NewOperation(nil)
+	err := operation.ParseComment(`@Produce octet-stream,csv`, nil)
+	assert.NoError(t, err, "ParseComment should not fail")
+	err = operation.ParseComment(`@Success 200 {file} binary "the file"`, nil)
+	assert.NoError(t, err, "ParseComment should not fail")
+	b, _ := json.MarshalIndent(operation, "", "    ")
+
+	expected := `{
+    "produces": [
+        "application/octet-stream",
+        "text/csv"
+    ],
+    "responses": {
+        "200": {
+            "description": "the file",
+            "schema": {
+                "type": "string",
+                "format": "binary"
+            }
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+
+	err = operation.ParseComment(`@Success 200 {file} model.File "the file"`, nil)
+	assert.EqualError(t, err, "{file} can't reference the model model.File, its data type must be binary")
+}
+
+func TestParseResponseCommentWithArrayOfBasicType(t *testing.T) {
+	operation := NewOperation(nil)
+	err := operation.ParseComment(`@Success 200 {array} string "names"`, nil)
+	assert.NoError(t, err, "ParseComment should not fail")
+	err = operation.ParseComment(`@Success 201 {array} integer "ids"`, nil)
+	assert.NoError(t, err, "ParseComment should not fail")
+	err = operation.ParseComment(`@Success 202 {array} int64`, nil)
+	assert.NoError(t, err, "ParseComment should not fail")
+	b, _ := json.MarshalIndent(operation, "", "    ")
+
+	expected := `{
+    "responses": {
+        "200": {
+            "description": "names",
+            "schema": {
+                "type": "array",
+                "items": {
+                    "type": "string"
+                }
+            }
+        },
+        "201": {
+            "description": "ids",
+            "schema": {
+                "type": "array",
+                "items": {
+                    "type": "integer"
+                }
+            }
+        },
+        "202": {
+            "description": "Accepted",
+            "schema": {
+                "type": "array",
+                "items": {
+                    "type": "integer"
+                }
+            }
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+
+	operation = NewOperation(nil)
+	err = operation.ParseComment(`@Success 200 {array} number`, nil)
+	assert.NoError(t, err, "ParseComment should not fail")
+	assert.Equal(t, spec.StringOrArray{NUMBER}, operation.Responses.StatusCodeResponses[200].Schema.Items.Schema.Type)
+	err = operation.ParseComment(`@Success 200 {array} boolean`, nil)
+	assert.NoError(t, err, "ParseComment should not fail")
+	assert.Equal(t, spec.StringOrArray{BOOLEAN}, operation.Responses.StatusCodeResponses[200].Schema.Items.Schema.Type)
+}
+
 func TestParseResponseCommentWithBasicTypeAndCodes(t *testing.T) {
 	comment := `@Success 200,201,default {string} string "it's ok"`
 	operation := NewOperation(nil)
@@ -721,6 +1025,17 @@ func TestParseEmptyResponseComment(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseEmptyResponseCommentNoContent(t *testing.T) {
+	operation := NewOperation(New())
+	assert.NoError(t, operation.ParseComment(`@Success 204 "deleted"`, nil))
+	assert.NoError(t, operation.ParseComment(`@Failure 404 {string} string "not found"`, nil))
+
+	response := operation.Responses.StatusCodeResponses[204]
+	assert.Equal(t, "deleted", response.Description)
+	assert.Nil(t, response.Schema)
+	assert.NotNil(t, operation.Responses.StatusCodeResponses[404].Schema)
+}
+
 func TestParseEmptyResponseCommentWithCodes(t *testing.T) {
 	comment := `@Success 200,201,default "it is ok"`
 	operation := NewOperation(nil)
@@ -926,6 +1241,39 @@ func TestParseParamCommentByPathType(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseParamCommentByCookieType(t *testing.T) {
+	operation := NewOperation(nil)
+	assert.NoError(t, operation.ParseComment(`@Param session cookie string true "session id"`, nil))
+	assert.NoError(t, operation.ParseComment(`@Param theme cookie string false "color theme" Enums(light, dark)`, nil))
+
+	b, _ := json.MarshalIndent(operation, "", "    ")
+	expected := `{
+    "x-cookie": [
+        {
+            "type": "string",
+            "description": "session id",
+            "name": "session",
+            "in": "cookie",
+            "required": true
+        },
+        {
+            "enum": [
+                "light",
+                "dark"
+            ],
+            "type": "string",
+            "description": "color theme",
+            "name": "theme",
+            "in": "cookie"
+        }
+    ]
+}`
+	assert.Equal(t, expected, string(b))
+
+	err := operation.ParseComment(`@Param ids cookie []string true "ids"`, nil)
+	assert.EqualError(t, err, "string is not supported type for cookie")
+}
+
 // Test ParseParamComment Query Params
 func TestParseParamCommentBodyArray(t *testing.T) {
 	comment := `@Param names body []string true "Users List"`
@@ -1004,6 +1352,20 @@ func TestParseParamCommentQueryArrayFormat(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseParamCommentQueryArrayFormatPipes(t *testing.T) {
+	operation := NewOperation(New(SetCollectionFormat("multi")))
+	err := operation.ParseComment(`@Param names query []string true "Users List" collectionFormat(pipes)`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Param ids query []int true "IDs"`, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "pipes", operation.Parameters[0].CollectionFormat)
+	assert.Equal(t, "multi", operation.Parameters[1].CollectionFormat)
+
+	err = operation.ParseComment(`@Param names query []string true "Users List" collectionFormat(comma)`, nil)
+	assert.EqualError(t, err, `collectionFormat comma is not one of csv, ssv, tsv, pipes, multi. comment=names query []string true "Users List" collectionFormat(comma)`)
+}
+
 func TestParseParamCommentByID(t *testing.T) {
 	comment := `@Param unsafe_id[lte] query int true "Unsafe query param"`
 	operation := NewOperation(nil)
@@ -1258,6 +1620,21 @@ func TestParseParamCommentNotMatch(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParseParamCommentByEnumDescriptions(t *testing.T) {
+	comment := `@Param fruit query string true "a fruit" Enums(a, b, c) x-enum-descriptions(Apple, Banana, Cherry)`
+	operation := NewOperation(nil)
+	err := operation.ParseComment(comment, nil)
+	assert.NoError(t, err)
+
+	param := operation.Parameters[0]
+	assert.Equal(t, []interface{}{"a", "b", "c"}, param.Enum)
+	assert.Equal(t, []string{"Apple", "Banana", "Cherry"}, param.Extensions["x-enum-descriptions"])
+
+	comment = `@Param fruit query string true "a fruit" Enums(a, b, c) x-enum-descriptions(Apple, Banana)`
+	err = NewOperation(nil).ParseComment(comment, nil)
+	assert.EqualError(t, err, "x-enum-descriptions of param fruit has 2 descriptions for 3 enums")
+}
+
 func TestParseParamCommentByEnums(t *testing.T) {
 	comment := `@Param some_id query string true "Some ID" Enums(A, B, C)`
 	operation := NewOperation(nil)
@@ -1510,6 +1887,36 @@ func TestParseParamCommentByDefault(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseParamCommentByDeprecatedAndExample(t *testing.T) {
+	comment := `@Param oldId query int false "legacy id, deprecated soon" deprecated example(123)`
+	operation := NewOperation(nil)
+	err := operation.ParseComment(comment, nil)
+
+	assert.NoError(t, err)
+	b, _ := json.MarshalIndent(operation, "", "    ")
+	expected := `{
+    "parameters": [
+        {
+            "type": "integer",
+            "x-deprecated": true,
+            "x-example": 123,
+            "description": "legacy id, deprecated soon",
+            "name": "oldId",
+            "in": "query"
+        }
+    ]
+}`
+	assert.Equal(t, expected, string(b))
+
+	operation = NewOperation(nil)
+	err = operation.ParseComment(`@Param newId query int false "id, not deprecated"`, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, operation.Parameters[0].Extensions)
+
+	err = operation.ParseComment(`@Param page query int false "page" example(first)`, nil)
+	assert.Error(t, err)
+}
+
 func TestParseIdComment(t *testing.T) {
 	comment := `@Id myOperationId`
 	operation := NewOperation(nil)
@@ -1558,6 +1965,44 @@ func TestParseSecurityComment(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseSecurityCommentCombinations(t *testing.T) {
+	operation := NewOperation(nil)
+
+	err := operation.ParseComment(`@Security ApiKey && OAuth2[read,write]`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Security BasicAuth`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Security OAuth2[ admin ]`, nil)
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(operation, "", "    ")
+	expected := `{
+    "security": [
+        {
+            "ApiKey": [],
+            "OAuth2": [
+                "read",
+                "write"
+            ]
+        },
+        {
+            "BasicAuth": []
+        },
+        {
+            "OAuth2": [
+                "admin"
+            ]
+        }
+    ]
+}`
+	assert.Equal(t, expected, string(b))
+
+	for _, comment := range []string{`@Security ApiKey &&`, `@Security OAuth2[read`, `@Security ApiKey OAuth2`} {
+		err = NewOperation(nil).ParseComment(comment, nil)
+		assert.Error(t, err, comment)
+	}
+}
+
 func TestParseMultiDescription(t *testing.T) {
 	comment := `@Description line one`
 	operation := NewOperation(nil)
@@ -1579,6 +2024,37 @@ func TestParseMultiDescription(t *testing.T) {
 	assert.Contains(t, string(b), expected)
 }
 
+func TestParseDescriptionWithCodeBlock(t *testing.T) {
+	comments := []string{
+		"// @Description Creates an order.",
+		"//",
+		"// Example request:",
+		"//",
+		"// ```json",
+		"// {",
+		"//     \"items\": [1, 2]",
+		"// }",
+		"// ```",
+		"//",
+		"// | status | meaning |",
+		"// |--------|---------|",
+		"// | 201    | created |",
+		"//",
+		"// @Tags orders",
+		"// ignored line",
+	}
+	operation := NewOperation(nil)
+	for _, comment := range comments {
+		err := operation.ParseComment(comment, nil)
+		assert.NoError(t, err)
+	}
+
+	expected := "Creates an order.\n\nExample request:\n\n```json\n{\n    \"items\": [1, 2]\n}\n```\n\n" +
+		"| status | meaning |\n|--------|---------|\n| 201    | created |"
+	assert.Equal(t, expected, operation.Description)
+	assert.Equal(t, []string{"orders"}, operation.Tags)
+}
+
 func TestParseSummary(t *testing.T) {
 	comment := `@summary line one`
 	operation := NewOperation(nil)
@@ -1698,3 +2174,350 @@ func TestParseCodeSamples(t *testing.T) {
 		assert.Error(t, err, "error was expected, as file does not exist")
 	})
 }
+
+func TestParseCodeSampleBlocks(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	comments := []string{
+		"// @x-codeSample curl",
+		"// ```",
+		"// curl -X POST \\",
+		"//   https://example.com/users",
+		"// ```",
+		"// @x-codeSample Go",
+		"//     client := NewClient()",
+		"//     if err := client.CreateUser(); err != nil {",
+		"//         panic(err)",
+		"//     }",
+		"// @Success 200",
+	}
+	for _, comment := range comments {
+		assert.NoError(t, operation.ParseComment(comment, nil))
+	}
+	assert.NoError(t, operation.checkPendingExtension())
+
+	b, _ := json.MarshalIndent(operation.Extensions["x-codeSamples"], "", "    ")
+	expected := `[
+    {
+        "lang": "curl",
+        "source": "curl -X POST \\\n  https://example.com/users"
+    },
+    {
+        "lang": "Go",
+        "source": "client := NewClient()\nif err := client.CreateUser(); err != nil {\n    panic(err)\n}"
+    }
+]`
+	assert.Equal(t, expected, string(b))
+	assert.Contains(t, operation.Responses.StatusCodeResponses, 200)
+
+	operation = NewOperation(nil)
+	assert.EqualError(t, operation.ParseComment("// @x-codeSample", nil), "annotation @x-codeSample need a language")
+	assert.NoError(t, operation.ParseComment("// @x-codeSample curl", nil))
+	assert.NoError(t, operation.ParseComment("// ```", nil))
+	assert.NoError(t, operation.ParseComment("// curl https://example.com", nil))
+	assert.EqualError(t, operation.checkPendingExtension(), "code sample curl need a closing ```")
+}
+
+func TestParseCodeSampleFiles(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil, SetCodeExampleFilesDirectory("testdata/code_samples"))
+	assert.NoError(t, operation.ParseComment("// @x-codeSample.file curl create_user", nil))
+	assert.NoError(t, operation.ParseComment("// @x-codeSample.file list_users.py", nil))
+	assert.NoError(t, operation.ParseComment("// @x-codeSample.file Python list_users.py", nil))
+
+	expected := []interface{}{
+		map[string]interface{}{"lang": "curl", "source": "curl -X POST \\\n  -d '{\"name\": \"John\"}' \\\n  https://example.com/users"},
+		map[string]interface{}{"lang": "py", "source": "import requests\n\nrequests.get(\"https://example.com/users\")"},
+		map[string]interface{}{"lang": "Python", "source": "import requests\n\nrequests.get(\"https://example.com/users\")"},
+	}
+	assert.Equal(t, expected, operation.Extensions["x-codeSamples"])
+
+	assert.EqualError(t, operation.ParseComment("// @x-codeSample.file create_user", nil),
+		"annotation @x-codeSample.file need a language, before the file name or as its extension")
+	assert.EqualError(t, operation.ParseComment("// @x-codeSample.file Go delete_user", nil),
+		"cannot find the code sample file testdata/code_samples/delete_user.Go")
+}
+
+func TestParseResponseCommentWithProduce(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	assert.NoError(t, operation.ParseComment(`@Produce json`, nil))
+	assert.NoError(t, operation.ParseComment(`@Failure 400,default {string} string "bad request" produce(plain)`, nil))
+	assert.NoError(t, operation.ParseComment(`@Success 200 {string} string "ok"`, nil))
+
+	resp := operation.Responses.StatusCodeResponses[400]
+	assert.Equal(t, "bad request", resp.Description)
+	assert.Equal(t, []string{"text/plain"}, resp.Extensions["x-produces"])
+	assert.Equal(t, []string{"text/plain"}, operation.Responses.Default.Extensions["x-produces"])
+	assert.Empty(t, operation.Responses.StatusCodeResponses[200].Extensions)
+
+	assert.EqualError(t, operation.ParseComment(`@Failure 500 {string} string "error" produce(unknown)`, nil),
+		"unknown produce type can't be accepted"+mimeTypeAliasesHint)
+}
+
+func TestParseResponseCommentWithProduceWithoutDescription(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	assert.NoError(t, operation.ParseComment(`@Failure 400 {string} string produce(text/plain)`, nil))
+
+	resp := operation.Responses.StatusCodeResponses[400]
+	assert.Equal(t, "Bad Request", resp.Description)
+	assert.Equal(t, []string{"text/plain"}, resp.Extensions["x-produces"])
+}
+
+func TestParseLocalizedComment(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	for _, comment := range []string{
+		"@Summary Get a user",
+		"@Summary[ja] ユーザー取得",
+		"@Summary[pt-BR] Obter um usuário",
+		"@Description[ja] 一行目",
+		"@Description[ja] 二行目",
+	} {
+		assert.NoError(t, operation.ParseComment(comment, nil))
+	}
+
+	assert.Equal(t, "Get a user", operation.Summary)
+	assert.Equal(t, "ユーザー取得", operation.Extensions["x-summary-ja"])
+	assert.Equal(t, "Obter um usuário", operation.Extensions["x-summary-pt-br"])
+	assert.Equal(t, "一行目\n二行目", operation.Extensions["x-description-ja"])
+	assert.Empty(t, operation.Description)
+}
+
+func TestParseResponseCommentWithInlineExample(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	assert.NoError(t, operation.ParseComment(`@Success 200 {object} object "ok (a row)" example({"id": 1, "name": "pen (blue)"})`, nil))
+	assert.NoError(t, operation.ParseComment(`@Failure 404 {string} string "not found" produce(plain) example("missing")`, nil))
+
+	resp := operation.Responses.StatusCodeResponses[200]
+	assert.Equal(t, "ok (a row)", resp.Description)
+	assert.Equal(t, map[string]interface{}{
+		"application/json": map[string]interface{}{"id": float64(1), "name": "pen (blue)"},
+	}, resp.Examples)
+	assert.Equal(t, map[string]interface{}{"text/plain": "missing"}, operation.Responses.StatusCodeResponses[404].Examples)
+
+	assert.EqualError(t, operation.ParseComment(`@Failure 500 {object} object "error" example({"id": 1,})`, nil),
+		`example {"id": 1,} of the response is neither declared by @example.named nor a valid json value: invalid character '}' looking for beginning of object key string`)
+}
+
+func TestParseResponseCommentWithInlineExampleWithoutDescription(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	assert.NoError(t, operation.ParseComment(`@Success 200 {object} object{id=int} example({"id":1})`, nil))
+
+	resp := operation.Responses.StatusCodeResponses[200]
+	assert.Equal(t, "OK", resp.Description)
+	assert.Equal(t, map[string]interface{}{"application/json": map[string]interface{}{"id": float64(1)}}, resp.Examples)
+}
+
+func TestParseResponseCommentWithHeaderAccumulated(t *testing.T) {
+	operation := NewOperation(nil)
+
+	err := operation.ParseComment(`@Success 201 "created"`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Header 201 {string} Location "/users/1"`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Header 201 {integer} X-Rate-Limit "requests remaining"`, nil)
+	assert.NoError(t, err)
+
+	headers := operation.Responses.StatusCodeResponses[201].Headers
+	assert.Len(t, headers, 2)
+	assert.Equal(t, "string", headers["Location"].Type)
+	assert.Equal(t, "integer", headers["X-Rate-Limit"].Type)
+	assert.Equal(t, "requests remaining", headers["X-Rate-Limit"].Description)
+}
+
+func TestParseResponseCommentWithHeaderForMissingResponse(t *testing.T) {
+	operation := NewOperation(nil)
+	err := operation.ParseComment(`@Header all {string} Token "qwerty"`, nil)
+	assert.NoError(t, err)
+	assert.EqualError(t, operation.applyResponseHeaders(nil), `can not find any response for @Header "all {string} Token "qwerty""`)
+
+	operation = NewOperation(nil)
+	err = operation.ParseComment(`@Success 200 "it's ok"`, nil)
+	assert.NoError(t, err)
+
+	err = operation.ParseComment(`@Header 400 {string} Token "qwerty"`, nil)
+	assert.NoError(t, err)
+	assert.EqualError(t, operation.applyResponseHeaders(nil), `can not find response 400 for @Header "400 {string} Token "qwerty""`)
+
+	operation = NewOperation(nil)
+	err = operation.ParseComment(`@Success 200 "it's ok"`, nil)
+	assert.NoError(t, err)
+
+	err = operation.ParseComment(`@Header default {string} Token "qwerty"`, nil)
+	assert.NoError(t, err)
+	assert.EqualError(t, operation.applyResponseHeaders(nil), `can not find default response for @Header "default {string} Token "qwerty""`)
+}
+
+func TestParseResponseCommentWithHeaderBeforeResponse(t *testing.T) {
+	operation := NewOperation(nil)
+	for _, comment := range []string{
+		`@Header 200 {string} Token "qwerty"`,
+		`@Header all {string} X-Request-Id "request id"`,
+		`@Success 200 "it's ok"`,
+		`@Failure 400 "bad request"`,
+	} {
+		assert.NoError(t, operation.ParseComment(comment, nil))
+	}
+	assert.NoError(t, operation.applyResponseHeaders(nil))
+
+	headers := operation.Responses.StatusCodeResponses[200].Headers
+	assert.Len(t, headers, 2)
+	assert.Equal(t, "qwerty", headers["Token"].Description)
+	assert.Equal(t, "request id", headers["X-Request-Id"].Description)
+
+	headers = operation.Responses.StatusCodeResponses[400].Headers
+	assert.Len(t, headers, 1)
+	assert.Equal(t, "request id", headers["X-Request-Id"].Description)
+}
+
+func TestParseResponseCommentWithHeaderForDefaultResponse(t *testing.T) {
+	defaults := &spec.Responses{}
+	defaults.StatusCodeResponses = map[int]spec.Response{
+		500: *spec.NewResponse().WithDescription("error"),
+	}
+
+	operation := NewOperation(nil)
+	for _, comment := range []string{
+		`@Success 200 "it's ok"`,
+		`@Header 500 {string} Token "qwerty"`,
+	} {
+		assert.NoError(t, operation.ParseComment(comment, nil))
+	}
+	assert.NoError(t, operation.applyResponseHeaders(defaults))
+
+	response := operation.Responses.StatusCodeResponses[500]
+	assert.Equal(t, "error", response.Description)
+	assert.Equal(t, "qwerty", response.Headers["Token"].Description)
+	assert.Empty(t, defaults.StatusCodeResponses[500].Headers)
+}
+
+func TestParseParamCommentByMultipleAttributes(t *testing.T) {
+	comment := `@Param limit query int false "page size" default(20) minimum(1) maximum(100) enums(10,20,50,100)`
+	operation := NewOperation(nil)
+	err := operation.ParseComment(comment, nil)
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(operation, "", "    ")
+	expected := `{
+    "parameters": [
+        {
+            "maximum": 100,
+            "minimum": 1,
+            "enum": [
+                10,
+                20,
+                50,
+                100
+            ],
+            "type": "integer",
+            "default": 20,
+            "description": "page size",
+            "name": "limit",
+            "in": "query"
+        }
+    ]
+}`
+	assert.Equal(t, expected, string(b))
+
+	comment = `@Param id path string true "id" default(abc) minlength(3) maxlength(8)`
+	operation = NewOperation(nil)
+	err = operation.ParseComment(comment, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", operation.Parameters[0].Default)
+	assert.Equal(t, int64(3), *operation.Parameters[0].MinLength)
+	assert.Equal(t, int64(8), *operation.Parameters[0].MaxLength)
+
+	comment = `@Param limit query int false "page size" default(twenty)`
+	operation = NewOperation(nil)
+	err = operation.ParseComment(comment, nil)
+	assert.Error(t, err)
+}
+
+func TestParseParamCommentByEnumType(t *testing.T) {
+	src := `
+package model
+
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusBlocked Status = "blocked"
+)
+
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+type Name string
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	parser := New()
+	parser.packages.CollectAstFile("model", "model.go", f)
+	_, err = parser.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	operation := NewOperation(parser)
+	err = operation.ParseComment(`@Param status query string true "status" enumType(model.Status)`, f)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Param level query int false "level" enumType(Level)`, f)
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(operation.Parameters, "", "    ")
+	expected := `[
+    {
+        "enum": [
+            "active",
+            "blocked"
+        ],
+        "type": "string",
+        "x-enum-varnames": [
+            "StatusActive",
+            "StatusBlocked"
+        ],
+        "description": "status",
+        "name": "status",
+        "in": "query",
+        "required": true
+    },
+    {
+        "enum": [
+            0,
+            1
+        ],
+        "type": "integer",
+        "x-enum-varnames": [
+            "LevelLow",
+            "LevelHigh"
+        ],
+        "description": "level",
+        "name": "level",
+        "in": "query"
+    }
+]`
+	assert.Equal(t, expected, string(b))
+
+	err = NewOperation(parser).ParseComment(`@Param level query string false "level" enumType(Level)`, f)
+	assert.EqualError(t, err, "enum type Level holds numbers, but the param is string")
+
+	err = NewOperation(parser).ParseComment(`@Param name query string false "name" enumType(Name)`, f)
+	assert.EqualError(t, err, "type Name has no enum values")
+
+	err = NewOperation(parser).ParseComment(`@Param name query string false "name" enumType(model.Missing)`, f)
+	assert.EqualError(t, err, "can not find enum type model.Missing")
+}
@@ -3,6 +3,7 @@ package swag
 import (
 	"go/ast"
 	"go/token"
+	"sort"
 	"strings"
 )
 
@@ -53,10 +54,21 @@ func (pkgs *PackagesDefinitions) CollectAstFile(packageDir, path string, astFile
 	}
 }
 
-//RangeFiles for range the collection of ast.File
+//RangeFiles for range the collection of ast.File, sorted by path so that the result doesn't depend on the parsing order
 func (pkgs *PackagesDefinitions) RangeFiles(handle func(filename string, file *ast.File) error) error {
-	for file, info := range pkgs.files {
-		if err := handle(info.Path, file); err != nil {
+	infos := make([]*AstFileInfo, 0, len(pkgs.files))
+	for _, info := range pkgs.files {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Path != infos[j].Path {
+			return infos[i].Path < infos[j].Path
+		}
+		return infos[i].PackagePath < infos[j].PackagePath
+	})
+
+	for _, info := range infos {
+		if err := handle(info.Path, info.File); err != nil {
 			return err
 		}
 	}
@@ -69,9 +81,21 @@ func (pkgs *PackagesDefinitions) ParseTypes() (map[*TypeSpecDef]*Schema, error)
 	parsedSchemas := make(map[*TypeSpecDef]*Schema)
 	for astFile, info := range pkgs.files {
 		for _, astDeclaration := range astFile.Decls {
+			if funcDecl, ok := astDeclaration.(*ast.FuncDecl); ok {
+				if funcDecl.Name.Name == swaggerSchemaMethod {
+					pkgs.collectSchemaMethod(info.PackagePath, funcDecl)
+				}
+				pkgs.collectMethod(info.PackagePath, funcDecl)
+				continue
+			}
 			if generalDeclaration, ok := astDeclaration.(*ast.GenDecl); ok && generalDeclaration.Tok == token.TYPE {
 				for _, astSpec := range generalDeclaration.Specs {
 					if typeSpec, ok := astSpec.(*ast.TypeSpec); ok {
+						if typeSpec.Doc == nil && len(generalDeclaration.Specs) == 1 {
+							// the doc of an ungrouped type declaration belongs to the GenDecl
+							typeSpec.Doc = generalDeclaration.Doc
+						}
+
 						typeSpecDef := &TypeSpecDef{
 							PkgPath:  info.PackagePath,
 							File:     astFile,
@@ -79,10 +103,13 @@ func (pkgs *PackagesDefinitions) ParseTypes() (map[*TypeSpecDef]*Schema, error)
 						}
 
 						if idt, ok := typeSpec.Type.(*ast.Ident); ok && IsGolangPrimitiveType(idt.Name) {
+							// a named type over a primitive type, like type UserID int64, is documented as that primitive
+							schema := PrimitiveSchema(TransToValidSchemeType(idt.Name))
+							schema.Format = TransToValidSchemeFormat(idt.Name)
 							parsedSchemas[typeSpecDef] = &Schema{
 								PkgPath: typeSpecDef.PkgPath,
 								Name:    astFile.Name.Name,
-								Schema:  PrimitiveSchema(TransToValidSchemeType(idt.Name)),
+								Schema:  schema,
 							}
 						}
 
@@ -108,9 +135,47 @@ func (pkgs *PackagesDefinitions) ParseTypes() (map[*TypeSpecDef]*Schema, error)
 			}
 		}
 	}
+	pkgs.parseEnums()
 	return parsedSchemas, nil
 }
 
+// collectSchemaMethod records a SwaggerSchema method declared in the package pkgPath
+func (pkgs *PackagesDefinitions) collectSchemaMethod(pkgPath string, funcDecl *ast.FuncDecl) {
+	recvName := receiverTypeName(funcDecl)
+	pd, ok := pkgs.packages[pkgPath]
+	if recvName == "" || !ok {
+		return
+	}
+	if pd.schemaMethods == nil {
+		pd.schemaMethods = make(map[string]*ast.FuncDecl)
+	}
+	pd.schemaMethods[recvName] = funcDecl
+}
+
+// collectMethod records the name of a method declared in the package pkgPath under its receiver type
+func (pkgs *PackagesDefinitions) collectMethod(pkgPath string, funcDecl *ast.FuncDecl) {
+	recvName := receiverTypeName(funcDecl)
+	pd, ok := pkgs.packages[pkgPath]
+	if recvName == "" || !ok {
+		return
+	}
+	if pd.methods == nil {
+		pd.methods = make(map[string]map[string]bool)
+	}
+	if pd.methods[recvName] == nil {
+		pd.methods[recvName] = make(map[string]bool)
+	}
+	pd.methods[recvName][funcDecl.Name.Name] = true
+}
+
+// findSchemaMethod returns the SwaggerSchema method of a type, nil if it doesn't declare one
+func (pkgs *PackagesDefinitions) findSchemaMethod(typeSpecDef *TypeSpecDef) *ast.FuncDecl {
+	if pd, ok := pkgs.packages[typeSpecDef.PkgPath]; ok {
+		return pd.schemaMethods[typeSpecDef.Name()]
+	}
+	return nil
+}
+
 func (pkgs *PackagesDefinitions) findTypeSpec(pkgPath string, typeName string) *TypeSpecDef {
 	if pkgs.packages != nil {
 		if pd, ok := pkgs.packages[pkgPath]; ok {
@@ -174,12 +239,42 @@ func (pkgs *PackagesDefinitions) findPackagePathFromImports(pkg string, file *as
 	return ""
 }
 
-// FindTypeSpec finds out TypeSpecDef of a type by typeName
+// FindTypeSpec finds out TypeSpecDef of a type by typeName, following the aliases of named types
 // @typeName the name of the target type, if it starts with a package name, find its own package path from imports on top of @file
 // @file the ast.file in which @typeName is used
 // @pkgPath the package path of @file
 func (pkgs *PackagesDefinitions) FindTypeSpec(typeName string, file *ast.File) *TypeSpecDef {
-	if IsGolangPrimitiveType(typeName) {
+	typeSpecDef := pkgs.findTypeSpecByName(typeName, file)
+	seen := make(map[*TypeSpecDef]bool)
+	for typeSpecDef != nil && typeSpecDef.TypeSpec != nil && typeSpecDef.TypeSpec.Assign.IsValid() && !seen[typeSpecDef] {
+		seen[typeSpecDef] = true
+		aliased := pkgs.findTypeSpecByName(aliasedTypeName(typeSpecDef.TypeSpec), typeSpecDef.File)
+		if aliased == nil {
+			// an alias of a primitive, of a type out of the parsed packages or of a type literal
+			break
+		}
+		typeSpecDef = aliased
+	}
+	return typeSpecDef
+}
+
+// aliasedTypeName returns the name of the type an alias declaration like 'type Foo = pkg.Bar' stands for, an empty
+// string if it stands for a type literal
+func aliasedTypeName(typeSpec *ast.TypeSpec) string {
+	switch expr := typeSpec.Type.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok {
+			return fullTypeName(pkg.Name, expr.Sel.Name)
+		}
+	}
+	return ""
+}
+
+// findTypeSpecByName finds out TypeSpecDef of a type by typeName, as it's declared
+func (pkgs *PackagesDefinitions) findTypeSpecByName(typeName string, file *ast.File) *TypeSpecDef {
+	if typeName == "" || IsGolangPrimitiveType(typeName) {
 		return nil
 	} else if file == nil { // for test
 		return pkgs.uniqueDefinitions[typeName]
@@ -232,3 +327,99 @@ func (pkgs *PackagesDefinitions) FindTypeSpec(typeName string, file *ast.File) *
 
 	return nil
 }
+
+// FindFuncDecl finds out the declaration of a function or a method by funcName
+// @funcName the name of the target function like Func, pkg.Func, Type.Method or pkg.Type.Method
+// @file the ast.File in which @funcName is used
+// @return the declaration and the ast.File in which it is declared
+func (pkgs *PackagesDefinitions) FindFuncDecl(funcName string, file *ast.File) (*ast.FuncDecl, *ast.File) {
+	if file == nil || pkgs.files[file] == nil {
+		return nil, nil
+	}
+
+	parts := strings.Split(funcName, ".")
+	pkgPath := pkgs.files[file].PackagePath
+	if len(parts) > 1 {
+		if path := pkgs.findPackagePathFromImports(parts[0], file); path != "" {
+			pkgPath = path
+			parts = parts[1:]
+		}
+	}
+
+	var recvName string
+	switch len(parts) {
+	case 1:
+	case 2:
+		recvName = parts[0]
+	default:
+		return nil, nil
+	}
+
+	pd, ok := pkgs.packages[pkgPath]
+	if !ok {
+		return nil, nil
+	}
+	for _, astFile := range pd.Files {
+		for _, decl := range astFile.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Name.Name != parts[len(parts)-1] || receiverTypeName(funcDecl) != recvName {
+				continue
+			}
+			return funcDecl, astFile
+		}
+	}
+	return nil, nil
+}
+
+// receiverTypeName returns the name of the receiver type of a method, or an empty string for a function
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	expr := funcDecl.Recv.List[0].Type
+	if starExpr, ok := expr.(*ast.StarExpr); ok {
+		expr = starExpr.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// findImplementers returns the types of the scanned packages, other than interfaces, declaring all the given
+// methods, sorted by package path and name
+func (pkgs *PackagesDefinitions) findImplementers(methods []string) []*TypeSpecDef {
+	pkgPaths := make([]string, 0, len(pkgs.packages))
+	for pkgPath := range pkgs.packages {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+
+	var implementers []*TypeSpecDef
+	for _, pkgPath := range pkgPaths {
+		pd := pkgs.packages[pkgPath]
+		names := make([]string, 0, len(pd.methods))
+		for name := range pd.methods {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+	types:
+		for _, name := range names {
+			typeSpecDef, ok := pd.TypeDefinitions[name]
+			if !ok {
+				continue
+			}
+			if _, ok = typeSpecDef.TypeSpec.Type.(*ast.InterfaceType); ok {
+				continue
+			}
+			for _, method := range methods {
+				if !pd.methods[name][method] {
+					continue types
+				}
+			}
+			implementers = append(implementers, typeSpecDef)
+		}
+	}
+	return implementers
+}
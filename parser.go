@@ -1,6 +1,7 @@
 package swag
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,16 +9,21 @@ import (
 	"go/build"
 	goparser "go/parser"
 	"go/token"
+	"io/fs"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/KyleBanks/depth"
@@ -33,6 +39,13 @@ const (
 
 	// SnakeCase indicates using SnakeCase strategy for struct field.
 	SnakeCase = "snakecase"
+
+	// ShortDefinitionNaming names a definition after the package and the type, like model.User.
+	ShortDefinitionNaming = "short"
+
+	// FullDefinitionNaming names a definition after the import path of the package and the type, the slashes being
+	// replaced by underscores, like github.com_org_app_model.User.
+	FullDefinitionNaming = "full"
 )
 
 var (
@@ -71,6 +84,24 @@ type Parser struct {
 
 	PropNamingStrategy string
 
+	// DefinitionNaming is ShortDefinitionNaming or FullDefinitionNaming. When it's set, two types given the same
+	// definition name are an error instead of having the first one renamed after the import path of its package
+	DefinitionNaming string
+
+	// ModelNamer returns the definition name of the type typeName declared in the package pkgPath, overriding
+	// DefinitionNaming. The name given by a @name comment is kept
+	ModelNamer func(pkgPath, typeName string) string
+
+	// NameResolver returns the property name of a struct field given its name and its tag, overriding the json tag
+	// and PropNamingStrategy when set. An empty name skips the field
+	NameResolver func(fieldName string, tag reflect.StructTag) string
+
+	// FileSystem the sources are read from instead of the disk when it's set, the search dirs and the main api file
+	// being paths within it, like the @description.file and the code sample files. The package paths come from its
+	// go.mod files, ParseDependency isn't supported
+	FileSystem fs.FS
+
+	// ParseVendor whether swag should parse the vendor folder, resolving the types of the vendored packages
 	ParseVendor bool
 
 	// ParseDependencies whether swag should be parse outside dependency folder
@@ -79,9 +110,115 @@ type Parser struct {
 	// ParseInternal whether swag should parse internal packages
 	ParseInternal bool
 
-	// structStack stores full names of the structures that were already parsed or are being parsed now
+	// ParseGoList whether swag should find the dependencies with go list instead of resolving the import tree,
+	// only the packages imported by the main package are parsed and parseDepth is ignored
+	ParseGoList bool
+
+	// ParseGeneralInfoAcrossDir whether swag should look for the general api info in every file of the search dirs
+	// instead of the main api file only
+	ParseGeneralInfoAcrossDir bool
+
+	// SwagDirectiveStyle whether swag should also recognize annotations written as //swag:xxx directives
+	SwagDirectiveStyle bool
+
+	// EmitMsEnum whether swag should add the x-ms-enum extension of AutoRest to enums of named types
+	EmitMsEnum bool
+
+	// DefaultOperationID whether swag should use the name of the handler function as operationId when @ID is absent
+	DefaultOperationID bool
+
+	// Int64AsString whether swag should document int64 and uint64 as strings, since large json numbers lose
+	// precision in some clients
+	Int64AsString bool
+
+	// RequiredByDefault whether swag should mark every field required unless it's tagged optional, like with
+	// json:",omitempty", binding:"-" or validate:"omitempty"
+	RequiredByDefault bool
+
+	// Nullable whether swag should add the x-nullable extension to pointer fields
+	Nullable bool
+
+	// PruneUnusedDefinitions whether swag should remove the definitions not referenced by any operation, directly or
+	// through other definitions
+	PruneUnusedDefinitions bool
+
+	// DedupeInlineSchemas whether swag should replace the identical inline objects found more than once by a $ref
+	// to a shared definition named InlineSchema1, InlineSchema2...
+	DedupeInlineSchemas bool
+
+	// InlineSingleUse whether swag should replace the $ref of the definitions referenced exactly once by
+	// their schema and remove them, except the recursive ones
+	InlineSingleUse bool
+
+	// ResolveInterfaces whether swag should document the interface types as one of the types implementing them in
+	// the scanned packages, listed by the x-oneOf extension, rather than as any value
+	ResolveInterfaces bool
+
+	// IncludeHidden whether swag should keep the operations annotated with @Hidden, which are left out by default
+	// along with the definitions only they use
+	IncludeHidden bool
+
+	// DefaultSchemes are the schemes of the spec when the general api info lacks @schemes
+	DefaultSchemes []string
+
+	// DefaultConsumes are the mime types, or their aliases like json, consumed by the spec and by the operations
+	// lacking @Accept
+	DefaultConsumes []string
+
+	// DefaultProduces are the mime types, or their aliases like json, produced by the spec and by the operations
+	// lacking @Produce
+	DefaultProduces []string
+
+	// Locale selects the summary and the description written by @Summary[locale] and @Description[locale], like ja,
+	// as the ones of the operations having them. They're kept as the x-summary-<locale> and x-description-<locale>
+	// extensions of the operations either way
+	Locale string
+
+	// Parallelism is the number of the Go source files parsed at once, GOMAXPROCS when it's zero or less. The spec
+	// doesn't depend on it
+	Parallelism int
+
+	// InferResponses whether swag should infer the 2xx responses of the Gin handlers lacking them from their
+	// c.JSON(code, obj) calls, on a best-effort basis
+	InferResponses bool
+
+	// ContinueOnError whether swag should skip the files failing to parse, logging their error, instead of failing.
+	// An error listing them is returned only if no file could be parsed
+	ContinueOnError bool
+
+	// PrefixAnnotation whether swag should prepend the path declared by a file level @RouterPrefix annotation to the
+	// @Router paths of the file, like the group of a router
+	PrefixAnnotation bool
+
+	// DurationType the type documenting time.Duration, either integer holding nanoseconds, the default, or string
+	DurationType string
+
+	// AliasTypes maps full type names like decimal.Decimal to the type documented instead, written like the
+	// swaggertype tag with an optional format after a colon, like primitive,number:double
+	AliasTypes map[string]string
+
+	// TypeOverrides maps type names to the schema documenting them, consulted before anything else. A type is looked
+	// up by the import path of its package followed by its name, like github.com/google/uuid.UUID, then by its
+	// package name followed by its name, like uuid.UUID
+	TypeOverrides map[string]*spec.Schema
+
+	// ExternalSpecs maps the names used in external://name#/definitions/Type to the json or yaml file of the spec,
+	// relative to the search dir
+	ExternalSpecs map[string]string
+
+	// externalSpecs holds the external specs read so far, by name
+	externalSpecs map[string]*spec.Swagger
+
+	// importedSchemas holds the names of the definitions copied from the external specs
+	importedSchemas map[string]bool
+
+	// structStack stores the type definitions that are being parsed now, a type found in it is referenced
+	// by $ref instead of being parsed again
 	structStack []*TypeSpecDef
 
+	// searchDir is the first search dir, the files referenced by annotations are relative to it
+	searchDir string
+
 	// markdownFileDir holds the path to the folder, where markdown files are stored
 	markdownFileDir string
 
@@ -91,8 +228,49 @@ type Parser struct {
 	// collectionFormatInQuery set the default collectionFormat otherwise then 'csv' for array in query params
 	collectionFormatInQuery string
 
-	// excludes excludes dirs and files in SearchDir
+	// excludes paths or glob patterns of dirs and files to skip, relative to SearchDir
 	excludes map[string]bool
+
+	// tags filters the operations by their tags, true includes the tag and false excludes it
+	tags map[string]bool
+
+	// debug logs the progress of parsing, nil means no output
+	debug Debugger
+
+	// fileSet holds the positions of the parsed files
+	fileSet *token.FileSet
+
+	// routes maps the method and path of every operation to the handler declaring it
+	routes map[string]routeHandler
+
+	// defaultResponseComments holds the @failure.default annotations of the general api info
+	defaultResponseComments []generalComment
+
+	// defaultResponses are the responses added to the operations lacking a response of the same code
+	defaultResponses *spec.Responses
+
+	// skippedFiles maps the files skipped because of ContinueOnError to their error
+	skippedFiles map[string]error
+
+	// namedExamples maps the names declared with @example.named to their decoded json value
+	namedExamples map[string]interface{}
+
+	// hiddenOperations holds the operations annotated with @Hidden
+	hiddenOperations map[*spec.Operation]bool
+}
+
+// generalComment is an annotation of the general api info parsed after the types
+type generalComment struct {
+	fileName string
+	value    string
+}
+
+// routeHandler is the function declaring an operation
+type routeHandler struct {
+	// id identifies the function even if its file is parsed several times
+	id string
+	// location is the name and the position of the function
+	location string
 }
 
 // New creates a new Parser with default properties.
@@ -121,6 +299,11 @@ func New(options ...func(*Parser)) *Parser {
 		existSchemaNames:   make(map[string]*Schema),
 		toBeRenamedSchemas: make(map[string]string),
 		excludes:           make(map[string]bool),
+		tags:               make(map[string]bool),
+		externalSpecs:      make(map[string]*spec.Swagger),
+		importedSchemas:    make(map[string]bool),
+		debug:              NewLogger(LogLevelInfo),
+		fileSet:            token.NewFileSet(),
 	}
 
 	for _, option := range options {
@@ -144,7 +327,23 @@ func SetCodeExamplesDirectory(directoryPath string) func(*Parser) {
 	}
 }
 
-// SetExcludedDirsAndFiles sets directories and files to be excluded when searching
+// SetCollectionFormat sets the default collectionFormat of array params in query, overridden by @query.collection.format.
+func SetCollectionFormat(format string) func(*Parser) {
+	return func(p *Parser) {
+		p.collectionFormatInQuery = format
+	}
+}
+
+// SetDebugger sets the Debugger the parser logs its progress through, nil disables logging.
+func SetDebugger(debugger Debugger) func(*Parser) {
+	return func(p *Parser) {
+		p.debug = debugger
+	}
+}
+
+// SetExcludedDirsAndFiles sets directories and files to be excluded when searching, comma separated.
+// Each one is a path or a glob pattern relative to the search dir, the excluded directories are skipped
+// when resolving dependencies as well.
 func SetExcludedDirsAndFiles(excludes string) func(*Parser) {
 	return func(p *Parser) {
 		for _, f := range strings.Split(excludes, ",") {
@@ -157,44 +356,102 @@ func SetExcludedDirsAndFiles(excludes string) func(*Parser) {
 	}
 }
 
+// SetTags sets the tags filtering the operations, comma separated. Only the operations having one of the tags are
+// kept, and a tag starting with ! removes the operations having it instead.
+func SetTags(include string) func(*Parser) {
+	return func(p *Parser) {
+		for _, tag := range strings.Split(include, ",") {
+			tag = strings.TrimSpace(tag)
+			if strings.HasPrefix(tag, "!") {
+				p.tags[strings.TrimSpace(tag[1:])] = false
+			} else if tag != "" {
+				p.tags[tag] = true
+			}
+		}
+	}
+}
+
 // ParseAPI parses general api info for given searchDir and mainAPIFile
 func (parser *Parser) ParseAPI(searchDir, mainAPIFile string, parseDepth int) error {
-	Printf("Generate general API Info, search dir:%s", searchDir)
+	return parser.ParseAPIMultiSearchDir([]string{searchDir}, mainAPIFile, parseDepth)
+}
 
-	packageDir, err := getPkgName(searchDir)
-	if err != nil {
-		Printf("warning: failed to get package name in dir: %s, error: %s", searchDir, err.Error())
+// ParseAPIMultiSearchDir is like ParseAPI but for multiple search dirs, mainAPIFile is relative to the first one
+func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile string, parseDepth int) error {
+	if parser.FileSystem != nil {
+		if parser.ParseDependency {
+			return errors.New("ParseDependency isn't supported along with FileSystem")
+		}
+		fsSearchDirs := make([]string, 0, len(searchDirs))
+		for _, searchDir := range searchDirs {
+			fsSearchDirs = append(fsSearchDirs, fsPath(searchDir))
+		}
+		searchDirs = fsSearchDirs
 	}
 
-	if err = parser.getAllGoFileInfo(packageDir, searchDir); err != nil {
-		return err
+	parser.searchDir = searchDirs[0]
+	for _, searchDir := range searchDirs {
+		debugf(parser.debug, "Generate general API Info, search dir:%s", searchDir)
+
+		if parser.FileSystem != nil {
+			if err := parser.getAllGoFileInfoFromFS(searchDir); err != nil {
+				return err
+			}
+			continue
+		}
+
+		packageDir, err := getPkgName(searchDir)
+		if err != nil {
+			warnf(parser.debug, "failed to get package name in dir: %s, error: %s", searchDir, err.Error())
+		}
+
+		if err = parser.getAllGoFileInfo(packageDir, searchDir); err != nil {
+			return err
+		}
 	}
 
-	absMainAPIFilePath, err := filepath.Abs(filepath.Join(searchDir, mainAPIFile))
-	if err != nil {
+	if len(searchDirs) > 1 {
+		if err := parser.checkTypeConflictsAcrossDirs(searchDirs); err != nil {
+			return err
+		}
+	}
+
+	// the main file is optional when the general api info is looked for across the search dirs
+	var absMainAPIFilePath string
+	var err error
+	if parser.FileSystem != nil {
+		absMainAPIFilePath, err = resolveMainAPIFileFS(parser.FileSystem, searchDirs[0], mainAPIFile)
+	} else {
+		absMainAPIFilePath, err = resolveMainAPIFile(searchDirs[0], mainAPIFile)
+	}
+	if err != nil && !parser.ParseGeneralInfoAcrossDir {
 		return err
 	}
 
 	if parser.ParseDependency {
-		var t depth.Tree
-		t.ResolveInternal = true
-		t.MaxDepth = parseDepth
-
-		pkgName, err := getPkgName(filepath.Dir(absMainAPIFilePath))
+		absSearchDir, err := filepath.Abs(searchDirs[0])
 		if err != nil {
 			return err
 		}
-		if err := t.Resolve(pkgName); err != nil {
-			return fmt.Errorf("pkg %s cannot find all dependencies, %s", pkgName, err)
+		mainDir := filepath.Dir(absMainAPIFilePath)
+		if parser.ParseGoList {
+			if err = parser.getAllGoFileInfoFromGoList(absSearchDir, mainDir); err != nil {
+				warnf(parser.debug, "failed to list the dependencies, resolving the import tree instead: %s", err)
+			}
 		}
-		for i := 0; i < len(t.Root.Deps); i++ {
-			if err := parser.getAllGoFileInfoFromDeps(&t.Root.Deps[i]); err != nil {
+		if !parser.ParseGoList || err != nil {
+			if err = parser.getAllGoFileInfoFromDepTree(absSearchDir, mainDir, parseDepth); err != nil {
 				return err
 			}
 		}
 	}
 
-	if err = parser.ParseGeneralAPIInfo(absMainAPIFilePath); err != nil {
+	if parser.ParseGeneralInfoAcrossDir {
+		err = parser.parseGeneralAPIInfoAcrossDirs(searchDirs, absMainAPIFilePath)
+	} else {
+		err = parser.ParseGeneralAPIInfo(absMainAPIFilePath)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -202,16 +459,104 @@ func (parser *Parser) ParseAPI(searchDir, mainAPIFile string, parseDepth int) er
 	if err != nil {
 		return err
 	}
+	for typeSpecDef, schema := range parser.parsedSchemas {
+		parser.setEnums(typeSpecDef, schema.Schema)
+	}
+
+	if err = parser.parseDefaultResponses(); err != nil {
+		return err
+	}
+
+	if err = parser.packages.RangeFiles(parser.parseRouterAPIInfo); err != nil {
+		return err
+	}
+	if err = parser.checkSkippedFiles(); err != nil {
+		return err
+	}
 
-	if err = parser.packages.RangeFiles(parser.ParseRouterAPIInfo); err != nil {
+	parser.applyDefaultResponses()
+	if err = parser.applyDefaultSchemesAndMimeTypes(); err != nil {
 		return err
 	}
+	parser.applyLocale()
 
 	parser.renameRefSchemas()
 
+	parser.removeHiddenOperations()
+	parser.filterOperationsByTags()
+	if parser.DedupeInlineSchemas {
+		parser.dedupeInlineSchemas()
+	}
+	if parser.PruneUnusedDefinitions {
+		parser.pruneUnusedDefinitions()
+	}
+	if parser.InlineSingleUse {
+		parser.inlineSingleUseDefinitions()
+	}
+
 	return parser.checkOperationIDUniqueness()
 }
 
+// checkTypeConflictsAcrossDirs returns an error if types of the same name are declared in more than one search dir
+func (parser *Parser) checkTypeConflictsAcrossDirs(searchDirs []string) error {
+	absSearchDirs := make([]string, 0, len(searchDirs))
+	for _, searchDir := range searchDirs {
+		absSearchDir, err := filepath.Abs(searchDir)
+		if err != nil {
+			return err
+		}
+		absSearchDirs = append(absSearchDirs, absSearchDir)
+	}
+	searchDirOf := func(path string) string {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return ""
+		}
+		for _, absSearchDir := range absSearchDirs {
+			if rel, err := filepath.Rel(absSearchDir, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+				return absSearchDir
+			}
+		}
+		return ""
+	}
+
+	type declaration struct {
+		searchDir string
+		path      string
+	}
+	declarations := make(map[string]declaration)
+
+	files := make([]*AstFileInfo, 0, len(parser.packages.files))
+	for _, info := range parser.packages.files {
+		files = append(files, info)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	for _, info := range files {
+		searchDir := searchDirOf(info.Path)
+		for _, astDeclaration := range info.File.Decls {
+			generalDeclaration, ok := astDeclaration.(*ast.GenDecl)
+			if !ok || generalDeclaration.Tok != token.TYPE {
+				continue
+			}
+			for _, astSpec := range generalDeclaration.Specs {
+				typeSpec, ok := astSpec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				name := TypeDocName(fullTypeName(info.File.Name.Name, typeSpec.Name.Name), typeSpec)
+				if previous, ok := declarations[name]; ok && previous.searchDir != searchDir {
+					return fmt.Errorf("conflicting definition %s declared in both %s and %s", name, previous.path, info.Path)
+				}
+				declarations[name] = declaration{searchDir: searchDir, path: info.Path}
+			}
+		}
+	}
+	return nil
+}
+
 func getPkgName(searchDir string) (string, error) {
 	cmd := exec.Command("go", "list", "-f={{.ImportPath}}")
 	cmd.Dir = searchDir
@@ -242,31 +587,148 @@ func initIfEmpty(license *spec.License) *spec.License {
 	return license
 }
 
+// resolveMainAPIFile returns the absolute path of mainAPIFile. A relative path is looked for in searchDir first,
+// then in the working directory since go:generate runs swag from the directory of the package declaring it. When
+// the file can't be found, the path relative to searchDir is returned along with an error listing the attempts
+func resolveMainAPIFile(searchDir, mainAPIFile string) (string, error) {
+	var attempts []string
+	if filepath.IsAbs(mainAPIFile) {
+		attempts = []string{mainAPIFile}
+	} else {
+		attempts = []string{filepath.Join(searchDir, mainAPIFile), mainAPIFile}
+	}
+	for _, attempt := range attempts {
+		if _, err := os.Stat(attempt); err == nil {
+			return filepath.Abs(attempt)
+		}
+	}
+	absPath, err := filepath.Abs(attempts[0])
+	if err != nil {
+		return "", err
+	}
+	return absPath, fmt.Errorf("cannot find the general API info file %s, tried %s", mainAPIFile, strings.Join(attempts, " and "))
+}
+
 // ParseGeneralAPIInfo parses general api info for given mainAPIFile path
 func (parser *Parser) ParseGeneralAPIInfo(mainAPIFile string) error {
+	var src interface{}
+	if parser.FileSystem != nil {
+		b, err := fs.ReadFile(parser.FileSystem, mainAPIFile)
+		if err != nil {
+			return fmt.Errorf("cannot parse source files %s: %s", mainAPIFile, err)
+		}
+		src = b
+	}
+
 	fileSet := token.NewFileSet()
-	fileTree, err := goparser.ParseFile(fileSet, mainAPIFile, nil, goparser.ParseComments)
+	fileTree, err := goparser.ParseFile(fileSet, mainAPIFile, src, goparser.ParseComments)
 	if err != nil {
 		return fmt.Errorf("cannot parse source files %s: %s", mainAPIFile, err)
 	}
 
+	return parser.parseGeneralAPIInfo(mainAPIFile, fileTree.Comments, nil)
+}
+
+// parseGeneralAPIInfoAcrossDirs parses the general api info written in any file of the search dirs. The comments
+// of the main file are parsed first, in the other files only the comments holding an annotation specific to the
+// general api info are. An error is returned if a field is set in several places.
+func (parser *Parser) parseGeneralAPIInfoAcrossDirs(searchDirs []string, mainAPIFile string) error {
+	absSearchDirs := make([]string, 0, len(searchDirs))
+	for _, searchDir := range searchDirs {
+		absSearchDir, err := filepath.Abs(searchDir)
+		if err != nil {
+			return err
+		}
+		absSearchDirs = append(absSearchDirs, absSearchDir+string(filepath.Separator))
+	}
+
+	type generalInfoFile struct {
+		path    string
+		absPath string
+		file    *ast.File
+	}
+	// a file may be parsed both from the search dir and as a dependency, keep the shortest path
+	filesByAbsPath := make(map[string]generalInfoFile)
+	for astFile, info := range parser.packages.files {
+		absPath, err := filepath.Abs(info.Path)
+		if err != nil {
+			return err
+		}
+		for _, absSearchDir := range absSearchDirs {
+			if !strings.HasPrefix(absPath, absSearchDir) {
+				continue
+			}
+			if f, ok := filesByAbsPath[absPath]; !ok || len(info.Path) < len(f.path) {
+				filesByAbsPath[absPath] = generalInfoFile{path: info.Path, absPath: absPath, file: astFile}
+			}
+			break
+		}
+	}
+	files := make([]generalInfoFile, 0, len(filesByAbsPath))
+	for _, f := range filesByAbsPath {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if (files[i].absPath == mainAPIFile) != (files[j].absPath == mainAPIFile) {
+			return files[i].absPath == mainAPIFile
+		}
+		return files[i].absPath < files[j].absPath
+	})
+
+	locations := make(map[string]string)
+	for _, f := range files {
+		comments := f.file.Comments
+		if f.absPath != mainAPIFile {
+			comments = nil
+			typeDocs := typeDocComments(f.file)
+			for _, comment := range f.file.Comments {
+				if !typeDocs[comment] && hasGeneralAPIOnlyAttribute(parser.rewriteSwagDirectives(comment)) {
+					comments = append(comments, comment)
+				}
+			}
+		}
+		if err := parser.parseGeneralAPIInfo(f.path, comments, locations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseGeneralAPIInfo parses the general api info in the comments of fileName. If locations isn't nil, it records
+// where each field is set and an error is returned for a field already set elsewhere.
+func (parser *Parser) parseGeneralAPIInfo(fileName string, fileComments []*ast.CommentGroup, locations map[string]string) error {
 	parser.swagger.Swagger = "2.0"
 	securityMap := map[string]*spec.SecurityScheme{}
 
-	for _, comment := range fileTree.Comments {
+	for _, comment := range fileComments {
+		comment = parser.rewriteSwagDirectives(comment)
 		if !isGeneralAPIComment(comment) {
 			continue
 		}
 		comments := strings.Split(comment.Text(), "\n")
 		previousAttribute := ""
+		fenceEnd := -1
 		// parsing classic meta data model
 		for i, commentLine := range comments {
+			if i <= fenceEnd {
+				continue
+			}
 			attribute := strings.ToLower(strings.Split(commentLine, " ")[0])
 			value := strings.TrimSpace(commentLine[len(attribute):])
 			multilineBlock := false
 			if previousAttribute == attribute {
 				multilineBlock = true
 			}
+			if strings.HasPrefix(attribute, "@tag.") && attribute != "@tag.name" && len(parser.swagger.Tags) == 0 {
+				return fmt.Errorf("%s needs to come after a @tag.name", attribute)
+			}
+			if locations != nil && !multilineBlock && isSingleValuedGeneralAPIAttribute(attribute) {
+				location := parser.position(fileName, commentLinePos(comment, commentLine))
+				if previous, ok := locations[attribute]; ok && previous != location {
+					return fmt.Errorf("%s is set in both %s and %s", attribute, previous, location)
+				}
+				locations[attribute] = location
+			}
 			switch attribute {
 			case "@version":
 				parser.swagger.Info.Version = value
@@ -275,9 +737,21 @@ func (parser *Parser) ParseGeneralAPIInfo(mainAPIFile string) error {
 			case "@description":
 				if multilineBlock {
 					parser.swagger.Info.Description += "\n" + value
-					continue
+				} else {
+					parser.swagger.Info.Description = value
+				}
+				if strings.HasPrefix(value, "```") {
+					// the lines of a fenced block are kept as they are up to the closing fence
+					block, n := fencedBlock(comments[i+1:])
+					parser.swagger.Info.Description += block
+					fenceEnd = i + n
+				}
+			case "@description.file":
+				content, err := parser.readFile(filepath.Join(parser.searchDir, value))
+				if err != nil {
+					return fmt.Errorf("failed to read description file %s: %s", value, err)
 				}
-				parser.swagger.Info.Description = value
+				parser.swagger.Info.Description = string(content)
 			case "@description.markdown":
 				commentInfo, err := getMarkdownForTag("api", parser.markdownFileDir)
 				if err != nil {
@@ -299,7 +773,19 @@ func (parser *Parser) ParseGeneralAPIInfo(mainAPIFile string) error {
 				parser.swagger.Info.License = initIfEmpty(parser.swagger.Info.License)
 				parser.swagger.Info.License.URL = value
 			case "@host":
-				parser.swagger.Host = value
+				if parser.swagger.Host == "" || parser.swagger.Host == value {
+					parser.swagger.Host = value
+					break
+				}
+				warnf(parser.debug, "Swagger 2.0 has a single host, %s is used and %s is only listed in %s",
+					parser.swagger.Host, value, serversExtension)
+				parser.addServer(value, "")
+			case "@server":
+				fields := strings.Fields(value)
+				if len(fields) == 0 {
+					return fmt.Errorf("annotation %s need an url", attribute)
+				}
+				parser.addServer(fields[0], strings.TrimSpace(strings.TrimPrefix(value, fields[0])))
 			case "@basepath":
 				parser.swagger.BasePath = value
 			case "@schemes":
@@ -367,10 +853,19 @@ func (parser *Parser) ParseGeneralAPIInfo(mainAPIFile string) error {
 					return err
 				}
 				securityMap[value] = securitySchemeOAuth2AccessToken(attrMap["@authorizationurl"], attrMap["@tokenurl"], scopes, extensions)
+			case "@failure.default":
+				parser.defaultResponseComments = append(parser.defaultResponseComments, generalComment{fileName: fileName, value: value})
+			case "@example.named":
+				if err := parser.parseNamedExample(attribute, value); err != nil {
+					return err
+				}
 			case "@x-tokenname":
 				// ignore this
 				break
 			case "@query.collection.format":
+				if TransToValidCollectionFormat(value) == "" {
+					return fmt.Errorf("%s %s is not one of csv, ssv, tsv, pipes, multi", attribute, value)
+				}
 				parser.collectionFormatInQuery = value
 			default:
 				prefixExtension := "@x-"
@@ -398,13 +893,150 @@ func (parser *Parser) ParseGeneralAPIInfo(mainAPIFile string) error {
 		}
 	}
 
+	parser.swagger.Tags = dedupeTags(parser.swagger.Tags)
+
 	if len(securityMap) > 0 {
-		parser.swagger.SecurityDefinitions = securityMap
+		if parser.swagger.SecurityDefinitions == nil {
+			parser.swagger.SecurityDefinitions = securityMap
+		} else {
+			for name, securityScheme := range securityMap {
+				parser.swagger.SecurityDefinitions[name] = securityScheme
+			}
+		}
 	}
 
 	return nil
 }
 
+// parseNamedExample declares the example of an @example.named annotation, whose value is a name followed by json
+func (parser *Parser) parseNamedExample(attribute, value string) error {
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return fmt.Errorf("annotation %s need a name and a value", attribute)
+	}
+	name := fields[0]
+	var valueJSON interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(value[len(name):])), &valueJSON); err != nil {
+		return fmt.Errorf("annotation %s %s need a valid json value", attribute, name)
+	}
+	if _, ok := parser.namedExamples[name]; ok {
+		return fmt.Errorf("example %s is declared more than once", name)
+	}
+	if parser.namedExamples == nil {
+		parser.namedExamples = make(map[string]interface{})
+	}
+	parser.namedExamples[name] = valueJSON
+	return nil
+}
+
+// fencedBlock returns the lines of a fenced block up to its closing fence, each one preceded by a newline, and the
+// number of lines it spans
+func fencedBlock(lines []string) (string, int) {
+	var block strings.Builder
+	for i, line := range lines {
+		block.WriteString("\n" + line)
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			return block.String(), i + 1
+		}
+	}
+	return block.String(), len(lines)
+}
+
+// serversExtension lists the servers declared by @server and the hosts following the first @host, since Swagger 2.0
+// has a single host
+const serversExtension = "x-servers"
+
+// addServer adds a server to the servers extension, url being either an absolute url or a host
+func (parser *Parser) addServer(url, description string) {
+	server := map[string]interface{}{"url": url}
+	if description != "" {
+		server["description"] = description
+	}
+	servers, _ := parser.swagger.Extensions[serversExtension].([]interface{})
+	parser.swagger.AddExtension(serversExtension, append(servers, server))
+}
+
+// isSingleValuedGeneralAPIAttribute tells whether attribute sets a field of the general api info, unlike the
+// tags and security definitions which add an entry
+func isSingleValuedGeneralAPIAttribute(attribute string) bool {
+	switch attribute {
+	case "@version", "@title", "@description", "@description.markdown", "@description.file", "@termsofservice",
+		"@contact.name", "@contact.email", "@contact.url", "@license.name", "@license.url",
+		"@basepath", "@schemes", "@query.collection.format":
+		return true
+	}
+	return strings.HasPrefix(attribute, "@x-") && attribute != "@x-tokenname"
+}
+
+// hasGeneralAPIOnlyAttribute tells whether comment holds an annotation found only in the general api info
+func hasGeneralAPIOnlyAttribute(comment *ast.CommentGroup) bool {
+	if !isGeneralAPIComment(comment) {
+		return false
+	}
+	for _, commentLine := range strings.Split(comment.Text(), "\n") {
+		attribute := strings.ToLower(strings.Split(commentLine, " ")[0])
+		switch {
+		case attribute == "@description", strings.HasPrefix(attribute, "@x-"):
+			// also found in the comments of operations and types
+			continue
+		case isSingleValuedGeneralAPIAttribute(attribute), attribute == "@failure.default", attribute == "@example.named",
+			attribute == "@host", attribute == "@server",
+			strings.HasPrefix(attribute, "@tag."), strings.HasPrefix(attribute, "@securitydefinitions."):
+			return true
+		}
+	}
+	return false
+}
+
+// typeDocComments returns the doc comments of the type declarations of file
+func typeDocComments(file *ast.File) map[*ast.CommentGroup]bool {
+	docs := make(map[*ast.CommentGroup]bool)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		if genDecl.Doc != nil {
+			docs[genDecl.Doc] = true
+		}
+		for _, typeSpec := range genDecl.Specs {
+			if doc := typeSpec.(*ast.TypeSpec).Doc; doc != nil {
+				docs[doc] = true
+			}
+		}
+	}
+	return docs
+}
+
+// commentLinePos returns the position of the comment of group holding line
+func commentLinePos(group *ast.CommentGroup, line string) token.Pos {
+	for _, comment := range group.List {
+		if strings.Contains(comment.Text, line) {
+			return comment.Pos()
+		}
+	}
+	return group.Pos()
+}
+
+// swagDirectivePrefix is the prefix of gofmt-stable directives, like //swag:router /x [get]
+const swagDirectivePrefix = "//swag:"
+
+// rewriteSwagDirectives rewrites //swag:xxx directives of a comment group into // @xxx annotations when SwagDirectiveStyle is set
+func (parser *Parser) rewriteSwagDirectives(comment *ast.CommentGroup) *ast.CommentGroup {
+	if !parser.SwagDirectiveStyle {
+		return comment
+	}
+
+	list := make([]*ast.Comment, 0, len(comment.List))
+	for _, c := range comment.List {
+		if strings.HasPrefix(c.Text, swagDirectivePrefix) {
+			c = &ast.Comment{Slash: c.Slash, Text: "// @" + strings.TrimPrefix(c.Text, swagDirectivePrefix)}
+		}
+		list = append(list, c)
+	}
+	return &ast.CommentGroup{List: list}
+}
+
 func isGeneralAPIComment(comment *ast.CommentGroup) bool {
 	for _, commentLine := range strings.Split(comment.Text(), "\n") {
 		attribute := strings.ToLower(strings.Split(commentLine, " ")[0])
@@ -438,7 +1070,7 @@ func extractSecurityAttribute(context string, search []string, lines []string) (
 			if err != nil {
 				return nil, nil, nil, err
 			}
-			scopes[scopScheme] = v[len(securityAttr):]
+			scopes[scopScheme] = strings.TrimSpace(v[len(securityAttr):])
 		}
 		if securityAttr == "@x-tokenname" {
 			extensions["x-tokenName"] = strings.TrimSpace(v[len(securityAttr):])
@@ -557,14 +1189,49 @@ func getSchemes(commentLine string) []string {
 
 // ParseRouterAPIInfo parses router api info for given astFile
 func (parser *Parser) ParseRouterAPIInfo(fileName string, astFile *ast.File) error {
+	var routerPrefix string
+	if parser.PrefixAnnotation {
+		var err error
+		if routerPrefix, err = parser.parseRouterPrefix(fileName, astFile); err != nil {
+			return err
+		}
+	}
+
 	for _, astDescription := range astFile.Decls {
 		switch astDeclaration := astDescription.(type) {
 		case *ast.FuncDecl:
 			if astDeclaration.Doc != nil && astDeclaration.Doc.List != nil {
 				operation := NewOperation(parser, SetCodeExampleFilesDirectory(parser.codeExampleFilesDir)) //for per 'function' comment, create a new 'Operation' object
-				for _, comment := range astDeclaration.Doc.List {
+				// annotation is the comment holding the annotation being parsed, errors are reported at its line
+				var annotation *ast.Comment
+				for _, comment := range parser.rewriteSwagDirectives(astDeclaration.Doc).List {
+					if operation.extension == nil && operation.codeSample == nil {
+						annotation = comment
+					}
 					if err := operation.ParseComment(comment.Text, astFile); err != nil {
-						return fmt.Errorf("ParseComment error in file %s :%+v", fileName, err)
+						return fmt.Errorf("ParseComment error in file %s :%+v", parser.position(fileName, annotation.Pos()), err)
+					}
+				}
+				if err := operation.checkPendingExtension(); err != nil {
+					return fmt.Errorf("ParseComment error in file %s :%+v", parser.position(fileName, annotation.Pos()), err)
+				}
+				if operation.Path != "" {
+					operation.Path = prefixRouterPath(routerPrefix, operation.Path)
+					parser.addPathParams(operation, fileName, astDeclaration)
+					parser.checkQueryHints(operation, fileName, astDeclaration)
+					if parser.InferResponses {
+						parser.inferResponses(operation, astDeclaration, astFile)
+					}
+				}
+				if err := operation.applyResponseHeaders(parser.defaultResponses); err != nil {
+					return fmt.Errorf("ParseComment error in file %s :%+v", parser.position(fileName, annotation.Pos()), err)
+				}
+				if operation.ID == "" && parser.DefaultOperationID && operation.Path != "" {
+					operation.ID = astDeclaration.Name.Name
+				}
+				if operation.Path != "" {
+					if err := parser.registerRoute(operation, astDeclaration, fileName); err != nil {
+						return err
 					}
 				}
 				var pathItem spec.PathItem
@@ -591,6 +1258,12 @@ func (parser *Parser) ParseRouterAPIInfo(fileName string, astFile *ast.File) err
 				}
 
 				parser.swagger.Paths.Paths[operation.Path] = pathItem
+				if operation.hidden {
+					if parser.hiddenOperations == nil {
+						parser.hiddenOperations = make(map[*spec.Operation]bool)
+					}
+					parser.hiddenOperations[&operation.Operation] = true
+				}
 			}
 		}
 	}
@@ -598,14 +1271,101 @@ func (parser *Parser) ParseRouterAPIInfo(fileName string, astFile *ast.File) err
 	return nil
 }
 
-func convertFromSpecificToPrimitive(typeName string) (string, error) {
-	name := typeName
-	if strings.ContainsRune(name, '.') {
-		name = strings.Split(name, ".")[1]
-	}
-	switch strings.ToUpper(name) {
-	case "TIME", "OBJECTID", "UUID":
-		return STRING, nil
+// pathParamPattern matches the {name} segments of a path
+var pathParamPattern = regexp.MustCompile(`\{([^/{}]+)\}`)
+
+// addPathParams adds a required string parameter for each {name} segment of the path of operation having no
+// @Param, the explicit ones taking precedence
+func (parser *Parser) addPathParams(operation *Operation, fileName string, handler *ast.FuncDecl) {
+	for _, matches := range pathParamPattern.FindAllStringSubmatch(operation.Path, -1) {
+		name := matches[1]
+		documented := false
+		for _, param := range operation.Parameters {
+			if param.In == "path" && param.Name == name {
+				documented = true
+				break
+			}
+		}
+		if documented {
+			continue
+		}
+		warnf(parser.debug, "path parameter %s of %s has no @Param in %s, it is documented as a required string",
+			name, operation.Path, parser.position(fileName, handler.Pos()))
+		operation.Parameters = append(operation.Parameters, *spec.PathParam(name).Typed(STRING, ""))
+	}
+}
+
+// checkQueryHints warns about the keys of the query string of @Router, like q for /search?q, having no @Param in query
+func (parser *Parser) checkQueryHints(operation *Operation, fileName string, handler *ast.FuncDecl) {
+	for _, key := range operation.queryHints {
+		documented := false
+		for _, param := range operation.Parameters {
+			if param.In == "query" && param.Name == key {
+				documented = true
+				break
+			}
+		}
+		if !documented {
+			warnf(parser.debug, "query parameter %s of the @Router of %s has no @Param in %s", key, operation.Path,
+				parser.position(fileName, handler.Pos()))
+		}
+	}
+}
+
+// parseRouterPrefix returns the path of the @RouterPrefix annotation written outside of the functions of astFile,
+// empty if there is none
+func (parser *Parser) parseRouterPrefix(fileName string, astFile *ast.File) (string, error) {
+	funcDocs := make(map[*ast.CommentGroup]bool)
+	for _, decl := range astFile.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Doc != nil {
+			funcDocs[funcDecl.Doc] = true
+		}
+	}
+
+	var prefix string
+	for _, comment := range astFile.Comments {
+		if funcDocs[comment] {
+			continue
+		}
+		for _, commentLine := range strings.Split(parser.rewriteSwagDirectives(comment).Text(), "\n") {
+			fields := strings.Fields(commentLine)
+			if len(fields) == 0 || strings.ToLower(fields[0]) != "@routerprefix" {
+				continue
+			}
+			if prefix != "" {
+				return "", fmt.Errorf("@RouterPrefix is declared more than once in file %s", fileName)
+			}
+			if len(fields) != 2 || !strings.HasPrefix(fields[1], "/") {
+				return "", fmt.Errorf("@RouterPrefix of file %s must be a path starting with /", fileName)
+			}
+			prefix = strings.TrimSuffix(fields[1], "/")
+			if prefix == "" {
+				prefix = "/"
+			}
+		}
+	}
+	return prefix, nil
+}
+
+// prefixRouterPath prepends prefix to path, unless path already starts with it
+func prefixRouterPath(prefix, path string) string {
+	switch {
+	case prefix == "" || prefix == "/" || path == prefix || strings.HasPrefix(path, prefix+"/"):
+		return path
+	case path == "/":
+		return prefix
+	}
+	return prefix + path
+}
+
+func convertFromSpecificToPrimitive(typeName string) (string, error) {
+	name := typeName
+	if strings.ContainsRune(name, '.') {
+		name = strings.Split(name, ".")[1]
+	}
+	switch strings.ToUpper(name) {
+	case "TIME", "OBJECTID", "UUID":
+		return STRING, nil
 	case "DECIMAL":
 		return NUMBER, nil
 	}
@@ -614,25 +1374,73 @@ func convertFromSpecificToPrimitive(typeName string) (string, error) {
 
 func (parser *Parser) getTypeSchema(typeName string, file *ast.File, ref bool) (*spec.Schema, error) {
 	if IsGolangPrimitiveType(typeName) {
+		if parser.Int64AsString && (typeName == "int64" || typeName == "uint64") {
+			return int64StringSchema(typeName), nil
+		}
 		return PrimitiveSchema(TransToValidSchemeType(typeName)), nil
 	}
 
+	if schema, ok := parser.typeOverrideSchema(typeName, file); ok {
+		return schema, nil
+	}
+
+	switch typeName {
+	case "time.Time":
+		schema := PrimitiveSchema(STRING)
+		schema.Format = "date-time"
+		return schema, nil
+	case "json.RawMessage":
+		// any json value
+		return &spec.Schema{}, nil
+	case "time.Duration":
+		if parser.DurationType == STRING {
+			return PrimitiveSchema(STRING), nil
+		}
+		schema := PrimitiveSchema(INTEGER)
+		schema.Format = "int64"
+		return schema, nil
+	}
+
 	if schemaType, err := convertFromSpecificToPrimitive(typeName); err == nil {
 		return PrimitiveSchema(schemaType), nil
 	}
 
+	if schema, ok, err := parser.aliasTypeSchema(typeName); ok {
+		return schema, err
+	}
+
 	typeSpecDef := parser.packages.FindTypeSpec(typeName, file)
 	if typeSpecDef == nil {
+		if !parser.ParseVendor && strings.ContainsRune(typeName, '.') && parser.hasVendorDir() {
+			warnf(parser.debug, "type %s may be vendored, enable ParseVendor to resolve the types of the vendor folder", typeName)
+		}
 		return nil, fmt.Errorf("cannot find type definition: %s", typeName)
 	}
 
+	// an alias left by FindTypeSpec stands for a primitive or a type out of the parsed packages, its schema is theirs
+	if typeSpecDef.TypeSpec != nil && typeSpecDef.TypeSpec.Assign.IsValid() {
+		if _, ok := typeSpecDef.TypeSpec.Type.(*ast.StructType); !ok {
+			return parser.parseTypeExpr(typeSpecDef.File, typeSpecDef.TypeSpec.Type, ref)
+		}
+	}
+
+	if len(parser.AliasTypes) > 0 && typeSpecDef.File != nil {
+		if schema, ok, err := parser.aliasTypeSchema(typeSpecDef.FullName()); ok {
+			return schema, err
+		}
+	}
+
+	if schema, ok, err := parser.selfDescribedTypeSchema(typeSpecDef); ok {
+		return schema, err
+	}
+
 	schema, ok := parser.parsedSchemas[typeSpecDef]
 	if !ok {
 		var err error
 		schema, err = parser.ParseDefinition(typeSpecDef)
 		if err == ErrRecursiveParseStruct {
 			if ref {
-				return parser.getRefTypeSchema(typeSpecDef, schema), nil
+				return parser.getRefTypeSchema(typeSpecDef, schema)
 			}
 
 		} else if err != nil {
@@ -641,11 +1449,152 @@ func (parser *Parser) getTypeSchema(typeName string, file *ast.File, ref bool) (
 	}
 
 	if ref && len(schema.Schema.Type) > 0 && schema.Schema.Type[0] == OBJECT {
-		return parser.getRefTypeSchema(typeSpecDef, schema), nil
+		return parser.getRefTypeSchema(typeSpecDef, schema)
+	}
+	// a named type over int64, unless it's an enum whose values are numbers
+	if parser.Int64AsString && schema.Schema.Type.Contains(INTEGER) && schema.Schema.Format == "int64" && len(schema.Schema.Enum) == 0 {
+		return int64StringSchema(schema.Schema.Format), nil
 	}
 	return schema.Schema, nil
 }
 
+// int64StringSchema is the schema of an int64 or uint64 documented as a string when Int64AsString is set, the
+// go type is kept as the x-format extension
+func int64StringSchema(typeName string) *spec.Schema {
+	schema := PrimitiveSchema(STRING)
+	schema.AddExtension("x-format", typeName)
+	return schema
+}
+
+// swaggerSchemaMethod is the method through which a type describes its own schema, returning a string literal
+// holding a primitive type and an optional format like
+//
+//	func (Time) SwaggerSchema() string { return "string,date-time" }
+const swaggerSchemaMethod = "SwaggerSchema"
+
+// selfDescribedTypeSchema builds the schema of a type declaring a SwaggerSchema method, ok tells whether it does
+func (parser *Parser) selfDescribedTypeSchema(typeSpecDef *TypeSpecDef) (schema *spec.Schema, ok bool, err error) {
+	funcDecl := parser.packages.findSchemaMethod(typeSpecDef)
+	if funcDecl == nil {
+		return nil, false, nil
+	}
+
+	var value string
+	if funcDecl.Body != nil && len(funcDecl.Body.List) == 1 {
+		if ret, ok := funcDecl.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			if lit, ok := ret.Results[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				value, _ = strconv.Unquote(lit.Value)
+			}
+		}
+	}
+	if value == "" {
+		return nil, true, fmt.Errorf("%s.%s must return a string literal", typeSpecDef.FullName(), swaggerSchemaMethod)
+	}
+
+	parts := strings.SplitN(value, ",", 2)
+	schemaType := strings.TrimSpace(parts[0])
+	if !IsSimplePrimitiveType(schemaType) {
+		return nil, true, fmt.Errorf("%s.%s returns %s which is not a primitive type", typeSpecDef.FullName(), swaggerSchemaMethod, schemaType)
+	}
+	schema = PrimitiveSchema(schemaType)
+	if len(parts) == 2 {
+		schema.Format = strings.TrimSpace(parts[1])
+	}
+	return schema, true, nil
+}
+
+// typeOverrideSchema returns a copy of the schema of TypeOverrides documenting a type used in file, ok tells whether
+// it's found
+func (parser *Parser) typeOverrideSchema(typeName string, file *ast.File) (schema *spec.Schema, ok bool) {
+	if len(parser.TypeOverrides) == 0 {
+		return nil, false
+	}
+	for _, name := range parser.qualifiedTypeNames(typeName, file) {
+		if schema, ok = parser.TypeOverrides[name]; ok && schema != nil {
+			return copySchemaShallow(schema), true
+		}
+	}
+	return nil, false
+}
+
+// qualifiedTypeNames returns the names of a type used in file, the import path of its package followed by its name
+// when it can be told, then its package name followed by its name
+func (parser *Parser) qualifiedTypeNames(typeName string, file *ast.File) []string {
+	if file == nil {
+		return []string{typeName}
+	}
+
+	var pkgPath string
+	pkgName, name := file.Name.Name, typeName
+	if i := strings.LastIndexByte(typeName, '.'); i != -1 {
+		pkgName, name = typeName[:i], typeName[i+1:]
+		pkgPath = parser.packages.findPackagePathFromImports(pkgName, file)
+		if pkgPath == "" {
+			pkgPath = importPathOf(pkgName, file)
+		}
+	} else if info, ok := parser.packages.files[file]; ok {
+		pkgPath = info.PackagePath
+	}
+
+	if pkgPath == "" || pkgPath == pkgName {
+		return []string{fullTypeName(pkgName, name)}
+	}
+	return []string{pkgPath + "." + name, fullTypeName(pkgName, name)}
+}
+
+// importPathOf returns the path of the import of file named pkgName, either by its alias or by the last element of
+// its path, an empty string if there's none
+func importPathOf(pkgName string, file *ast.File) string {
+	for _, imp := range file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		if imp.Name != nil && imp.Name.Name == pkgName || imp.Name == nil && importPath[strings.LastIndexByte(importPath, '/')+1:] == pkgName {
+			return importPath
+		}
+	}
+	return ""
+}
+
+// aliasTypeSchema builds the schema of a type found in AliasTypes, ok tells whether typeName is found
+func (parser *Parser) aliasTypeSchema(typeName string) (schema *spec.Schema, ok bool, err error) {
+	alias, ok := parser.AliasTypes[typeName]
+	if !ok {
+		return nil, false, nil
+	}
+
+	types := strings.Split(alias, ",")
+	var format string
+	if i := strings.Index(types[len(types)-1], ":"); i != -1 {
+		types[len(types)-1], format = types[len(types)-1][:i], types[len(types)-1][i+1:]
+	}
+	schema, err = BuildCustomSchema(types)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid alias type %s of %s: %s", alias, typeName, err)
+	}
+
+	// the format belongs to the innermost type
+	eleSchema := schema
+	for {
+		if eleSchema.Items != nil && eleSchema.Items.Schema != nil {
+			eleSchema = eleSchema.Items.Schema
+		} else if eleSchema.AdditionalProperties != nil && eleSchema.AdditionalProperties.Schema != nil {
+			eleSchema = eleSchema.AdditionalProperties.Schema
+		} else {
+			break
+		}
+	}
+	eleSchema.Format = format
+	return schema, true, nil
+}
+
+// checkFormat returns format as it is, the formats unknown to swagger and json schema are logged since tools may
+// ignore them
+func (parser *Parser) checkFormat(format string) string {
+	if !knownFormats[format] {
+		debugf(parser.debug, "Unknown format %s is kept as it is", format)
+	}
+	return format
+}
+
 func (parser *Parser) renameRefSchemas() {
 	if len(parser.toBeRenamedSchemas) == 0 {
 		return
@@ -678,9 +1627,33 @@ func (parser *Parser) renameSchema(name, pkgPath string) string {
 	return name
 }
 
-func (parser *Parser) getRefTypeSchema(typeSpecDef *TypeSpecDef, schema *Schema) *spec.Schema {
+// definitionName returns the name of the definition of typeSpecDef, given by its @name comment, ModelNamer or
+// DefinitionNaming
+func (parser *Parser) definitionName(typeSpecDef *TypeSpecDef) string {
+	typeName := typeSpecDef.FullName()
+	if name := TypeDocName(typeName, typeSpecDef.TypeSpec); name != typeName {
+		return name
+	}
+	switch {
+	case parser.ModelNamer != nil:
+		return parser.ModelNamer(typeSpecDef.PkgPath, typeSpecDef.Name())
+	case parser.DefinitionNaming == FullDefinitionNaming:
+		return strings.ReplaceAll(fullTypeName(typeSpecDef.PkgPath, typeSpecDef.Name()), "/", "_")
+	}
+	return typeName
+}
+
+func (parser *Parser) getRefTypeSchema(typeSpecDef *TypeSpecDef, schema *Schema) (*spec.Schema, error) {
 	if _, ok := parser.outputSchemas[typeSpecDef]; !ok {
 		if existSchema, ok := parser.existSchemaNames[schema.Name]; ok {
+			// the types are renamed only when no definition naming is chosen
+			if parser.DefinitionNaming == ShortDefinitionNaming && parser.ModelNamer == nil {
+				return nil, fmt.Errorf("definition %s is given to types of both %s and %s, use the %s definition naming to tell them apart",
+					schema.Name, existSchema.PkgPath, schema.PkgPath, FullDefinitionNaming)
+			}
+			if parser.DefinitionNaming != "" || parser.ModelNamer != nil {
+				return nil, fmt.Errorf("definition %s is given to types of both %s and %s", schema.Name, existSchema.PkgPath, schema.PkgPath)
+			}
 			//store the first one to be renamed after parsing over
 			if _, ok = parser.toBeRenamedSchemas[existSchema.Name]; !ok {
 				parser.toBeRenamedSchemas[existSchema.Name] = existSchema.PkgPath
@@ -701,7 +1674,7 @@ func (parser *Parser) getRefTypeSchema(typeSpecDef *TypeSpecDef, schema *Schema)
 	refSchema := RefSchema(schema.Name)
 	//store every URL
 	parser.toBeRenamedRefURLs = append(parser.toBeRenamedRefURLs, refSchema.Ref.Ref.GetURL())
-	return refSchema
+	return refSchema, nil
 }
 
 func (parser *Parser) isInStructStack(typeSpecDef *TypeSpecDef) bool {
@@ -718,15 +1691,15 @@ func (parser *Parser) isInStructStack(typeSpecDef *TypeSpecDef) bool {
 // with a schema for the given type
 func (parser *Parser) ParseDefinition(typeSpecDef *TypeSpecDef) (*Schema, error) {
 	typeName := typeSpecDef.FullName()
-	refTypeName := TypeDocName(typeName, typeSpecDef.TypeSpec)
+	refTypeName := parser.definitionName(typeSpecDef)
 
 	if schema, ok := parser.parsedSchemas[typeSpecDef]; ok {
-		Println("Skipping '" + typeName + "', already parsed.")
+		debugf(parser.debug, "Skipping '%s', already parsed.", typeName)
 		return schema, nil
 	}
 
 	if parser.isInStructStack(typeSpecDef) {
-		Println("Skipping '" + typeName + "', recursion detected.")
+		debugf(parser.debug, "Skipping '%s', recursion detected.", typeName)
 		return &Schema{
 				Name:    refTypeName,
 				PkgPath: typeSpecDef.PkgPath,
@@ -734,13 +1707,37 @@ func (parser *Parser) ParseDefinition(typeSpecDef *TypeSpecDef) (*Schema, error)
 			ErrRecursiveParseStruct
 	}
 	parser.structStack = append(parser.structStack, typeSpecDef)
+	defer func() {
+		parser.structStack = parser.structStack[:len(parser.structStack)-1]
+	}()
 
-	Println("Generating " + typeName)
+	debugf(parser.debug, "Generating %s", typeName)
 
 	schema, err := parser.parseTypeExpr(typeSpecDef.File, typeSpecDef.TypeSpec.Type, false)
 	if err != nil {
 		return nil, err
 	}
+	if ident, ok := typeSpecDef.TypeSpec.Type.(*ast.Ident); ok && IsGolangPrimitiveType(ident.Name) {
+		schema.Format = TransToValidSchemeFormat(ident.Name)
+	}
+	parser.setEnums(typeSpecDef, schema)
+
+	if expr := additionalPropertiesAnnotation(typeSpecDef.TypeSpec); expr != "" {
+		schema.AdditionalProperties, err = parser.parseAdditionalProperties(typeSpecDef.File, expr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	extensions, err := parser.parseSchemaExtensions(typeSpecDef)
+	if err != nil {
+		return nil, err
+	}
+	if extensions != nil {
+		schema = copySchemaShallow(schema)
+		schema.Extensions = mergeExtensions(schema.Extensions, extensions)
+	}
+
 	s := &Schema{Name: refTypeName, PkgPath: typeSpecDef.PkgPath, Schema: schema}
 	parser.parsedSchemas[typeSpecDef] = s
 
@@ -752,6 +1749,101 @@ func (parser *Parser) ParseDefinition(typeSpecDef *TypeSpecDef) (*Schema, error)
 	return s, nil
 }
 
+// additionalPropertiesAnnotation returns the type expression of a '// @additionalProperties ' comment on a type spec
+func additionalPropertiesAnnotation(typeSpec *ast.TypeSpec) string {
+	for _, commentGroup := range []*ast.CommentGroup{typeSpec.Doc, typeSpec.Comment} {
+		if commentGroup == nil {
+			continue
+		}
+		for _, comment := range commentGroup.List {
+			text := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+			fields := strings.Fields(text)
+			if len(fields) > 1 && strings.ToLower(fields[0]) == "@additionalproperties" {
+				return strings.TrimSpace(text[len(fields[0]):])
+			}
+		}
+	}
+	return ""
+}
+
+// parseSchemaExtensions parses the x- annotations in the comments of a type declaration, their values must be json
+func (parser *Parser) parseSchemaExtensions(typeSpecDef *TypeSpecDef) (spec.Extensions, error) {
+	var fileName string
+	if info, ok := parser.packages.files[typeSpecDef.File]; ok {
+		fileName = info.Path
+	}
+
+	var extensions spec.Extensions
+	for _, commentGroup := range []*ast.CommentGroup{typeSpecDef.TypeSpec.Doc, typeSpecDef.TypeSpec.Comment} {
+		if commentGroup == nil {
+			continue
+		}
+		for _, comment := range commentGroup.List {
+			text := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+			fields := strings.Fields(text)
+			if len(fields) == 0 || !strings.HasPrefix(strings.ToLower(fields[0]), "@x-") {
+				continue
+			}
+			attribute := fields[0]
+			var valueJSON interface{}
+			if err := json.Unmarshal([]byte(strings.TrimSpace(text[len(attribute):])), &valueJSON); err != nil {
+				return nil, fmt.Errorf("annotation %s of %s in file %s need a valid json value",
+					attribute, typeSpecDef.FullName(), parser.position(fileName, comment.Pos()))
+			}
+			if extensions == nil {
+				extensions = spec.Extensions{}
+			}
+			extensions[attribute[1:]] = valueJSON
+		}
+	}
+	return extensions, nil
+}
+
+// parseAdditionalProperties parses the value type of additionalProperties, such as string, []int, pkg.Type or true
+func (parser *Parser) parseAdditionalProperties(file *ast.File, typeExpr string) (*spec.SchemaOrBool, error) {
+	switch typeExpr {
+	case "true", "false":
+		return &spec.SchemaOrBool{Allows: typeExpr == "true"}, nil
+	}
+
+	expr, err := goparser.ParseExpr(typeExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid @additionalProperties type %s: %s", typeExpr, err)
+	}
+	return parser.parseAdditionalPropertiesExpr(file, expr)
+}
+
+func (parser *Parser) parseAdditionalPropertiesExpr(file *ast.File, expr ast.Expr) (*spec.SchemaOrBool, error) {
+	if _, ok := expr.(*ast.InterfaceType); ok {
+		return &spec.SchemaOrBool{Allows: true}, nil
+	}
+
+	schema, err := parser.parseTypeExpr(file, expr, true)
+	if err != nil {
+		return nil, err
+	}
+	return &spec.SchemaOrBool{Allows: true, Schema: schema}, nil
+}
+
+// inlineMapField returns the map type of a field tagged with `json:",inline"`, which holds extra properties of a struct
+func inlineMapField(field *ast.Field) (*ast.MapType, bool) {
+	if field.Tag == nil {
+		return nil, false
+	}
+	mapType, ok := field.Type.(*ast.MapType)
+	if !ok {
+		return nil, false
+	}
+
+	jsonTag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", "")).Get("json")
+	for _, option := range strings.Split(jsonTag, ",")[1:] {
+		if strings.TrimSpace(option) == "inline" {
+			return mapType, true
+		}
+	}
+	return nil, false
+}
+
 func fullTypeName(pkgName, typeName string) string {
 	if pkgName != "" {
 		return pkgName + "." + typeName
@@ -789,6 +1881,12 @@ func (parser *Parser) parseTypeExpr(file *ast.File, typeExpr ast.Expr, ref bool)
 		return spec.ArrayProperty(itemSchema), nil
 	// type Foo map[string]Bar
 	case *ast.MapType:
+		// json objects have string keys only
+		if keySchema, err := parser.parseTypeExpr(file, expr.Key, false); err != nil || !keySchema.Type.Contains(STRING) {
+			keyType, _ := getFieldType(expr.Key)
+			warnf(parser.debug, "Map key type '%s' is not a string. Using 'object' instead.", keyType)
+			break
+		}
 		if _, ok := expr.Value.(*ast.InterfaceType); ok {
 			return spec.MapProperty(nil), nil
 		}
@@ -799,9 +1897,12 @@ func (parser *Parser) parseTypeExpr(file *ast.File, typeExpr ast.Expr, ref bool)
 		return spec.MapProperty(schema), nil
 	case *ast.FuncType:
 		return nil, ErrFuncTypeField
+	// type Foo interface {...}
+	case *ast.InterfaceType:
+		return parser.interfaceSchema(file, expr)
 	// ...
 	default:
-		Printf("Type definition of type '%T' is not supported yet. Using 'object' instead.\n", typeExpr)
+		warnf(parser.debug, "Type definition of type '%T' is not supported yet. Using 'object' instead.", typeExpr)
 	}
 
 	return PrimitiveSchema(OBJECT), nil
@@ -811,7 +1912,17 @@ func (parser *Parser) parseStruct(file *ast.File, fields *ast.FieldList) (*spec.
 
 	required := make([]string, 0)
 	properties := make(map[string]spec.Schema)
+	var additionalProperties *spec.SchemaOrBool
 	for _, field := range fields.List {
+		if mapType, ok := inlineMapField(field); ok {
+			var err error
+			additionalProperties, err = parser.parseAdditionalPropertiesExpr(file, mapType.Value)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
 		fieldProps, requiredFromAnon, err := parser.parseStructField(file, field)
 		if err == ErrFuncTypeField {
 			continue
@@ -830,9 +1941,10 @@ func (parser *Parser) parseStruct(file *ast.File, fields *ast.FieldList) (*spec.
 
 	return &spec.Schema{
 		SchemaProps: spec.SchemaProps{
-			Type:       []string{OBJECT},
-			Properties: properties,
-			Required:   required,
+			Type:                 []string{OBJECT},
+			Properties:           properties,
+			Required:             required,
+			AdditionalProperties: additionalProperties,
 		}}, nil
 }
 
@@ -844,12 +1956,16 @@ type structField struct {
 	formatType   string
 	isRequired   bool
 	readOnly     bool
+	writeOnly    bool
 	crossPkg     string
 	exampleValue interface{}
 	maximum      *float64
 	minimum      *float64
 	maxLength    *int64
 	minLength    *int64
+	maxItems     *int64
+	minItems     *int64
+	uniqueItems  bool
 	enums        []interface{}
 	defaultValue interface{}
 	extensions   map[string]interface{}
@@ -857,11 +1973,8 @@ type structField struct {
 
 func (parser *Parser) parseStructField(file *ast.File, field *ast.Field) (map[string]spec.Schema, []string, error) {
 	if field.Names == nil {
-		if field.Tag != nil {
-			skip, ok := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", "")).Lookup("swaggerignore")
-			if ok && strings.EqualFold(skip, "true") {
-				return nil, nil, nil
-			}
+		if isIgnoredField(field) {
+			return nil, nil, nil
 		}
 
 		typeName, err := getFieldType(field.Type)
@@ -909,6 +2022,10 @@ func (parser *Parser) parseStructField(file *ast.File, field *ast.Field) (map[st
 	}
 
 	types := parser.GetSchemaTypePath(schema, 2)
+	if len(types) == 0 && isAnySchema(schema) {
+		// the tags of a field accepting any value, like an interface, are read as the ones of an object
+		types = []string{OBJECT}
+	}
 	if len(types) == 0 {
 		return nil, nil, fmt.Errorf("invalid type for field: %s", field.Names[0])
 	}
@@ -922,21 +2039,38 @@ func (parser *Parser) parseStructField(file *ast.File, field *ast.Field) (map[st
 		schema = PrimitiveSchema(structField.schemaType)
 	}
 
+	// the schema of a named type may be shared, copy it before applying the tags of this field
+	schema = copySchemaShallow(schema)
 	schema.Description = structField.desc
 	schema.ReadOnly = structField.readOnly
 	schema.Default = structField.defaultValue
 	schema.Example = structField.exampleValue
-	schema.Format = structField.formatType
-	schema.Extensions = structField.extensions
+	if structField.formatType != "" {
+		schema.Format = parser.checkFormat(structField.formatType)
+	}
+	if parser.Nullable && parser.isPointerType(field.Type, file) {
+		schema.Extensions = mergeExtensions(schema.Extensions, spec.Extensions{"x-nullable": true})
+	}
+	if structField.writeOnly {
+		// Swagger 2.0 has no writeOnly
+		schema.Extensions = mergeExtensions(schema.Extensions, spec.Extensions{"x-writeOnly": true})
+	}
+	schema.Extensions = mergeExtensions(schema.Extensions, structField.extensions)
 	eleSchema := schema
 	if structField.schemaType == "array" {
-		eleSchema = schema.Items.Schema
+		eleSchema = copySchemaShallow(schema.Items.Schema)
+		schema.Items = &spec.SchemaOrArray{Schema: eleSchema}
+		schema.MaxItems = structField.maxItems
+		schema.MinItems = structField.minItems
+		schema.UniqueItems = structField.uniqueItems
 	}
 	eleSchema.Maximum = structField.maximum
 	eleSchema.Minimum = structField.minimum
 	eleSchema.MaxLength = structField.maxLength
 	eleSchema.MinLength = structField.minLength
-	eleSchema.Enum = structField.enums
+	if structField.enums != nil {
+		eleSchema.Enum = structField.enums
+	}
 
 	var tagRequired []string
 	if structField.isRequired {
@@ -945,6 +2079,29 @@ func (parser *Parser) parseStructField(file *ast.File, field *ast.Field) (map[st
 	return map[string]spec.Schema{fieldName: *schema}, tagRequired, nil
 }
 
+func copySchemaShallow(schema *spec.Schema) *spec.Schema {
+	schemaCopy := *schema
+	return &schemaCopy
+}
+
+// mergeExtensions returns a new map of extensions containing the ones of both, extensions of b win
+func mergeExtensions(a, b spec.Extensions) spec.Extensions {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	extensions := make(spec.Extensions, len(a)+len(b))
+	for k, v := range a {
+		extensions[k] = v
+	}
+	for k, v := range b {
+		extensions[k] = v
+	}
+	return extensions
+}
+
 func getFieldType(field ast.Expr) (string, error) {
 	switch ftype := field.(type) {
 	case *ast.Ident:
@@ -966,34 +2123,90 @@ func getFieldType(field ast.Expr) (string, error) {
 	return "", fmt.Errorf("unknown field type %#v", field)
 }
 
+// isPointerType tells whether typeExpr is a pointer, written as such or through named types and aliases like
+// type OptionalInt *int
+func (parser *Parser) isPointerType(typeExpr ast.Expr, file *ast.File) bool {
+	seen := make(map[*TypeSpecDef]bool)
+	for {
+		if _, ok := typeExpr.(*ast.StarExpr); ok {
+			return true
+		}
+		typeName, err := getFieldType(typeExpr)
+		if err != nil {
+			return false
+		}
+		typeSpecDef := parser.packages.FindTypeSpec(typeName, file)
+		if typeSpecDef == nil || typeSpecDef.TypeSpec == nil || seen[typeSpecDef] {
+			return false
+		}
+		seen[typeSpecDef] = true
+		typeExpr, file = typeSpecDef.TypeSpec.Type, typeSpecDef.File
+	}
+}
+
+// structFieldsByName maps the property names of a struct type to their fields, it's empty for other types
+func (parser *Parser) structFieldsByName(typeSpecDef *TypeSpecDef) map[string]*ast.Field {
+	fields := make(map[string]*ast.Field)
+	if typeSpecDef == nil {
+		return fields
+	}
+	structType, ok := typeSpecDef.TypeSpec.Type.(*ast.StructType)
+	if !ok {
+		return fields
+	}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) != 1 {
+			continue
+		}
+		if name, _, err := parser.getFieldName(field); err == nil && name != "" {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+// isIgnoredField tells whether field, embedded or not, is tagged swaggerignore:"true"
+func isIgnoredField(field *ast.Field) bool {
+	if field.Tag == nil {
+		return false
+	}
+	ignoreTag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", "")).Get("swaggerignore")
+	return strings.EqualFold(ignoreTag, "true")
+}
+
 func (parser *Parser) getFieldName(field *ast.Field) (name string, schema *spec.Schema, err error) {
 	// Skip non-exported fields.
 	if !ast.IsExported(field.Names[0].Name) {
 		return "", nil, nil
 	}
 
+	var structTag reflect.StructTag
 	if field.Tag != nil {
 		// `json:"tag"` -> json:"tag"
-		structTag := reflect.StructTag(strings.Replace(field.Tag.Value, "`", "", -1))
-		if ignoreTag := structTag.Get("swaggerignore"); strings.EqualFold(ignoreTag, "true") {
-			return "", nil, nil
-		}
-
-		name = structTag.Get("json")
-		// json:"tag,hoge"
-		if name = strings.TrimSpace(strings.Split(name, ",")[0]); name == "-" {
+		structTag = reflect.StructTag(strings.Replace(field.Tag.Value, "`", "", -1))
+		if isIgnoredField(field) {
 			return "", nil, nil
 		}
 
-		if typeTag := structTag.Get("swaggertype"); typeTag != "" {
-			parts := strings.Split(typeTag, ",")
-			schema, err = BuildCustomSchema(parts)
+		if types, _, _ := swaggerTypeTag(structTag.Get("swaggertype")); len(types) > 0 {
+			schema, err = BuildCustomSchema(types)
 			if err != nil {
 				return "", nil, err
 			}
 		}
 	}
 
+	if parser.NameResolver != nil {
+		// an empty name skips the field
+		return parser.NameResolver(field.Names[0].Name, structTag), schema, nil
+	}
+
+	name = structTag.Get("json")
+	// json:"tag,hoge"
+	if name = strings.TrimSpace(strings.Split(name, ",")[0]); name == "-" {
+		return "", nil, nil
+	}
+
 	if name == "" {
 		switch parser.PropNamingStrategy {
 		case SnakeCase:
@@ -1009,6 +2222,26 @@ func (parser *Parser) getFieldName(field *ast.Field) (name string, schema *spec.
 	return name, schema, err
 }
 
+// swaggerTypeTag splits a swaggertype tag into the types documenting the field and its readonly and writeonly
+// options, like swaggertype:"primitive,string,readonly" or swaggertype:",writeonly"
+func swaggerTypeTag(tag string) (types []string, readOnly, writeOnly bool) {
+	if tag == "" {
+		return nil, false, false
+	}
+	for _, part := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(part) {
+		case "readonly":
+			readOnly = true
+		case "writeonly":
+			writeOnly = true
+		case "":
+		default:
+			types = append(types, part)
+		}
+	}
+	return types, readOnly, writeOnly
+}
+
 func (parser *Parser) parseFieldTag(field *ast.Field, types []string) (*structField, error) {
 	structField := &structField{
 		//    name:       field.Names[0].Name,
@@ -1026,10 +2259,16 @@ func (parser *Parser) parseFieldTag(field *ast.Field, types []string) (*structFi
 	}
 
 	if field.Tag == nil {
+		structField.isRequired = parser.RequiredByDefault
 		return structField, nil
 	}
 	// `json:"tag"` -> json:"tag"
 	structTag := reflect.StructTag(strings.Replace(field.Tag.Value, "`", "", -1))
+	structField.isRequired = parser.RequiredByDefault && !isOptionalField(structTag)
+
+	if descriptionTag := structTag.Get("description"); descriptionTag != "" {
+		structField.desc = descriptionTag
+	}
 
 	jsonTag := structTag.Get("json")
 	// json:"name,string" or json:",string"
@@ -1050,22 +2289,6 @@ func (parser *Parser) parseFieldTag(field *ast.Field, types []string) (*structFi
 	if formatTag := structTag.Get("format"); formatTag != "" {
 		structField.formatType = formatTag
 	}
-	if bindingTag := structTag.Get("binding"); bindingTag != "" {
-		for _, val := range strings.Split(bindingTag, ",") {
-			if val == "required" {
-				structField.isRequired = true
-				break
-			}
-		}
-	}
-	if validateTag := structTag.Get("validate"); validateTag != "" {
-		for _, val := range strings.Split(validateTag, ",") {
-			if val == "required" {
-				structField.isRequired = true
-				break
-			}
-		}
-	}
 	if extensionsTag := structTag.Get("extensions"); extensionsTag != "" {
 		structField.extensions = map[string]interface{}{}
 		for _, val := range strings.Split(extensionsTag, ",") {
@@ -1091,6 +2314,16 @@ func (parser *Parser) parseFieldTag(field *ast.Field, types []string) (*structFi
 			structField.enums = append(structField.enums, value)
 		}
 	}
+	if descriptionsTag := structTag.Get("enumDescriptions"); descriptionsTag != "" {
+		descriptions := splitEnumDescriptions(descriptionsTag)
+		if len(descriptions) != len(structField.enums) {
+			return nil, fmt.Errorf("enumDescriptions of field %s has %d descriptions for %d enums", field.Names[0].Name, len(descriptions), len(structField.enums))
+		}
+		if structField.extensions == nil {
+			structField.extensions = map[string]interface{}{}
+		}
+		structField.extensions["x-enum-descriptions"] = descriptions
+	}
 	if defaultTag := structTag.Get("default"); defaultTag != "" {
 		value, err := defineType(structField.schemaType, defaultTag)
 		if err != nil {
@@ -1125,9 +2358,25 @@ func (parser *Parser) parseFieldTag(field *ast.Field, types []string) (*structFi
 		}
 		structField.minLength = minLength
 	}
+	_, structField.readOnly, structField.writeOnly = swaggerTypeTag(structTag.Get("swaggertype"))
 	if readOnly := structTag.Get("readonly"); readOnly != "" {
 		structField.readOnly = readOnly == "true"
 	}
+	if writeOnly := structTag.Get("writeonly"); writeOnly != "" {
+		structField.writeOnly = writeOnly == "true"
+	}
+	if structField.readOnly && structField.writeOnly {
+		return nil, fmt.Errorf("field %s can't be both readonly and writeonly", field.Names[0].Name)
+	}
+	// the rules of binding and validate tags don't override the dedicated tags above, and binding is preferred to
+	// validate when they disagree
+	bindingTag := structTag.Get("binding")
+	structField.parseValidationTag(bindingTag, false)
+	bindingRequired := structField.isRequired
+	structField.parseValidationTag(structTag.Get("validate"), false)
+	if isRequiredDecidedBy(bindingTag) {
+		structField.isRequired = bindingRequired
+	}
 
 	// perform this after setting everything else (min, max, etc...)
 	if hasStringTag {
@@ -1156,14 +2405,185 @@ func (parser *Parser) parseFieldTag(field *ast.Field, types []string) (*structFi
 	return structField, nil
 }
 
-// GetSchemaTypePath get path of schema type
-func (parser *Parser) GetSchemaTypePath(schema *spec.Schema, depth int) []string {
-	if schema == nil || depth == 0 {
-		return nil
+// isOptionalField tells whether the tags of a field mark it optional with json:",omitempty", binding:"-" or an
+// omitempty rule of the binding and validate tags
+func isOptionalField(structTag reflect.StructTag) bool {
+	for _, option := range strings.Split(structTag.Get("json"), ",")[1:] {
+		if option == "omitempty" {
+			return true
+		}
 	}
-	if name := schema.Ref.String(); name != "" {
-		if pos := strings.LastIndexByte(name, '/'); pos >= 0 {
-			name = name[pos+1:]
+	if structTag.Get("binding") == "-" {
+		return true
+	}
+	for _, tagName := range []string{"binding", "validate"} {
+		for _, rule := range strings.Split(structTag.Get(tagName), ",") {
+			if rule == "omitempty" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRequiredDecidedBy tells whether a binding or validate tag decides if the field is required, with a required,
+// a conditional required or an omitempty rule, or by skipping the field with -
+func isRequiredDecidedBy(tag string) bool {
+	if tag == "-" {
+		return true
+	}
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "dive" {
+			break
+		}
+		name := strings.SplitN(rule, "=", 2)[0]
+		switch name {
+		case "required", "omitempty", "required_if", "required_with", "required_without":
+			return true
+		}
+	}
+	return false
+}
+
+// parseValidationTag reads the constraints of a binding or validate tag, like validate:"required,min=1,oneof=a b".
+// Rules whose value doesn't fit the type of the field are ignored. The length rules of an array field give its
+// number of items. The min, max, len and oneof rules of other fields are only read along with scalarRules, for
+// the parameters expanded from a struct, the properties of the models being left as they were.
+func (field *structField) parseValidationTag(tag string, scalarRules bool) {
+	if tag == "" {
+		return
+	}
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		// the rules after dive apply to the elements of the field
+		if rule == "dive" {
+			if field.schemaType == ARRAY {
+				field.parseElementRules(rules[i+1:])
+			}
+			break
+		}
+		name, value := rule, ""
+		if i := strings.Index(rule, "="); i != -1 {
+			name, value = rule[:i], rule[i+1:]
+		}
+		switch name {
+		case "required":
+			field.isRequired = true
+		case "unique":
+			field.uniqueItems = field.schemaType == ARRAY
+		case "required_if", "required_with", "required_without":
+			// the conditions aren't expressible in the schema, the raw rule is kept as an extension like
+			// x-required-if and the field isn't listed as required
+			field.isRequired = false
+			field.setConditionalRequired(name, value)
+		case "min", "gte":
+			if scalarRules || field.schemaType == ARRAY {
+				field.setMinimum(value)
+			}
+		case "max", "lte":
+			if scalarRules || field.schemaType == ARRAY {
+				field.setMaximum(value)
+			}
+		case "len":
+			if scalarRules || field.schemaType == ARRAY {
+				field.setMinimum(value)
+				field.setMaximum(value)
+			}
+		case "oneof":
+			if !scalarRules || field.enums != nil || field.schemaType == ARRAY {
+				continue
+			}
+			var enums []interface{}
+			for _, e := range strings.Fields(value) {
+				enum, err := defineType(field.schemaType, strings.Trim(e, "'"))
+				if err != nil {
+					enums = nil
+					break
+				}
+				enums = append(enums, enum)
+			}
+			field.enums = enums
+		}
+	}
+}
+
+// setConditionalRequired adds the extension holding the rule of a conditional required rule, unless the extensions
+// tag of the field already sets it
+func (field *structField) setConditionalRequired(name, value string) {
+	extension := "x-" + strings.ReplaceAll(name, "_", "-")
+	if _, ok := field.extensions[extension]; ok {
+		return
+	}
+	if field.extensions == nil {
+		field.extensions = map[string]interface{}{}
+	}
+	field.extensions[extension] = value
+}
+
+// parseElementRules reads the rules following the dive rule of an array field, which constrain its items like the
+// dedicated tags of the field do
+func (field *structField) parseElementRules(rules []string) {
+	element := &structField{schemaType: field.arrayType}
+	element.parseValidationTag(strings.Join(rules, ","), true)
+	if field.minimum == nil {
+		field.minimum = element.minimum
+	}
+	if field.maximum == nil {
+		field.maximum = element.maximum
+	}
+	if field.minLength == nil {
+		field.minLength = element.minLength
+	}
+	if field.maxLength == nil {
+		field.maxLength = element.maxLength
+	}
+	if field.enums == nil {
+		field.enums = element.enums
+	}
+}
+
+func (field *structField) setMinimum(value string) {
+	switch {
+	case field.schemaType == ARRAY && field.minItems == nil:
+		if minItems, err := strconv.ParseInt(value, 10, 64); err == nil {
+			field.minItems = &minItems
+		}
+	case IsNumericType(field.schemaType) && field.minimum == nil:
+		if minimum, err := strconv.ParseFloat(value, 64); err == nil {
+			field.minimum = &minimum
+		}
+	case field.schemaType == STRING && field.minLength == nil:
+		if minLength, err := strconv.ParseInt(value, 10, 64); err == nil {
+			field.minLength = &minLength
+		}
+	}
+}
+
+func (field *structField) setMaximum(value string) {
+	switch {
+	case field.schemaType == ARRAY && field.maxItems == nil:
+		if maxItems, err := strconv.ParseInt(value, 10, 64); err == nil {
+			field.maxItems = &maxItems
+		}
+	case IsNumericType(field.schemaType) && field.maximum == nil:
+		if maximum, err := strconv.ParseFloat(value, 64); err == nil {
+			field.maximum = &maximum
+		}
+	case field.schemaType == STRING && field.maxLength == nil:
+		if maxLength, err := strconv.ParseInt(value, 10, 64); err == nil {
+			field.maxLength = &maxLength
+		}
+	}
+}
+
+// GetSchemaTypePath get path of schema type
+func (parser *Parser) GetSchemaTypePath(schema *spec.Schema, depth int) []string {
+	if schema == nil || depth == 0 {
+		return nil
+	}
+	if name := schema.Ref.String(); name != "" {
+		if pos := strings.LastIndexByte(name, '/'); pos >= 0 {
+			name = name[pos+1:]
 			if schema, ok := parser.swagger.Definitions[name]; ok {
 				return parser.GetSchemaTypePath(&schema, depth)
 			}
@@ -1186,6 +2606,37 @@ func (parser *Parser) GetSchemaTypePath(schema *spec.Schema, depth int) []string
 	return nil
 }
 
+// dedupeTags merges the tags declared more than once by @tag.name into the first one, whose place is kept, the
+// description, docs and extensions it lacks being taken from the others
+func dedupeTags(tags []spec.Tag) []spec.Tag {
+	var deduped []spec.Tag
+	index := make(map[string]int, len(tags))
+	for _, tag := range tags {
+		i, ok := index[tag.Name]
+		if !ok {
+			index[tag.Name] = len(deduped)
+			deduped = append(deduped, tag)
+			continue
+		}
+		first := &deduped[i]
+		if first.Description == "" {
+			first.Description = tag.Description
+		}
+		if first.ExternalDocs == nil {
+			first.ExternalDocs = tag.ExternalDocs
+		}
+		for name, value := range tag.Extensions {
+			if _, ok := first.Extensions[name]; !ok {
+				if first.Extensions == nil {
+					first.Extensions = spec.Extensions{}
+				}
+				first.Extensions[name] = value
+			}
+		}
+	}
+	return deduped
+}
+
 func replaceLastTag(slice []spec.Tag, element spec.Tag) {
 	slice = slice[:len(slice)-1]
 	slice = append(slice, element)
@@ -1313,22 +2764,96 @@ func defineTypeOfExample(schemaType, arrayType, exampleValue string) (interface{
 
 // GetAllGoFileInfo gets all Go source files information for given searchDir.
 func (parser *Parser) getAllGoFileInfo(packageDir, searchDir string) error {
-	return filepath.Walk(searchDir, func(path string, f os.FileInfo, err error) error {
+	// the packages of the modules replaced by a directory of the search dir have the import path of these modules
+	mod, err := findGoModule(searchDir)
+	if err != nil {
+		warnf(parser.debug, "failed to read the go.mod of %s: %s", searchDir, err)
+	}
+
+	var files []goFile
+	err = filepath.Walk(searchDir, func(path string, f os.FileInfo, err error) error {
 		if err := parser.Skip(path, f); err != nil {
 			return err
-		} else if f.IsDir() {
-			return nil
 		}
 
 		relPath, err := filepath.Rel(searchDir, path)
 		if err != nil {
 			return err
 		}
-		return parser.parseFile(filepath.ToSlash(filepath.Dir(filepath.Clean(filepath.Join(packageDir, relPath)))), path, nil)
+		if parser.isExcluded(relPath) {
+			if f.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if f.IsDir() {
+			return nil
+		}
+		if mod != nil {
+			if absDir, err := filepath.Abs(filepath.Dir(path)); err == nil {
+				if importPath, ok := mod.replacedImportPath(absDir); ok {
+					files = append(files, goFile{packageDir: importPath, path: path})
+					return nil
+				}
+			}
+		}
+		files = append(files, goFile{packageDir: vendoredPackagePath(filepath.ToSlash(filepath.Dir(filepath.Clean(filepath.Join(packageDir, relPath))))), path: path})
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+	return parser.parseFiles(files)
+}
+
+// vendoredPackagePath returns the import path of a package of a vendor folder, like github.com/pkg/errors for
+// example.com/app/vendor/github.com/pkg/errors, so that the imports of the vendored packages resolve. Other package
+// paths are returned as is
+func vendoredPackagePath(pkgPath string) string {
+	if i := strings.LastIndex(pkgPath, "/vendor/"); i != -1 {
+		return pkgPath[i+len("/vendor/"):]
+	}
+	return strings.TrimPrefix(pkgPath, "vendor/")
+}
+
+// hasVendorDir tells whether the search dir holds a vendor folder
+func (parser *Parser) hasVendorDir() bool {
+	if parser.FileSystem != nil {
+		info, err := fs.Stat(parser.FileSystem, path.Join(parser.searchDir, "vendor"))
+		return err == nil && info.IsDir()
+	}
+	info, err := os.Stat(filepath.Join(parser.searchDir, "vendor"))
+	return err == nil && info.IsDir()
 }
 
-func (parser *Parser) getAllGoFileInfoFromDeps(pkg *depth.Pkg) error {
+// getAllGoFileInfoFromDepTree parses the files of the packages found by resolving the imports of the package
+// in mainDir up to parseDepth
+func (parser *Parser) getAllGoFileInfoFromDepTree(searchDir, mainDir string, parseDepth int) error {
+	var t depth.Tree
+	t.ResolveInternal = true
+	t.MaxDepth = parseDepth
+	// the packages of the module and of the modules it replaces by a local directory are found through go.mod,
+	// whatever the working directory
+	if mod, err := findGoModule(mainDir); err == nil && mod != nil {
+		t.Importer = &moduleImporter{mod: mod}
+	}
+
+	pkgName, err := getPkgName(mainDir)
+	if err != nil {
+		return err
+	}
+	if err := t.Resolve(pkgName); err != nil {
+		return fmt.Errorf("pkg %s cannot find all dependencies, %s", pkgName, err)
+	}
+	for i := 0; i < len(t.Root.Deps); i++ {
+		if err := parser.getAllGoFileInfoFromDeps(searchDir, &t.Root.Deps[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (parser *Parser) getAllGoFileInfoFromDeps(searchDir string, pkg *depth.Pkg) error {
 	ignoreInternal := pkg.Internal && !parser.ParseInternal
 	if ignoreInternal || !pkg.Resolved { // ignored internal and not resolved dependencies
 		return nil
@@ -1339,24 +2864,27 @@ func (parser *Parser) getAllGoFileInfoFromDeps(pkg *depth.Pkg) error {
 		return nil
 	}
 	srcDir := pkg.Raw.Dir
+	if relDir, err := filepath.Rel(searchDir, srcDir); err == nil && parser.isExcluded(relDir) || parser.isExcluded(pkg.Name) {
+		return nil
+	}
+
 	files, err := ioutil.ReadDir(srcDir) // only parsing files in the dir(don't contains sub dir files)
 	if err != nil {
 		return err
 	}
 
+	goFiles := make([]goFile, 0, len(files))
 	for _, f := range files {
-		if f.IsDir() {
-			continue
-		}
-
-		path := filepath.Join(srcDir, f.Name())
-		if err := parser.parseFile(pkg.Name, path, nil); err != nil {
-			return err
+		if !f.IsDir() {
+			goFiles = append(goFiles, goFile{packageDir: pkg.Name, path: filepath.Join(srcDir, f.Name())})
 		}
 	}
+	if err := parser.parseFiles(goFiles); err != nil {
+		return err
+	}
 
 	for i := 0; i < len(pkg.Deps); i++ {
-		if err := parser.getAllGoFileInfoFromDeps(&pkg.Deps[i]); err != nil {
+		if err := parser.getAllGoFileInfoFromDeps(searchDir, &pkg.Deps[i]); err != nil {
 			return err
 		}
 	}
@@ -1364,20 +2892,211 @@ func (parser *Parser) getAllGoFileInfoFromDeps(pkg *depth.Pkg) error {
 	return nil
 }
 
+// goListPackage is the part of a package printed by go list -json used to find its files
+type goListPackage struct {
+	Dir        string
+	ImportPath string
+	GoFiles    []string
+	Standard   bool
+	DepOnly    bool
+}
+
+// getAllGoFileInfoFromGoList parses the files of the packages imported directly or not by the package in mainDir
+func (parser *Parser) getAllGoFileInfoFromGoList(searchDir, mainDir string) error {
+	cmd := exec.Command("go", "list", "-deps", "-json")
+	cmd.Dir = mainDir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("execute go list command, %s, stderr:%s", err, stderr.String())
+	}
+
+	var pkgs []goListPackage
+	decoder := json.NewDecoder(&stdout)
+	for decoder.More() {
+		var pkg goListPackage
+		if err := decoder.Decode(&pkg); err != nil {
+			return fmt.Errorf("decode go list output, %s", err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	var files []goFile
+	for _, pkg := range pkgs {
+		// the main package itself is found in the search dir
+		if !pkg.DepOnly || pkg.Standard && !parser.ParseInternal {
+			continue
+		}
+		if relDir, err := filepath.Rel(searchDir, pkg.Dir); err == nil && parser.isExcluded(relDir) || parser.isExcluded(pkg.ImportPath) {
+			continue
+		}
+		for _, fileName := range pkg.GoFiles {
+			files = append(files, goFile{packageDir: pkg.ImportPath, path: filepath.Join(pkg.Dir, fileName)})
+		}
+	}
+	return parser.parseFiles(files)
+}
+
 func (parser *Parser) parseFile(packageDir, path string, src interface{}) error {
-	if strings.HasSuffix(strings.ToLower(path), "_test.go") || filepath.Ext(path) != ".go" {
+	return parser.parseFiles([]goFile{{packageDir: packageDir, path: path, src: src}})
+}
+
+// goFile is a Go source file to parse along with the import path of its package, src is read from path when nil
+type goFile struct {
+	packageDir string
+	path       string
+	src        interface{}
+}
+
+// parseFiles parses the files with Parallelism workers and collects them in their order, the first file failing to
+// parse being the one reported whatever the parallelism
+func (parser *Parser) parseFiles(files []goFile) error {
+	goFiles := make([]goFile, 0, len(files))
+	for _, file := range files {
+		if !strings.HasSuffix(strings.ToLower(file.path), "_test.go") && filepath.Ext(file.path) == ".go" {
+			goFiles = append(goFiles, file)
+		}
+	}
+
+	astFiles := make([]*ast.File, len(goFiles))
+	errs := make([]error, len(goFiles))
+	workers := parser.Parallelism
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(goFiles) {
+		workers = len(goFiles)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// positions are relative to FileSet, which is safe for concurrent use
+				astFiles[i], errs[i] = goparser.ParseFile(parser.fileSet, goFiles[i].path, goFiles[i].src, goparser.ParseComments)
+			}
+		}()
+	}
+	for i := range goFiles {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, file := range goFiles {
+		if errs[i] != nil {
+			if err := parser.skipFile(file.path, fmt.Errorf("ParseFile error:%+v", errs[i])); err != nil {
+				return err
+			}
+			continue
+		}
+		parser.packages.CollectAstFile(file.packageDir, file.path, astFiles[i])
+	}
+	return nil
+}
+
+// parseRouterAPIInfo parses the operations of a file like ParseRouterAPIInfo, with ContinueOnError the operations
+// of a file failing to parse are dropped and the file is skipped
+func (parser *Parser) parseRouterAPIInfo(fileName string, astFile *ast.File) error {
+	if !parser.ContinueOnError {
+		return parser.ParseRouterAPIInfo(fileName, astFile)
+	}
+
+	paths := make(map[string]spec.PathItem, len(parser.swagger.Paths.Paths))
+	for path, pathItem := range parser.swagger.Paths.Paths {
+		paths[path] = pathItem
+	}
+	routes := make(map[string]routeHandler, len(parser.routes))
+	for route, handler := range parser.routes {
+		routes[route] = handler
+	}
+	if err := parser.ParseRouterAPIInfo(fileName, astFile); err != nil {
+		parser.swagger.Paths.Paths = paths
+		parser.routes = routes
+		return parser.skipFile(fileName, err)
+	}
+	return nil
+}
+
+// skipFile records the error of a file and returns nil when ContinueOnError is set, it returns err otherwise
+func (parser *Parser) skipFile(fileName string, err error) error {
+	if !parser.ContinueOnError {
+		return err
+	}
+	warnf(parser.debug, "skipping %s: %s", fileName, err)
+	if parser.skippedFiles == nil {
+		parser.skippedFiles = make(map[string]error)
+	}
+	parser.skippedFiles[fileName] = err
+	return nil
+}
+
+// checkSkippedFiles returns an error listing the skipped files if none of the files could be parsed, it logs them
+// otherwise
+func (parser *Parser) checkSkippedFiles() error {
+	if len(parser.skippedFiles) == 0 {
 		return nil
 	}
+	fileNames := make([]string, 0, len(parser.skippedFiles))
+	for fileName := range parser.skippedFiles {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	parsed := false
+	for _, info := range parser.packages.files {
+		if _, ok := parser.skippedFiles[info.Path]; !ok {
+			parsed = true
+			break
+		}
+	}
+	if !parsed {
+		return fmt.Errorf("no file could be parsed, skipped: %s", strings.Join(fileNames, ", "))
+	}
+	warnf(parser.debug, "skipped %d files failing to parse: %s", len(fileNames), strings.Join(fileNames, ", "))
+	return nil
+}
+
+// registerRoute records the handler of an operation, returning an error if another handler already declared
+// the same method and path
+func (parser *Parser) registerRoute(operation *Operation, handler *ast.FuncDecl, fileName string) error {
+	if parser.routes == nil {
+		parser.routes = make(map[string]routeHandler)
+	}
 
-	// positions are relative to FileSet
-	astFile, err := goparser.ParseFile(token.NewFileSet(), path, src, goparser.ParseComments)
+	// a file may be parsed both from the search dir and as a dependency, compare absolute paths
+	absFileName, err := filepath.Abs(fileName)
 	if err != nil {
-		return fmt.Errorf("ParseFile error:%+v", err)
+		absFileName = fileName
+	}
+	current := routeHandler{
+		id:       fmt.Sprintf("%s:%d:%s", absFileName, parser.fileSet.Position(handler.Pos()).Line, handler.Name.Name),
+		location: fmt.Sprintf("%s (%s)", handler.Name.Name, parser.position(fileName, handler.Pos())),
+	}
+	route := fmt.Sprintf("%s %s", strings.ToUpper(operation.HTTPMethod), operation.Path)
+	if previous, ok := parser.routes[route]; ok && previous.id != current.id {
+		return fmt.Errorf("duplicated route '%s' found in %s, previously declared in: %s", route, current.location, previous.location)
 	}
-	parser.packages.CollectAstFile(packageDir, path, astFile)
+	parser.routes[route] = current
 	return nil
 }
 
+// position returns the file name and the line of pos when the file was parsed by the parser, the file name only otherwise
+func (parser *Parser) position(fileName string, pos token.Pos) string {
+	if parser.fileSet == nil {
+		return fileName
+	}
+	if file := parser.fileSet.File(pos); file != nil && file.Name() == fileName {
+		return fmt.Sprintf("%s:%d", fileName, file.Line(pos))
+	}
+	return fileName
+}
+
 func (parser *Parser) checkOperationIDUniqueness() error {
 	// operationsIds contains all operationId annotations to check it's unique
 	operationsIds := make(map[string]string)
@@ -1387,40 +3106,346 @@ func (parser *Parser) checkOperationIDUniqueness() error {
 		}
 		if previousPath, ok := operationsIds[operationID]; ok {
 			return fmt.Errorf(
-				"duplicated @id annotation '%s' found in '%s', previously declared in: '%s'",
+				"duplicated operationId '%s' found in '%s', previously declared in: '%s'",
 				operationID, currentPath, previousPath)
 		}
 		operationsIds[operationID] = currentPath
 		return nil
 	}
-	getOperationID := func(itm spec.PathItem) (string, string) {
-		if itm.Get != nil {
-			return "GET", itm.Get.ID
+
+	paths := make([]string, 0, len(parser.swagger.Paths.Paths))
+	for path := range parser.swagger.Paths.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		itm := parser.swagger.Paths.Paths[path]
+		for _, op := range []struct {
+			method    string
+			operation *spec.Operation
+		}{
+			{http.MethodGet, itm.Get},
+			{http.MethodPut, itm.Put},
+			{http.MethodPost, itm.Post},
+			{http.MethodDelete, itm.Delete},
+			{http.MethodOptions, itm.Options},
+			{http.MethodHead, itm.Head},
+			{http.MethodPatch, itm.Patch},
+		} {
+			if op.operation == nil {
+				continue
+			}
+			if err := saveOperationID(op.operation.ID, fmt.Sprintf("%s %s", op.method, path)); err != nil {
+				return err
+			}
 		}
-		if itm.Put != nil {
-			return "PUT", itm.Put.ID
+	}
+	return nil
+}
+
+// parseDefaultResponses parses the responses of the @failure.default annotations, their types are resolved in
+// the file declaring them
+func (parser *Parser) parseDefaultResponses() error {
+	if len(parser.defaultResponseComments) == 0 {
+		return nil
+	}
+
+	operation := NewOperation(parser)
+	for _, comment := range parser.defaultResponseComments {
+		if err := operation.ParseResponseComment(comment.value, parser.findAstFile(comment.fileName)); err != nil {
+			return fmt.Errorf("@failure.default in %s: %s", comment.fileName, err)
 		}
-		if itm.Post != nil {
-			return "POST", itm.Post.ID
+	}
+	parser.defaultResponses = operation.Responses
+	return nil
+}
+
+// applyDefaultResponses adds the default responses to every operation lacking a response of the same code
+func (parser *Parser) applyDefaultResponses() {
+	if parser.defaultResponses == nil {
+		return
+	}
+
+	for _, itm := range parser.swagger.Paths.Paths {
+		for _, operation := range []*spec.Operation{itm.Get, itm.Put, itm.Post, itm.Delete, itm.Options, itm.Head, itm.Patch} {
+			if operation == nil {
+				continue
+			}
+			if operation.Responses == nil {
+				operation.Responses = &spec.Responses{}
+			}
+			if operation.Responses.Default == nil {
+				operation.Responses.Default = parser.defaultResponses.Default
+			}
+			for code, response := range parser.defaultResponses.StatusCodeResponses {
+				if operation.Responses.StatusCodeResponses == nil {
+					operation.Responses.StatusCodeResponses = make(map[int]spec.Response)
+				}
+				if _, ok := operation.Responses.StatusCodeResponses[code]; !ok {
+					operation.Responses.StatusCodeResponses[code] = response
+				}
+			}
+		}
+	}
+}
+
+// applyDefaultSchemesAndMimeTypes sets DefaultSchemes as the schemes of the spec lacking @schemes, and
+// DefaultConsumes and DefaultProduces as the mime types of the spec and of the operations lacking @Accept or @Produce
+func (parser *Parser) applyDefaultSchemesAndMimeTypes() error {
+	if len(parser.swagger.Schemes) == 0 {
+		parser.swagger.Schemes = parser.DefaultSchemes
+	}
+
+	var consumes, produces []string
+	if err := parseMimeTypeList(strings.Join(parser.DefaultConsumes, ","), &consumes, "%v default consume type can't be accepted"); err != nil {
+		return err
+	}
+	if err := parseMimeTypeList(strings.Join(parser.DefaultProduces, ","), &produces, "%v default produce type can't be accepted"); err != nil {
+		return err
+	}
+	if len(consumes) == 0 && len(produces) == 0 {
+		return nil
+	}
+	if len(parser.swagger.Consumes) == 0 {
+		parser.swagger.Consumes = consumes
+	}
+	if len(parser.swagger.Produces) == 0 {
+		parser.swagger.Produces = produces
+	}
+
+	for _, itm := range parser.swagger.Paths.Paths {
+		for _, operation := range []*spec.Operation{itm.Get, itm.Put, itm.Post, itm.Delete, itm.Options, itm.Head, itm.Patch} {
+			if operation == nil {
+				continue
+			}
+			if len(operation.Consumes) == 0 {
+				operation.Consumes = consumes
+			}
+			if len(operation.Produces) == 0 {
+				operation.Produces = produces
+			}
+		}
+	}
+	return nil
+}
+
+// applyLocale makes the summary and the description of Locale, written by @Summary[locale] and
+// @Description[locale], the ones of the operations having them
+func (parser *Parser) applyLocale() {
+	if parser.Locale == "" {
+		return
+	}
+	locale := strings.ToLower(parser.Locale)
+	for _, itm := range parser.swagger.Paths.Paths {
+		for _, operation := range []*spec.Operation{itm.Get, itm.Put, itm.Post, itm.Delete, itm.Options, itm.Head, itm.Patch} {
+			if operation == nil {
+				continue
+			}
+			if summary, ok := operation.Extensions.GetString("x-summary-" + locale); ok {
+				operation.Summary = summary
+			}
+			if description, ok := operation.Extensions.GetString("x-description-" + locale); ok {
+				operation.Description = description
+			}
+		}
+	}
+}
+
+// matchTags tells whether tags pass the filter set by SetTags
+func (parser *Parser) matchTags(tags []string) bool {
+	included := true
+	for _, include := range parser.tags {
+		if include {
+			included = false
+			break
+		}
+	}
+	for _, tag := range tags {
+		include, ok := parser.tags[tag]
+		if !ok {
+			continue
 		}
-		if itm.Delete != nil {
-			return "DELETE", itm.Delete.ID
+		if !include {
+			return false
 		}
-		if itm.Options != nil {
-			return "OPTIONS", itm.Options.ID
+		included = true
+	}
+	return included
+}
+
+// filterOperationsByTags removes the operations whose tags don't pass the filter set by SetTags, then the tags and
+// the definitions no longer used
+func (parser *Parser) filterOperationsByTags() {
+	if len(parser.tags) == 0 {
+		return
+	}
+	FilterOperations(parser.swagger, parser.matchTags)
+}
+
+// FilterOperations removes the operations of swagger whose tags are not kept by keep, as well as the paths left
+// without operation, the tags not kept and the definitions no longer used.
+func FilterOperations(swagger *spec.Swagger, keep func(tags []string) bool) {
+	for path, itm := range swagger.Paths.Paths {
+		empty := true
+		for _, operation := range []**spec.Operation{&itm.Get, &itm.Put, &itm.Post, &itm.Delete, &itm.Options, &itm.Head, &itm.Patch} {
+			if *operation == nil {
+				continue
+			}
+			if !keep((*operation).Tags) {
+				*operation = nil
+				continue
+			}
+			empty = false
 		}
-		if itm.Head != nil {
-			return "HEAD", itm.Head.ID
+		if empty {
+			delete(swagger.Paths.Paths, path)
+		} else {
+			swagger.Paths.Paths[path] = itm
 		}
-		if itm.Patch != nil {
-			return "PATCH", itm.Patch.ID
+	}
+
+	var tags []spec.Tag
+	for _, tag := range swagger.Tags {
+		if keep([]string{tag.Name}) {
+			tags = append(tags, tag)
 		}
-		return "", ""
 	}
+	swagger.Tags = tags
+
+	PruneUnusedDefinitions(swagger)
+}
+
+// removeHiddenOperations removes the operations annotated with @Hidden unless IncludeHidden is set, as well as the
+// paths left without operation and the definitions used only by these operations
+func (parser *Parser) removeHiddenOperations() {
+	if parser.IncludeHidden || len(parser.hiddenOperations) == 0 {
+		return
+	}
+
+	usedBefore := usedDefinitions(parser.swagger)
 	for path, itm := range parser.swagger.Paths.Paths {
-		method, id := getOperationID(itm)
-		if err := saveOperationID(id, fmt.Sprintf("%s %s", method, path)); err != nil {
-			return err
+		empty := true
+		for _, operation := range []**spec.Operation{&itm.Get, &itm.Put, &itm.Post, &itm.Delete, &itm.Options, &itm.Head, &itm.Patch} {
+			if *operation == nil {
+				continue
+			}
+			if parser.hiddenOperations[*operation] {
+				*operation = nil
+				continue
+			}
+			empty = false
+		}
+		if empty {
+			delete(parser.swagger.Paths.Paths, path)
+		} else {
+			parser.swagger.Paths.Paths[path] = itm
+		}
+	}
+
+	usedAfter := usedDefinitions(parser.swagger)
+	for name := range usedBefore {
+		if !usedAfter[name] {
+			debugf(parser.debug, "Pruning definition %s used only by hidden operations", name)
+			delete(parser.swagger.Definitions, name)
+		}
+	}
+}
+
+// pruneUnusedDefinitions removes the definitions not referenced by the operations, directly or through other
+// definitions
+func (parser *Parser) pruneUnusedDefinitions() {
+	for _, name := range PruneUnusedDefinitions(parser.swagger) {
+		debugf(parser.debug, "Pruning unused definition %s", name)
+	}
+}
+
+// PruneUnusedDefinitions removes the definitions of swagger not referenced by its operations, directly or through
+// other definitions, and returns their names.
+func PruneUnusedDefinitions(swagger *spec.Swagger) []string {
+	used := usedDefinitions(swagger)
+	var pruned []string
+	for name := range swagger.Definitions {
+		if !used[name] {
+			pruned = append(pruned, name)
+			delete(swagger.Definitions, name)
+		}
+	}
+	sort.Strings(pruned)
+	return pruned
+}
+
+// usedDefinitions returns the names of the definitions of swagger referenced by its operations, directly or through
+// other definitions
+func usedDefinitions(swagger *spec.Swagger) map[string]bool {
+	var refs []spec.Ref
+	for path, itm := range swagger.Paths.Paths {
+		if !strings.HasPrefix(path, "/") {
+			// the functions lacking @Router are stored under an empty path, which isn't written
+			continue
+		}
+		for _, operation := range []*spec.Operation{itm.Get, itm.Put, itm.Post, itm.Delete, itm.Options, itm.Head, itm.Patch} {
+			if operation != nil {
+				refs = append(refs, operationRefs(operation)...)
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for len(refs) > 0 {
+		ref := refs[len(refs)-1]
+		refs = refs[:len(refs)-1]
+		parts := strings.Split(ref.GetURL().Fragment, "/")
+		name := parts[len(parts)-1]
+		if used[name] {
+			continue
+		}
+		used[name] = true
+		if schema, ok := swagger.Definitions[name]; ok {
+			refs = append(refs, schemaRefs(&schema)...)
+		}
+	}
+	return used
+}
+
+// operationRefs returns the $ref of the schemas of the parameters and responses of operation
+func operationRefs(operation *spec.Operation) []spec.Ref {
+	var refs []spec.Ref
+	for _, param := range operation.Parameters {
+		refs = append(refs, schemaRefs(param.Schema)...)
+	}
+	if operation.Responses != nil {
+		if operation.Responses.Default != nil {
+			refs = append(refs, schemaRefs(operation.Responses.Default.Schema)...)
+		}
+		for _, response := range operation.Responses.StatusCodeResponses {
+			refs = append(refs, schemaRefs(response.Schema)...)
+		}
+	}
+	return refs
+}
+
+// schemaRefs returns the $ref found in schema and the schemas nested in it, without following them
+func schemaRefs(schema *spec.Schema) []spec.Ref {
+	var refs []spec.Ref
+	_ = walkSchema(schema, func(schema *spec.Schema) error {
+		if schema.Ref.GetURL() != nil {
+			refs = append(refs, schema.Ref)
+		}
+		return nil
+	})
+	return refs
+}
+
+// findAstFile returns the collected file of fileName, nil if it isn't collected
+func (parser *Parser) findAstFile(fileName string) *ast.File {
+	absFileName, err := filepath.Abs(fileName)
+	if err != nil {
+		return nil
+	}
+	for astFile, info := range parser.packages.files {
+		if absPath, err := filepath.Abs(info.Path); err == nil && absPath == absFileName {
+			return astFile
 		}
 	}
 	return nil
@@ -1435,16 +3460,37 @@ func (parser *Parser) Skip(path string, f os.FileInfo) error {
 			return filepath.SkipDir
 		}
 
-		if parser.excludes != nil {
-			if _, ok := parser.excludes[path]; ok {
-				return filepath.SkipDir
-			}
+		if parser.isExcluded(path) {
+			return filepath.SkipDir
 		}
 	}
 
 	return nil
 }
 
+// isExcluded reports whether path, or one of its parent directories, matches one of the excludes
+func (parser *Parser) isExcluded(path string) bool {
+	if len(parser.excludes) == 0 {
+		return false
+	}
+
+	path = filepath.Clean(path)
+	for ; path != "." && path != string(filepath.Separator); path = filepath.Dir(path) {
+		if parser.excludes[path] {
+			return true
+		}
+		for pattern := range parser.excludes {
+			if matched, _ := filepath.Match(pattern, path); matched {
+				return true
+			}
+		}
+		if filepath.Dir(path) == path {
+			break
+		}
+	}
+	return false
+}
+
 // GetSwagger returns *spec.Swagger which is the root document object for the API specification.
 func (parser *Parser) GetSwagger() *spec.Swagger {
 	return parser.swagger
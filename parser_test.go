@@ -2,13 +2,20 @@ package swag
 
 import (
 	"encoding/json"
+	"fmt"
 	goparser "go/parser"
 	"go/token"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/go-openapi/spec"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,7 +71,7 @@ func TestParser_ParseGeneralApiInfo(t *testing.T) {
             "authorizationUrl": "https://example.com/oauth/authorize",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information"
+                "admin": "Grants read and write access to administrative information"
             },
             "x-tokenName": "id_token"
         },
@@ -73,8 +80,8 @@ func TestParser_ParseGeneralApiInfo(t *testing.T) {
             "flow": "application",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "write": "Grants write access"
             }
         },
         "OAuth2Implicit": {
@@ -82,8 +89,8 @@ func TestParser_ParseGeneralApiInfo(t *testing.T) {
             "flow": "implicit",
             "authorizationUrl": "https://example.com/oauth/authorize",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "write": "Grants write access"
             }
         },
         "OAuth2Password": {
@@ -91,9 +98,9 @@ func TestParser_ParseGeneralApiInfo(t *testing.T) {
             "flow": "password",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "read": " Grants read access",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "read": "Grants read access",
+                "write": "Grants write access"
             }
         }
     },
@@ -146,7 +153,7 @@ func TestParser_ParseGeneralApiInfoTemplated(t *testing.T) {
             "authorizationUrl": "https://example.com/oauth/authorize",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information"
+                "admin": "Grants read and write access to administrative information"
             }
         },
         "OAuth2Application": {
@@ -154,8 +161,8 @@ func TestParser_ParseGeneralApiInfoTemplated(t *testing.T) {
             "flow": "application",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "write": "Grants write access"
             }
         },
         "OAuth2Implicit": {
@@ -163,8 +170,8 @@ func TestParser_ParseGeneralApiInfoTemplated(t *testing.T) {
             "flow": "implicit",
             "authorizationUrl": "https://example.com/oauth/authorize",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "write": "Grants write access"
             }
         },
         "OAuth2Password": {
@@ -172,9 +179,9 @@ func TestParser_ParseGeneralApiInfoTemplated(t *testing.T) {
             "flow": "password",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "read": " Grants read access",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "read": "Grants read access",
+                "write": "Grants write access"
             }
         }
     },
@@ -245,6 +252,38 @@ func TestParser_ParseGeneralApiInfoWithOpsInSameFile(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParser_ParseGeneralApiInfoMultilineDescription(t *testing.T) {
+	expected := `{
+    "swagger": "2.0",
+    "info": {
+        "description": "This is a sample server.\n\nIt serves pets:\n` + "```" + `json\n{\n    \"id\": 1,\n    \"name\": \"doggie\"\n}\n` + "```" + `\nSee the docs for more.",
+        "title": "Swagger Example API",
+        "contact": {},
+        "version": "1.0"
+    },
+    "basePath": "/v1",
+    "paths": {}
+}`
+
+	p := New()
+	err := p.ParseGeneralAPIInfo("testdata/description/main.go")
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, expected, string(b))
+}
+
+func TestParser_ParseGeneralApiInfoDescriptionFile(t *testing.T) {
+	p := New()
+	err := p.ParseAPI("testdata/description_file", "main.go", defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Pet store\n\nThis is a **sample** server.\n", p.swagger.Info.Description)
+
+	p = New()
+	err = p.ParseAPI("testdata/description", "../description_file/main.go", defaultParseDepth)
+	assert.EqualError(t, err, "failed to read description file description.md: open testdata/description/description.md: no such file or directory")
+}
+
 func TestParser_ParseGeneralApiInfoFailed(t *testing.T) {
 	gopath := os.Getenv("GOPATH")
 	assert.NotNil(t, gopath)
@@ -528,7 +567,8 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
             "type": "object",
             "properties": {
                 "created_at": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "date-time"
                 },
                 "error_code": {
                     "type": "integer"
@@ -612,9 +652,7 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
                         "type": "string"
                     }
                 },
-                "data": {
-                    "type": "object"
-                },
+                "data": {},
                 "decimal": {
                     "type": "number"
                 },
@@ -678,7 +716,8 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
             "type": "object",
             "properties": {
                 "deleted_at": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "date-time"
                 },
                 "id": {
                     "type": "integer"
@@ -736,7 +775,7 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
             "authorizationUrl": "https://example.com/oauth/authorize",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information"
+                "admin": "Grants read and write access to administrative information"
             }
         },
         "OAuth2Application": {
@@ -744,8 +783,8 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
             "flow": "application",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "write": "Grants write access"
             }
         },
         "OAuth2Implicit": {
@@ -753,8 +792,8 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
             "flow": "implicit",
             "authorizationUrl": "https://example.com/oauth/authorize",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "write": "Grants write access"
             }
         },
         "OAuth2Password": {
@@ -762,9 +801,9 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
             "flow": "password",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "read": " Grants read access",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "read": "Grants read access",
+                "write": "Grants write access"
             }
         }
     }
@@ -1011,7 +1050,8 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
             "type": "object",
             "properties": {
                 "createdAt": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "date-time"
                 },
                 "errorCode": {
                     "type": "integer"
@@ -1071,9 +1111,7 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
                         }
                     }
                 },
-                "data": {
-                    "type": "object"
-                },
+                "data": {},
                 "decimal": {
                     "type": "number"
                 },
@@ -1134,7 +1172,8 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
             "type": "object",
             "properties": {
                 "deletedAt": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "date-time"
                 },
                 "id": {
                     "type": "integer"
@@ -1192,7 +1231,7 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
             "authorizationUrl": "https://example.com/oauth/authorize",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information"
+                "admin": "Grants read and write access to administrative information"
             }
         },
         "OAuth2Application": {
@@ -1200,8 +1239,8 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
             "flow": "application",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "write": "Grants write access"
             }
         },
         "OAuth2Implicit": {
@@ -1209,8 +1248,8 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
             "flow": "implicit",
             "authorizationUrl": "https://example.com/oauth/authorize",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "write": "Grants write access"
             }
         },
         "OAuth2Password": {
@@ -1218,9 +1257,9 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
             "flow": "password",
             "tokenUrl": "https://example.com/oauth/token",
             "scopes": {
-                "admin": " Grants read and write access to administrative information",
-                "read": " Grants read access",
-                "write": " Grants write access"
+                "admin": "Grants read and write access to administrative information",
+                "read": "Grants read access",
+                "write": "Grants write access"
             }
         }
     }
@@ -1294,9 +1333,14 @@ func TestParseStructComment(t *testing.T) {
         "web.APIError": {
             "type": "object",
             "properties": {
+                "code": {
+                    "description": "Error code, see the list of codes",
+                    "type": "integer"
+                },
                 "createdAt": {
                     "description": "Error time",
-                    "type": "string"
+                    "type": "string",
+                    "format": "date-time"
                 },
                 "error": {
                     "description": "Error an Api error",
@@ -1478,13 +1522,15 @@ func TestParseModelAsTypeAlias(t *testing.T) {
             "type": "object",
             "properties": {
                 "created_at": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "date-time"
                 },
                 "name": {
                     "type": "string"
                 },
                 "timestamp": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "date-time"
                 }
             }
         }
@@ -1546,216 +1592,131 @@ func TestParseNested(t *testing.T) {
 	assert.Equal(t, string(expected), string(b))
 }
 
-func TestParseDuplicated(t *testing.T) {
-	searchDir := "testdata/duplicated"
+func TestParseRecursive(t *testing.T) {
+	searchDir := "testdata/recursive"
 	mainAPIFile := "main.go"
 	p := New()
-	p.ParseDependency = true
 	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
-	assert.Errorf(t, err, "duplicated @id declarations successfully found")
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	assert.Len(t, p.swagger.Definitions, 3)
+	treeNode := p.swagger.Definitions["model.TreeNode"]
+	assert.Equal(t, "#/definitions/model.TreeNode", treeNode.Properties["children"].Items.Schema.Ref.String())
+	parent := treeNode.Properties["parent"]
+	assert.Equal(t, "#/definitions/model.TreeNode", parent.Ref.String())
+	assert.Empty(t, p.structStack)
 }
 
-func TestParseDuplicatedOtherMethods(t *testing.T) {
-	searchDir := "testdata/duplicated2"
+type recordingDebugger struct {
+	messages []string
+}
+
+func (d *recordingDebugger) Errorf(format string, v ...interface{}) {
+	d.messages = append(d.messages, "error: "+fmt.Sprintf(format, v...))
+}
+
+func (d *recordingDebugger) Warnf(format string, v ...interface{}) {
+	d.messages = append(d.messages, "warning: "+fmt.Sprintf(format, v...))
+}
+
+func (d *recordingDebugger) Infof(format string, v ...interface{}) {
+	d.messages = append(d.messages, fmt.Sprintf(format, v...))
+}
+
+func TestParser_SetDebugger(t *testing.T) {
+	searchDir := "testdata/recursive"
 	mainAPIFile := "main.go"
-	p := New()
-	p.ParseDependency = true
-	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
-	assert.Errorf(t, err, "duplicated @id declarations successfully found")
+
+	debugger := &recordingDebugger{}
+	p := New(SetDebugger(debugger))
+	assert.NoError(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+	assert.Contains(t, debugger.messages, "Generating model.TreeNode")
+	assert.Contains(t, debugger.messages, "Skipping 'model.TreeNode', recursion detected.")
+
+	p = New(SetDebugger(nil))
+	assert.NoError(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+	assert.Len(t, p.swagger.Definitions, 3)
 }
 
-func TestParseConflictSchemaName(t *testing.T) {
-	searchDir := "testdata/conflict_name"
+func TestParseAnnotationsFrom(t *testing.T) {
+	searchDir := "testdata/annotations_from"
 	mainAPIFile := "main.go"
 	p := New()
-	p.ParseDependency = true
 	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
-	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+
 	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
 	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
 	assert.Equal(t, string(expected), string(b))
 }
 
-func TestParser_ParseStructArrayObject(t *testing.T) {
+func TestParseAnnotationsFromFailed(t *testing.T) {
 	src := `
 package api
 
-type Response struct {
-	Code int
-	Table [][]string
-	Data []struct{
-		Field1 uint
-		Field2 string
-	}
-}
-
-// @Success 200 {object} Response
-// @Router /api/{id} [get]
-func Test(){
-}
+// @annotationsFrom Missing
+// @Router /test [get]
+func Fun() {}
 `
-	expected := `{
-   "api.Response": {
-      "type": "object",
-      "properties": {
-         "code": {
-            "type": "integer"
-         },
-         "data": {
-            "type": "array",
-            "items": {
-               "type": "object",
-               "properties": {
-                  "field1": {
-                     "type": "integer"
-                  },
-                  "field2": {
-                     "type": "string"
-                  }
-               }
-            }
-         },
-         "table": {
-            "type": "array",
-            "items": {
-               "type": "array",
-               "items": {
-                  "type": "string"
-               }
-            }
-         }
-      }
-   }
-}`
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
 	p := New()
 	p.packages.CollectAstFile("api", "api/api.go", f)
-	_, err = p.packages.ParseTypes()
-	assert.NoError(t, err)
-
-	err = p.ParseRouterAPIInfo("", f)
-	assert.NoError(t, err)
-
-	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
-	assert.NoError(t, err)
-	assert.Equal(t, expected, string(out))
-
-}
+	err = p.ParseRouterAPIInfo("api/api.go", f)
+	assert.EqualError(t, err, "ParseComment error in file api/api.go :@annotationsFrom cannot find function: Missing")
 
-func TestParser_ParseEmbededStruct(t *testing.T) {
-	src := `
+	src = `
 package api
 
-type Response struct {
-	rest.ResponseWrapper
-}
-
-// @Success 200 {object} Response
-// @Router /api/{id} [get]
-func Test(){
-}
-`
-	restsrc := `
-package rest
-
-type ResponseWrapper struct {
-	Status   string
-	Code     int
-	Messages []string
-	Result   interface{}
-}
+// @annotationsFrom Fun
+// @Router /test [get]
+func Fun() {}
 `
-	expected := `{
-   "api.Response": {
-      "type": "object",
-      "properties": {
-         "code": {
-            "type": "integer"
-         },
-         "messages": {
-            "type": "array",
-            "items": {
-               "type": "string"
-            }
-         },
-         "result": {
-            "type": "object"
-         },
-         "status": {
-            "type": "string"
-         }
-      }
-   }
-}`
-	parser := New()
-	parser.ParseDependency = true
-
-	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
-	assert.NoError(t, err)
-	parser.packages.CollectAstFile("api", "api/api.go", f)
-
-	f2, err := goparser.ParseFile(token.NewFileSet(), "", restsrc, goparser.ParseComments)
+	f, err = goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
-	parser.packages.CollectAstFile("rest", "rest/rest.go", f2)
 
-	_, err = parser.packages.ParseTypes()
-	assert.NoError(t, err)
+	p = New()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+	err = p.ParseRouterAPIInfo("api/api.go", f)
+	assert.EqualError(t, err, "ParseComment error in file api/api.go :@annotationsFrom Fun references itself")
+}
 
-	err = parser.ParseRouterAPIInfo("", f)
+func TestParseParamStruct(t *testing.T) {
+	searchDir := "testdata/param_struct"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
 
-	out, err := json.MarshalIndent(parser.swagger.Definitions, "", "   ")
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
 	assert.NoError(t, err)
-	assert.Equal(t, expected, string(out))
 
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
 }
 
-func TestParser_ParseStructPointerMembers(t *testing.T) {
+func TestParseParamStructRecursive(t *testing.T) {
 	src := `
 package api
 
-type Child struct {
-	Name string
-}
-
-type Parent struct {
-	Test1 *string  //test1
-	Test2 *Child   //test2
+type Node struct {
+	Name   string ` + "`json:\"name\"`" + `
+	Parent *Node  ` + "`json:\"parent\"`" + `
 }
 
-// @Success 200 {object} Parent
-// @Router /api/{id} [get]
-func Test(){
-}
+// @Param node query Node false "node"
+// @Router /nodes [get]
+func ListNodes() {}
 `
-
-	expected := `{
-   "api.Child": {
-      "type": "object",
-      "properties": {
-         "name": {
-            "type": "string"
-         }
-      }
-   },
-   "api.Parent": {
-      "type": "object",
-      "properties": {
-         "test1": {
-            "description": "test1",
-            "type": "string"
-         },
-         "test2": {
-            "description": "test2",
-            "$ref": "#/definitions/api.Child"
-         }
-      }
-   }
-}`
-
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
@@ -1763,787 +1724,3104 @@ func Test(){
 	p.packages.CollectAstFile("api", "api/api.go", f)
 	_, err = p.packages.ParseTypes()
 	assert.NoError(t, err)
+	err = p.ParseRouterAPIInfo("api/api.go", f)
+	assert.EqualError(t, err, "ParseComment error in file api/api.go :recursive type Node can't be expanded into query parameters")
+}
 
-	err = p.ParseRouterAPIInfo("", f)
+func TestParseExtensions(t *testing.T) {
+	searchDir := "testdata/extensions"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
 
-	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
 	assert.NoError(t, err)
-	assert.Equal(t, expected, string(out))
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
 }
 
-func TestParser_ParseStructMapMember(t *testing.T) {
+func TestParseExtensionsFailed(t *testing.T) {
 	src := `
 package api
 
-type MyMapType map[string]string
+// @Success 200 {string} string
+// @x-amazon-apigateway-integration {
+//   "uri": "${some_arn}",
+//   "type": aws_proxy
+// }
+// @Router /test [get]
+func Fun() {}
+`
+	p := New()
+	assert.NoError(t, p.parseFile("api", "api/api.go", src))
+	for astFile := range p.packages.files {
+		err := p.ParseRouterAPIInfo("api/api.go", astFile)
+		assert.EqualError(t, err, "ParseComment error in file api/api.go:5 :annotation @x-amazon-apigateway-integration need a valid json value")
+	}
 
-type Child struct {
-	Name string
+	src = `
+package api
+
+// @Success 200 {string} string
+// @Router /test [get]
+// @x-amazon-apigateway-integration {
+//   "uri": "${some_arn}"
+func Fun() {}
+`
+	p = New()
+	assert.NoError(t, p.parseFile("api", "api/api.go", src))
+	for astFile := range p.packages.files {
+		err := p.ParseRouterAPIInfo("api/api.go", astFile)
+		assert.EqualError(t, err, "ParseComment error in file api/api.go:6 :annotation @x-amazon-apigateway-integration need a valid json value")
+	}
+
+	src = `
+package api
+
+// Pet is a pet
+// @x-tablename pets
+type Pet struct {
+	ID int
+}
+`
+	p = New()
+	assert.NoError(t, p.parseFile("api", "api/api.go", src))
+	_, err := p.packages.ParseTypes()
+	assert.NoError(t, err)
+	_, err = p.getTypeSchema("api.Pet", nil, true)
+	assert.EqualError(t, err, "annotation @x-tablename of api.Pet in file api/api.go:5 need a valid json value")
 }
 
-type Parent struct {
-	Test1 map[string]interface{}  //test1
-	Test2 map[string]string		  //test2
-	Test3 map[string]*string	  //test3
-	Test4 map[string]Child		  //test4
-	Test5 map[string]*Child		  //test5
-	Test6 MyMapType				  //test6
-	Test7 []Child				  //test7
-	Test8 []*Child				  //test8
-	Test9 []map[string]string	  //test9
+func TestParseNamedPrimitiveTypes(t *testing.T) {
+	src := `
+package model
+
+type UserID int64
+
+type Token string
+
+type Money struct {
+	units int
+	nanos int
 }
 
-// @Success 200 {object} Parent
-// @Router /api/{id} [get]
-func Test(){
+type User struct {
+	ID      UserID   ` + "`json:\"id\"`" + `
+	Token   Token    ` + "`json:\"token\"`" + `
+	Friends []UserID ` + "`json:\"friends\"`" + `
+	Balance Money    ` + "`json:\"balance\"`" + `
 }
 `
-	expected := `{
-   "api.Child": {
-      "type": "object",
-      "properties": {
-         "name": {
-            "type": "string"
-         }
-      }
-   },
-   "api.MyMapType": {
-      "type": "object",
-      "additionalProperties": {
-         "type": "string"
-      }
-   },
-   "api.Parent": {
-      "type": "object",
-      "properties": {
-         "test1": {
-            "description": "test1",
-            "type": "object",
-            "additionalProperties": true
-         },
-         "test2": {
-            "description": "test2",
-            "type": "object",
-            "additionalProperties": {
-               "type": "string"
-            }
-         },
-         "test3": {
-            "description": "test3",
-            "type": "object",
-            "additionalProperties": {
-               "type": "string"
-            }
-         },
-         "test4": {
-            "description": "test4",
-            "type": "object",
-            "additionalProperties": {
-               "$ref": "#/definitions/api.Child"
-            }
-         },
-         "test5": {
-            "description": "test5",
-            "type": "object",
-            "additionalProperties": {
-               "$ref": "#/definitions/api.Child"
-            }
-         },
-         "test6": {
-            "description": "test6",
-            "$ref": "#/definitions/api.MyMapType"
-         },
-         "test7": {
-            "description": "test7",
-            "type": "array",
-            "items": {
-               "$ref": "#/definitions/api.Child"
-            }
-         },
-         "test8": {
-            "description": "test8",
-            "type": "array",
-            "items": {
-               "$ref": "#/definitions/api.Child"
-            }
-         },
-         "test9": {
-            "description": "test9",
-            "type": "array",
-            "items": {
-               "type": "object",
-               "additionalProperties": {
-                  "type": "string"
-               }
-            }
-         }
-      }
-   }
-}`
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
 	p := New()
-	p.packages.CollectAstFile("api", "api/api.go", f)
-
+	p.AliasTypes = map[string]string{"model.Money": "primitive,number:double"}
+	p.packages.CollectAstFile("model", "model.go", f)
 	_, err = p.packages.ParseTypes()
 	assert.NoError(t, err)
 
-	err = p.ParseRouterAPIInfo("", f)
+	_, err = p.getTypeSchema("User", f, true)
 	assert.NoError(t, err)
+	b, _ := json.MarshalIndent(p.swagger.Definitions, "", "    ")
+	expected := `{
+    "model.User": {
+        "type": "object",
+        "properties": {
+            "balance": {
+                "type": "number",
+                "format": "double"
+            },
+            "friends": {
+                "type": "array",
+                "items": {
+                    "type": "integer",
+                    "format": "int64"
+                }
+            },
+            "id": {
+                "type": "integer",
+                "format": "int64"
+            },
+            "token": {
+                "type": "string"
+            }
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
 
-	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
-	assert.NoError(t, err)
-	assert.Equal(t, expected, string(out))
+	operation := NewOperation(p)
+	assert.NoError(t, operation.ParseComment(`@Param id path model.UserID true "user id"`, f))
+	assert.NoError(t, operation.ParseComment(`@Param tokens query []Token false "tokens"`, f))
+	assert.NoError(t, operation.ParseComment(`@Success 200 {object} model.UserID`, f))
+	b, _ = json.MarshalIndent(operation, "", "    ")
+	expected = `{
+    "parameters": [
+        {
+            "type": "integer",
+            "format": "int64",
+            "description": "user id",
+            "name": "id",
+            "in": "path",
+            "required": true
+        },
+        {
+            "type": "array",
+            "items": {
+                "type": "string"
+            },
+            "description": "tokens",
+            "name": "tokens",
+            "in": "query"
+        }
+    ],
+    "responses": {
+        "200": {
+            "description": "OK",
+            "schema": {
+                "type": "integer",
+                "format": "int64"
+            }
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+
+	p.AliasTypes["model.Token"] = "primitive,uuid"
+	_, err = p.getTypeSchema("Token", f, true)
+	assert.EqualError(t, err, "invalid alias type primitive,uuid of model.Token: uuid is not basic types")
 }
 
-func TestParser_ParseRouterApiInfoErr(t *testing.T) {
+func TestParseTimeTypes(t *testing.T) {
 	src := `
-package test
+package model
 
-// @Accept unknown
-func Test(){
+import "time"
+
+type Event struct {
+	At       time.Time     ` + "`json:\"at\"`" + `
+	Day      time.Time     ` + "`json:\"day\" swaggertype:\"string\" format:\"date\"`" + `
+	Duration time.Duration ` + "`json:\"duration\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
-	p := New()
-	err = p.ParseRouterAPIInfo("", f)
-	assert.EqualError(t, err, "ParseComment error in file  :unknown accept type can't be accepted")
+	for _, durationType := range []string{"", STRING} {
+		p := New()
+		p.DurationType = durationType
+		p.packages.CollectAstFile("model", "model.go", f)
+		_, err = p.packages.ParseTypes()
+		assert.NoError(t, err)
+
+		schema, err := p.getTypeSchema("Event", f, false)
+		assert.NoError(t, err)
+
+		at := schema.Properties["at"]
+		assert.Equal(t, spec.StringOrArray{STRING}, at.Type)
+		assert.Equal(t, "date-time", at.Format)
+		day := schema.Properties["day"]
+		assert.Equal(t, spec.StringOrArray{STRING}, day.Type)
+		assert.Equal(t, "date", day.Format)
+
+		duration := schema.Properties["duration"]
+		if durationType == STRING {
+			assert.Equal(t, spec.StringOrArray{STRING}, duration.Type)
+			assert.Equal(t, "", duration.Format)
+		} else {
+			assert.Equal(t, spec.StringOrArray{INTEGER}, duration.Type)
+			assert.Equal(t, "int64", duration.Format)
+		}
+	}
 }
 
-func TestParser_ParseRouterApiGet(t *testing.T) {
+func TestParseNullable(t *testing.T) {
 	src := `
-package test
+package model
 
-// @Router /api/{id} [get]
-func Test(){
+type Pet struct {
+	ID    int     ` + "`json:\"id\"`" + `
+	Name  *string ` + "`json:\"name\"`" + `
+	Owner *Person ` + "`json:\"owner\"`" + `
+	Tag   string  ` + "`json:\"tag\" extensions:\"x-nullable\"`" + `
+}
+
+type Person struct {
+	Name string ` + "`json:\"name\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
+	for _, nullable := range []bool{false, true} {
+		p := New()
+		p.Nullable = nullable
+		p.packages.CollectAstFile("model", "model.go", f)
+		_, err = p.packages.ParseTypes()
+		assert.NoError(t, err)
+
+		schema, err := p.getTypeSchema("Pet", f, false)
+		assert.NoError(t, err)
+
+		assert.Empty(t, schema.Properties["id"].Extensions)
+		assert.Equal(t, spec.Extensions{"x-nullable": true}, schema.Properties["tag"].Extensions)
+		name, owner := schema.Properties["name"], schema.Properties["owner"]
+		assert.Equal(t, "#/definitions/model.Person", owner.Ref.String())
+		if nullable {
+			assert.Equal(t, spec.Extensions{"x-nullable": true}, name.Extensions)
+			assert.Equal(t, spec.Extensions{"x-nullable": true}, owner.Extensions)
+		} else {
+			assert.Empty(t, name.Extensions)
+			assert.Empty(t, owner.Extensions)
+		}
+	}
+}
+
+func TestParseSelfDescribedTypes(t *testing.T) {
+	searchDir := "testdata/self_described"
+	mainAPIFile := "main.go"
 	p := New()
-	err = p.ParseRouterAPIInfo("", f)
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
 
-	ps := p.swagger.Paths.Paths
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
 
-	val, ok := ps["/api/{id}"]
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+}
 
-	assert.True(t, ok)
-	assert.NotNil(t, val.Get)
+func TestParseSelfDescribedTypesFailed(t *testing.T) {
+	for src, expected := range map[string]string{
+		"func (ID) SwaggerSchema() string { return idSchema }":   "model.ID.SwaggerSchema must return a string literal",
+		"func (ID) SwaggerSchema() string { return \"object\" }": "model.ID.SwaggerSchema returns object which is not a primitive type",
+	} {
+		f, err := goparser.ParseFile(token.NewFileSet(), "", "package model\n\ntype ID struct{}\n\n"+src, goparser.ParseComments)
+		assert.NoError(t, err)
+
+		p := New()
+		p.packages.CollectAstFile("model", "model.go", f)
+		_, err = p.packages.ParseTypes()
+		assert.NoError(t, err)
+
+		_, err = p.getTypeSchema("ID", f, false)
+		assert.EqualError(t, err, expected)
+	}
 }
 
-func TestParser_ParseRouterApiPOST(t *testing.T) {
+func TestParseRequiredByDefault(t *testing.T) {
 	src := `
-package test
-
-// @Router /api/{id} [post]
-func Test(){
+package model
+
+type Pet struct {
+	ID       int
+	Name     string ` + "`json:\"name\"`" + `
+	Tag      string ` + "`json:\"tag,omitempty\"`" + `
+	Owner    string ` + "`json:\"owner\" binding:\"-\"`" + `
+	Nickname string ` + "`json:\"nickname\" validate:\"omitempty,max=16\"`" + `
+	Age      int    ` + "`json:\"age,omitempty\" binding:\"required\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
-	p := New()
-	err = p.ParseRouterAPIInfo("", f)
-	assert.NoError(t, err)
-
-	ps := p.swagger.Paths.Paths
-
-	val, ok := ps["/api/{id}"]
-
-	assert.True(t, ok)
-	assert.NotNil(t, val.Post)
+	for requiredByDefault, expected := range map[bool][]string{
+		false: {"age"},
+		true:  {"age", "id", "name"},
+	} {
+		p := New()
+		p.RequiredByDefault = requiredByDefault
+		p.packages.CollectAstFile("model", "model.go", f)
+		_, err = p.packages.ParseTypes()
+		assert.NoError(t, err)
+
+		schema, err := p.getTypeSchema("Pet", f, false)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, expected, schema.Required)
+	}
 }
 
-func TestParser_ParseRouterApiDELETE(t *testing.T) {
+func TestParseConditionalRequired(t *testing.T) {
 	src := `
-package test
-
-// @Router /api/{id} [delete]
-func Test(){
+package model
+
+type Account struct {
+	Type    string ` + "`json:\"type\" validate:\"required\"`" + `
+	Company string ` + "`json:\"company\" validate:\"required_if=Type premium,max=64\"`" + `
+	Email   string ` + "`json:\"email\" validate:\"required_without=Phone\"`" + `
+	Phone   string ` + "`json:\"phone\" binding:\"required_with=Country\"`" + `
+	Country string ` + "`json:\"country\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
-	p := New()
 
-	err = p.ParseRouterAPIInfo("", f)
-	assert.NoError(t, err)
+	for _, requiredByDefault := range []bool{false, true} {
+		p := New()
+		p.RequiredByDefault = requiredByDefault
+		p.packages.CollectAstFile("model", "model.go", f)
+		_, err = p.packages.ParseTypes()
+		assert.NoError(t, err)
+
+		schema, err := p.getTypeSchema("Account", f, false)
+		assert.NoError(t, err)
+		if requiredByDefault {
+			assert.ElementsMatch(t, []string{"type", "country"}, schema.Required)
+		} else {
+			assert.Equal(t, []string{"type"}, schema.Required)
+		}
+		assert.Equal(t, spec.Extensions{"x-required-if": "Type premium"}, schema.Properties["company"].Extensions)
+		assert.Nil(t, schema.Properties["company"].MaxLength)
+		assert.Equal(t, spec.Extensions{"x-required-without": "Phone"}, schema.Properties["email"].Extensions)
+		assert.Equal(t, spec.Extensions{"x-required-with": "Country"}, schema.Properties["phone"].Extensions)
+		assert.Empty(t, schema.Properties["country"].Extensions)
+	}
+}
 
-	ps := p.swagger.Paths.Paths
+func TestParseTypeOverrides(t *testing.T) {
+	src := `
+package model
 
-	val, ok := ps["/api/{id}"]
+import (
+	"github.com/google/uuid"
+	dec "github.com/shopspring/decimal"
+)
 
-	assert.True(t, ok)
-	assert.NotNil(t, val.Delete)
+type Money struct {
+	Amount int
 }
 
-func TestParser_ParseRouterApiPUT(t *testing.T) {
-	src := `
-package test
-
-// @Router /api/{id} [put]
-func Test(){
+type Order struct {
+	ID     uuid.UUID            ` + "`json:\"id\"`" + `
+	Total  dec.Decimal          ` + "`json:\"total\"`" + `
+	Prices []Money              ` + "`json:\"prices\"`" + `
+	Refs   map[string]uuid.UUID ` + "`json:\"refs\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
+	uuidSchema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{STRING}, Format: "uuid"}}
+	decimalSchema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{NUMBER}, Format: "double"}}
+	moneySchema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{STRING}, Pattern: `^\d+\.\d{2}$`}}
+
 	p := New()
-	err = p.ParseRouterAPIInfo("", f)
+	p.TypeOverrides = map[string]*spec.Schema{
+		"github.com/google/uuid.UUID":           uuidSchema,
+		"github.com/shopspring/decimal.Decimal": decimalSchema,
+		"model.Money":                           moneySchema,
+	}
+	p.packages.CollectAstFile("model", "model.go", f)
+	_, err = p.packages.ParseTypes()
 	assert.NoError(t, err)
 
-	ps := p.swagger.Paths.Paths
-
-	val, ok := ps["/api/{id}"]
-
-	assert.True(t, ok)
-	assert.NotNil(t, val.Put)
+	schema, err := p.getTypeSchema("Order", f, false)
+	assert.NoError(t, err)
+	assert.Equal(t, *uuidSchema, schema.Properties["id"])
+	assert.Equal(t, *decimalSchema, schema.Properties["total"])
+	assert.Equal(t, moneySchema, schema.Properties["prices"].Items.Schema)
+	assert.Equal(t, uuidSchema, schema.Properties["refs"].AdditionalProperties.Schema)
+	assert.Empty(t, p.swagger.Definitions)
 }
 
-func TestParser_ParseRouterApiPATCH(t *testing.T) {
+func TestParseNameResolver(t *testing.T) {
 	src := `
-package test
+package model
 
-// @Router /api/{id} [patch]
-func Test(){
+type Pet struct {
+	ID       int
+	FullName string ` + "`json:\"full_name\"`" + `
+	Secret   string ` + "`api:\"-\"`" + `
+	Owner    string ` + "`api:\"petOwner\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
 	p := New()
-	err = p.ParseRouterAPIInfo("", f)
+	p.NameResolver = func(fieldName string, tag reflect.StructTag) string {
+		switch name := tag.Get("api"); name {
+		case "-":
+			return ""
+		case "":
+			return strings.ToLower(fieldName)
+		default:
+			return name
+		}
+	}
+	p.packages.CollectAstFile("model", "model.go", f)
+	_, err = p.packages.ParseTypes()
 	assert.NoError(t, err)
 
-	ps := p.swagger.Paths.Paths
+	schema, err := p.getTypeSchema("Pet", f, false)
+	assert.NoError(t, err)
+	var names []string
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"id", "fullname", "petOwner"}, names)
+}
 
-	val, ok := ps["/api/{id}"]
+func TestParseMapFields(t *testing.T) {
+	src := `
+package model
 
-	assert.True(t, ok)
-	assert.NotNil(t, val.Patch)
+type Pet struct {
+	Name string ` + "`json:\"name\"`" + `
 }
 
-func TestParser_ParseRouterApiHead(t *testing.T) {
-	src := `
-package test
+type Kind string
 
-// @Router /api/{id} [head]
-func Test(){
+type Store struct {
+	Pets   map[string]Pet         ` + "`json:\"pets\"`" + `
+	Labels map[string]string      ` + "`json:\"labels\"`" + `
+	Kinds  map[Kind]int           ` + "`json:\"kinds\"`" + `
+	Extra  map[string]interface{} ` + "`json:\"extra\"`" + `
+	ByID   map[int]Pet            ` + "`json:\"by_id\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
+
 	p := New()
+	p.packages.CollectAstFile("model", "model.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
 
-	err = p.ParseRouterAPIInfo("", f)
-
+	schema, err := p.getTypeSchema("Store", f, false)
 	assert.NoError(t, err)
-	ps := p.swagger.Paths.Paths
-
-	val, ok := ps["/api/{id}"]
 
-	assert.True(t, ok)
-	assert.NotNil(t, val.Head)
+	expected := `{
+    "by_id": {
+        "type": "object"
+    },
+    "extra": {
+        "type": "object",
+        "additionalProperties": true
+    },
+    "kinds": {
+        "type": "object",
+        "additionalProperties": {
+            "type": "integer"
+        }
+    },
+    "labels": {
+        "type": "object",
+        "additionalProperties": {
+            "type": "string"
+        }
+    },
+    "pets": {
+        "type": "object",
+        "additionalProperties": {
+            "$ref": "#/definitions/model.Pet"
+        }
+    }
+}`
+	b, _ := json.MarshalIndent(schema.Properties, "", "    ")
+	assert.Equal(t, expected, string(b))
 }
 
-func TestParser_ParseRouterApiOptions(t *testing.T) {
+func TestParseInt64AsString(t *testing.T) {
 	src := `
-package test
+package model
 
-// @Router /api/{id} [options]
-func Test(){
+type ID int64
+
+type Kind int64
+
+const (
+	KindA Kind = iota
+	KindB
+)
+
+type Order struct {
+	ID    ID       ` + "`json:\"id\"`" + `
+	Total int64    ` + "`json:\"total\" example:\"9007199254740993\"`" + `
+	Count int      ` + "`json:\"count\"`" + `
+	Parts []uint64 ` + "`json:\"parts\"`" + `
+	Kind  Kind     ` + "`json:\"kind\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
-	p := New()
-	err = p.ParseRouterAPIInfo("", f)
-	assert.NoError(t, err)
-
-	ps := p.swagger.Paths.Paths
-
-	val, ok := ps["/api/{id}"]
+	for _, int64AsString := range []bool{false, true} {
+		p := New()
+		p.Int64AsString = int64AsString
+		p.packages.CollectAstFile("model", "model.go", f)
+		schemas, err := p.packages.ParseTypes()
+		assert.NoError(t, err)
+		p.parsedSchemas = schemas
+		for typeSpecDef, schema := range p.parsedSchemas {
+			p.setEnums(typeSpecDef, schema.Schema)
+		}
 
-	assert.True(t, ok)
-	assert.NotNil(t, val.Options)
+		schema, err := p.getTypeSchema("Order", f, false)
+		assert.NoError(t, err)
+
+		id, total, count := schema.Properties["id"], schema.Properties["total"], schema.Properties["count"]
+		parts, kind := schema.Properties["parts"], schema.Properties["kind"]
+		assert.Equal(t, spec.StringOrArray{INTEGER}, count.Type)
+		assert.Equal(t, spec.StringOrArray{INTEGER}, kind.Type)
+		if int64AsString {
+			assert.Equal(t, spec.StringOrArray{STRING}, id.Type)
+			assert.Equal(t, "int64", id.Extensions["x-format"])
+			assert.Equal(t, spec.StringOrArray{STRING}, total.Type)
+			assert.Equal(t, "int64", total.Extensions["x-format"])
+			assert.Equal(t, "9007199254740993", total.Example)
+			assert.Equal(t, spec.StringOrArray{STRING}, parts.Items.Schema.Type)
+			assert.Equal(t, "uint64", parts.Items.Schema.Extensions["x-format"])
+		} else {
+			assert.Equal(t, spec.StringOrArray{INTEGER}, id.Type)
+			assert.Equal(t, "int64", id.Format)
+			assert.Equal(t, spec.StringOrArray{INTEGER}, total.Type)
+			assert.Equal(t, 9007199254740993, total.Example)
+			assert.Equal(t, spec.StringOrArray{INTEGER}, parts.Items.Schema.Type)
+		}
+	}
 }
 
-func TestParser_ParseRouterApiMultiple(t *testing.T) {
-	src := `
-package test
+func TestParseReceiverMethodsAcrossFiles(t *testing.T) {
+	searchDir := "testdata/receiver_methods"
+	mainAPIFile := "main.go"
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
 
-// @Router /api/{id} [get]
-func Test1(){
-}
+	for _, parseDependency := range []bool{false, true} {
+		p := New()
+		p.ParseDependency = parseDependency
+		err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+		assert.NoError(t, err)
 
-// @Router /api/{id} [patch]
-func Test2(){
+		b, _ := json.MarshalIndent(p.swagger, "", "    ")
+		assert.Equal(t, string(expected), string(b))
+	}
 }
 
-// @Router /api/{id} [delete]
-func Test3(){
+func TestParseDuplicated(t *testing.T) {
+	searchDir := "testdata/duplicated"
+	mainAPIFile := "main.go"
+	p := New()
+	p.ParseDependency = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.Errorf(t, err, "duplicated @id declarations successfully found")
 }
-`
-	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
-	assert.NoError(t, err)
 
+func TestParseDuplicatedOtherMethods(t *testing.T) {
+	searchDir := "testdata/duplicated2"
+	mainAPIFile := "main.go"
 	p := New()
-	err = p.ParseRouterAPIInfo("", f)
-	assert.NoError(t, err)
-
-	ps := p.swagger.Paths.Paths
-
-	val, ok := ps["/api/{id}"]
-
-	assert.True(t, ok)
-	assert.NotNil(t, val.Get)
-	assert.NotNil(t, val.Patch)
-	assert.NotNil(t, val.Delete)
+	p.ParseDependency = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.Errorf(t, err, "duplicated @id declarations successfully found")
 }
 
-// func TestParseDeterministic(t *testing.T) {
-// 	mainAPIFile := "main.go"
-// 	for _, searchDir := range []string{
-// 		"testdata/simple",
-// 		"testdata/model_not_under_root/cmd",
-// 	} {
-// 		t.Run(searchDir, func(t *testing.T) {
-// 			var expected string
-
-// 			// run the same code 100 times and check that the output is the same every time
-// 			for i := 0; i < 100; i++ {
-// 				p := New()
-// 				p.PropNamingStrategy = PascalCase
-// 				err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
-// 				b, _ := json.MarshalIndent(p.swagger, "", "    ")
-// 				assert.NotEqual(t, "", string(b))
-
-// 				if expected == "" {
-// 					expected = string(b)
-// 				}
-
-// 				assert.Equal(t, expected, string(b))
-// 			}
-// 		})
-// 	}
-// }
-
-func TestApiParseTag(t *testing.T) {
-	searchDir := "testdata/tags"
+func TestParseGeneralInfoAcrossDir(t *testing.T) {
+	searchDir := "testdata/general_info_across_dir"
 	mainAPIFile := "main.go"
-	p := New(SetMarkdownFileDirectory(searchDir))
-	p.PropNamingStrategy = PascalCase
+	p := New()
+	p.ParseGeneralInfoAcrossDir = true
 	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
 
-	if len(p.swagger.Tags) != 3 {
-		t.Error("Number of tags did not match")
-	}
-
-	dogs := p.swagger.Tags[0]
-	if dogs.TagProps.Name != "dogs" || dogs.TagProps.Description != "Dogs are cool" {
-		t.Error("Failed to parse dogs name or description")
-	}
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
 
-	cats := p.swagger.Tags[1]
-	if cats.TagProps.Name != "cats" || cats.TagProps.Description != "Cats are the devil" {
-		t.Error("Failed to parse cats name or description")
-	}
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
 
-	if cats.TagProps.ExternalDocs.URL != "https://google.de" || cats.TagProps.ExternalDocs.Description != "google is super useful to find out that cats are evil!" {
-		t.Error("URL: ", cats.TagProps.ExternalDocs.URL)
-		t.Error("Description: ", cats.TagProps.ExternalDocs.Description)
-		t.Error("Failed to parse cats external documentation")
-	}
+	p = New()
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Empty(t, p.swagger.Host)
+	assert.Empty(t, p.swagger.Tags)
 }
 
-func TestApiParseTag_NonExistendTag(t *testing.T) {
-	searchDir := "testdata/tags_nonexistend_tag"
+func TestParseGeneralInfoAcrossDirConflict(t *testing.T) {
+	searchDir := "testdata/general_info_conflict"
 	mainAPIFile := "main.go"
-	p := New(SetMarkdownFileDirectory(searchDir))
-	p.PropNamingStrategy = PascalCase
+	p := New()
+	p.ParseGeneralInfoAcrossDir = true
 	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
-	assert.Error(t, err)
+	assert.EqualError(t, err, "@version is set in both "+filepath.Join(searchDir, "main.go")+":4 and "+filepath.Join(searchDir, "router.go")+":4")
 }
 
-func TestParseTagMarkdownDescription(t *testing.T) {
-	searchDir := "testdata/tags"
+func TestParseDefaultResponses(t *testing.T) {
+	searchDir := "testdata/default_responses"
 	mainAPIFile := "main.go"
-	p := New(SetMarkdownFileDirectory(searchDir))
-	p.PropNamingStrategy = PascalCase
+	p := New()
 	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
-	if err != nil {
-		t.Error("Failed to parse api description: " + err.Error())
-	}
+	assert.NoError(t, err)
 
-	if len(p.swagger.Tags) != 3 {
-		t.Error("Number of tags did not match")
-	}
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
 
-	apes := p.swagger.Tags[2]
-	if apes.TagProps.Description == "" {
-		t.Error("Failed to parse tag description markdown file")
-	}
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
 }
 
-func TestParseApiMarkdownDescription(t *testing.T) {
-	searchDir := "testdata/tags"
+func TestParseDuplicatedRoute(t *testing.T) {
+	searchDir := "testdata/duplicated_route"
 	mainAPIFile := "main.go"
-	p := New(SetMarkdownFileDirectory(searchDir))
-	p.PropNamingStrategy = PascalCase
+	p := New()
 	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
-	if err != nil {
-		t.Error("Failed to parse api description: " + err.Error())
-	}
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicated route 'GET /users' found in ")
+	assert.Contains(t, err.Error(), "GetUsers ("+filepath.Join(searchDir, "api", "api.go")+":6)")
+	assert.Contains(t, err.Error(), "ListUsers ("+filepath.Join(searchDir, "admin", "admin.go")+":6)")
 
-	if p.swagger.Info.Description == "" {
-		t.Error("Failed to parse api description: " + err.Error())
+	src := `
+package api
+
+// @Router /users [get]
+func GetUsers() {}
+
+// @Router /users [post]
+func CreateUser() {}
+`
+	p = New()
+	assert.NoError(t, p.parseFile("api", "api/api.go", src))
+	for astFile := range p.packages.files {
+		assert.NoError(t, p.ParseRouterAPIInfo("api/api.go", astFile))
 	}
+	pathItem := p.swagger.Paths.Paths["/users"]
+	assert.NotNil(t, pathItem.Get)
+	assert.NotNil(t, pathItem.Post)
 }
 
-func TestIgnoreInvalidPkg(t *testing.T) {
-	searchDir := "testdata/deps_having_invalid_pkg"
+func TestParseConflictSchemaName(t *testing.T) {
+	searchDir := "testdata/conflict_name"
 	mainAPIFile := "main.go"
 	p := New()
-	if err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth); err != nil {
-		t.Error("Failed to ignore valid pkg: " + err.Error())
-	}
+	p.ParseDependency = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(b))
 }
 
-func TestFixes432(t *testing.T) {
-	searchDir := "testdata/fixes-432"
-	mainAPIFile := "cmd/main.go"
+func TestParseDefinitionNaming(t *testing.T) {
+	searchDir := "testdata/conflict_name"
+	mainAPIFile := "main.go"
 
 	p := New()
-	if err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth); err != nil {
-		t.Error("Failed to ignore valid pkg: " + err.Error())
-	}
-}
+	p.ParseDependency = true
+	p.DefinitionNaming = ShortDefinitionNaming
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.EqualError(t, err, "ParseComment error in file testdata/conflict_name/api/api2.go:13 :definition model.MyStruct is given to types of both "+
+		"github.com/swaggo/swag/testdata/conflict_name/model and github.com/swaggo/swag/testdata/conflict_name/model2, use the full definition naming to tell them apart")
 
-func TestParseOutsideDependencies(t *testing.T) {
-	searchDir := "testdata/pare_outside_dependencies"
-	mainAPIFile := "cmd/main.go"
+	definitionNames := func(p *Parser) []string {
+		var names []string
+		for name := range p.swagger.Definitions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	responseRefs := func(p *Parser) []string {
+		var refs []string
+		for _, path := range []string{"/health", "/health2"} {
+			refs = append(refs, p.swagger.Paths.Paths[path].Get.Responses.StatusCodeResponses[200].Schema.Ref.String())
+		}
+		return refs
+	}
 
-	p := New()
+	p = New()
 	p.ParseDependency = true
-	if err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth); err != nil {
-		t.Error("Failed to parse api: " + err.Error())
+	p.DefinitionNaming = FullDefinitionNaming
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"github.com_swaggo_swag_testdata_conflict_name_model.ErrorsResponse",
+		"github.com_swaggo_swag_testdata_conflict_name_model.MyPayload",
+		"github.com_swaggo_swag_testdata_conflict_name_model.MyStruct",
+		"github.com_swaggo_swag_testdata_conflict_name_model2.ErrorsResponse",
+		"github.com_swaggo_swag_testdata_conflict_name_model2.MyPayload2",
+		"github.com_swaggo_swag_testdata_conflict_name_model2.MyStruct",
+	}, definitionNames(p))
+	assert.Equal(t, []string{
+		"#/definitions/github.com_swaggo_swag_testdata_conflict_name_model.ErrorsResponse",
+		"#/definitions/github.com_swaggo_swag_testdata_conflict_name_model2.ErrorsResponse",
+	}, responseRefs(p))
+
+	p = New()
+	p.ParseDependency = true
+	p.ModelNamer = func(pkgPath, typeName string) string {
+		return path.Base(pkgPath) + typeName
 	}
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"model2ErrorsResponse", "model2MyPayload2", "model2MyStruct", "modelErrorsResponse", "modelMyPayload", "modelMyStruct",
+	}, definitionNames(p))
+	assert.Equal(t, []string{"#/definitions/modelErrorsResponse", "#/definitions/model2ErrorsResponse"}, responseRefs(p))
+	b, _ := json.Marshal(p.swagger.Definitions)
+	assert.Contains(t, string(b), `"#/definitions/model2MyStruct"`)
 }
 
-func TestParseStructParamCommentByQueryType(t *testing.T) {
+func TestParser_ParseStructArrayObject(t *testing.T) {
 	src := `
-package main
+package api
 
-type Student struct {
-	Name string
-	Age int
-	Teachers []string
-	SkipField map[string]string
+type Response struct {
+	Code int
+	Table [][]string
+	Data []struct{
+		Field1 uint
+		Field2 string
+	}
 }
 
-// @Param request query Student true "query params"
-// @Success 200
-// @Router /test [get]
-func Fun()  {
-
+// @Success 200 {object} Response
+// @Router /api/{id} [get]
+func Test(){
 }
 `
 	expected := `{
-    "info": {
-        "contact": {}
-    },
-    "paths": {
-        "/test": {
-            "get": {
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "name": "age",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "name": "teachers",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": ""
-                    }
-                }
+   "api.Response": {
+      "type": "object",
+      "properties": {
+         "code": {
+            "type": "integer"
+         },
+         "data": {
+            "type": "array",
+            "items": {
+               "type": "object",
+               "properties": {
+                  "field1": {
+                     "type": "integer"
+                  },
+                  "field2": {
+                     "type": "string"
+                  }
+               }
             }
-        }
-    }
+         },
+         "table": {
+            "type": "array",
+            "items": {
+               "type": "array",
+               "items": {
+                  "type": "string"
+               }
+            }
+         }
+      }
+   }
+}`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+
+}
+
+func TestParser_ParseEmbededStruct(t *testing.T) {
+	src := `
+package api
+
+type Response struct {
+	rest.ResponseWrapper
+}
+
+// @Success 200 {object} Response
+// @Router /api/{id} [get]
+func Test(){
+}
+`
+	restsrc := `
+package rest
+
+type ResponseWrapper struct {
+	Status   string
+	Code     int
+	Messages []string
+	Result   interface{}
+}
+`
+	expected := `{
+   "api.Response": {
+      "type": "object",
+      "properties": {
+         "code": {
+            "type": "integer"
+         },
+         "messages": {
+            "type": "array",
+            "items": {
+               "type": "string"
+            }
+         },
+         "result": {},
+         "status": {
+            "type": "string"
+         }
+      }
+   }
+}`
+	parser := New()
+	parser.ParseDependency = true
+
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+	parser.packages.CollectAstFile("api", "api/api.go", f)
+
+	f2, err := goparser.ParseFile(token.NewFileSet(), "", restsrc, goparser.ParseComments)
+	assert.NoError(t, err)
+	parser.packages.CollectAstFile("rest", "rest/rest.go", f2)
+
+	_, err = parser.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = parser.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	out, err := json.MarshalIndent(parser.swagger.Definitions, "", "   ")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+
+}
+
+func TestParser_ParseStructPointerMembers(t *testing.T) {
+	src := `
+package api
+
+type Child struct {
+	Name string
+}
+
+type Parent struct {
+	Test1 *string  //test1
+	Test2 *Child   //test2
+}
+
+// @Success 200 {object} Parent
+// @Router /api/{id} [get]
+func Test(){
+}
+`
+
+	expected := `{
+   "api.Child": {
+      "type": "object",
+      "properties": {
+         "name": {
+            "type": "string"
+         }
+      }
+   },
+   "api.Parent": {
+      "type": "object",
+      "properties": {
+         "test1": {
+            "description": "test1",
+            "type": "string"
+         },
+         "test2": {
+            "description": "test2",
+            "$ref": "#/definitions/api.Child"
+         }
+      }
+   }
 }`
 
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
 	p := New()
-	p.packages.CollectAstFile("api", "api/api.go", f)
-
-	_, err = p.packages.ParseTypes()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+}
+
+func TestParser_ParseStructMapMember(t *testing.T) {
+	src := `
+package api
+
+type MyMapType map[string]string
+
+type Child struct {
+	Name string
+}
+
+type Parent struct {
+	Test1 map[string]interface{}  //test1
+	Test2 map[string]string		  //test2
+	Test3 map[string]*string	  //test3
+	Test4 map[string]Child		  //test4
+	Test5 map[string]*Child		  //test5
+	Test6 MyMapType				  //test6
+	Test7 []Child				  //test7
+	Test8 []*Child				  //test8
+	Test9 []map[string]string	  //test9
+}
+
+// @Success 200 {object} Parent
+// @Router /api/{id} [get]
+func Test(){
+}
+`
+	expected := `{
+   "api.Child": {
+      "type": "object",
+      "properties": {
+         "name": {
+            "type": "string"
+         }
+      }
+   },
+   "api.MyMapType": {
+      "type": "object",
+      "additionalProperties": {
+         "type": "string"
+      }
+   },
+   "api.Parent": {
+      "type": "object",
+      "properties": {
+         "test1": {
+            "description": "test1",
+            "type": "object",
+            "additionalProperties": true
+         },
+         "test2": {
+            "description": "test2",
+            "type": "object",
+            "additionalProperties": {
+               "type": "string"
+            }
+         },
+         "test3": {
+            "description": "test3",
+            "type": "object",
+            "additionalProperties": {
+               "type": "string"
+            }
+         },
+         "test4": {
+            "description": "test4",
+            "type": "object",
+            "additionalProperties": {
+               "$ref": "#/definitions/api.Child"
+            }
+         },
+         "test5": {
+            "description": "test5",
+            "type": "object",
+            "additionalProperties": {
+               "$ref": "#/definitions/api.Child"
+            }
+         },
+         "test6": {
+            "description": "test6",
+            "$ref": "#/definitions/api.MyMapType"
+         },
+         "test7": {
+            "description": "test7",
+            "type": "array",
+            "items": {
+               "$ref": "#/definitions/api.Child"
+            }
+         },
+         "test8": {
+            "description": "test8",
+            "type": "array",
+            "items": {
+               "$ref": "#/definitions/api.Child"
+            }
+         },
+         "test9": {
+            "description": "test9",
+            "type": "array",
+            "items": {
+               "type": "object",
+               "additionalProperties": {
+                  "type": "string"
+               }
+            }
+         }
+      }
+   }
+}`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+}
+
+func TestParser_ParseRouterApiInfoErr(t *testing.T) {
+	src := `
+package test
+
+// @Accept unknown
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.EqualError(t, err, "ParseComment error in file  :unknown accept type can't be accepted"+mimeTypeAliasesHint)
+}
+
+func TestParser_ParseRouterApiGet(t *testing.T) {
+	src := `
+package test
+
+// @Router /api/{id} [get]
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	ps := p.swagger.Paths.Paths
+
+	val, ok := ps["/api/{id}"]
+
+	assert.True(t, ok)
+	assert.NotNil(t, val.Get)
+}
+
+func TestParser_ParseRouterApiPOST(t *testing.T) {
+	src := `
+package test
+
+// @Router /api/{id} [post]
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	ps := p.swagger.Paths.Paths
+
+	val, ok := ps["/api/{id}"]
+
+	assert.True(t, ok)
+	assert.NotNil(t, val.Post)
+}
+
+func TestParser_ParseRouterApiDELETE(t *testing.T) {
+	src := `
+package test
+
+// @Router /api/{id} [delete]
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+	p := New()
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	ps := p.swagger.Paths.Paths
+
+	val, ok := ps["/api/{id}"]
+
+	assert.True(t, ok)
+	assert.NotNil(t, val.Delete)
+}
+
+func TestParser_ParseRouterApiPUT(t *testing.T) {
+	src := `
+package test
+
+// @Router /api/{id} [put]
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	ps := p.swagger.Paths.Paths
+
+	val, ok := ps["/api/{id}"]
+
+	assert.True(t, ok)
+	assert.NotNil(t, val.Put)
+}
+
+func TestParser_ParseRouterApiPATCH(t *testing.T) {
+	src := `
+package test
+
+// @Router /api/{id} [patch]
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	ps := p.swagger.Paths.Paths
+
+	val, ok := ps["/api/{id}"]
+
+	assert.True(t, ok)
+	assert.NotNil(t, val.Patch)
+}
+
+func TestParser_ParseRouterApiHead(t *testing.T) {
+	src := `
+package test
+
+// @Router /api/{id} [head]
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+	p := New()
+
+	err = p.ParseRouterAPIInfo("", f)
+
+	assert.NoError(t, err)
+	ps := p.swagger.Paths.Paths
+
+	val, ok := ps["/api/{id}"]
+
+	assert.True(t, ok)
+	assert.NotNil(t, val.Head)
+}
+
+func TestParser_ParseRouterApiOptions(t *testing.T) {
+	src := `
+package test
+
+// @Router /api/{id} [options]
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	ps := p.swagger.Paths.Paths
+
+	val, ok := ps["/api/{id}"]
+
+	assert.True(t, ok)
+	assert.NotNil(t, val.Options)
+}
+
+func TestParser_ParseRouterApiMultiple(t *testing.T) {
+	src := `
+package test
+
+// @Router /api/{id} [get]
+func Test1(){
+}
+
+// @Router /api/{id} [patch]
+func Test2(){
+}
+
+// @Router /api/{id} [delete]
+func Test3(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	ps := p.swagger.Paths.Paths
+
+	val, ok := ps["/api/{id}"]
+
+	assert.True(t, ok)
+	assert.NotNil(t, val.Get)
+	assert.NotNil(t, val.Patch)
+	assert.NotNil(t, val.Delete)
+}
+
+// func TestParseDeterministic(t *testing.T) {
+// 	mainAPIFile := "main.go"
+// 	for _, searchDir := range []string{
+// 		"testdata/simple",
+// 		"testdata/model_not_under_root/cmd",
+// 	} {
+// 		t.Run(searchDir, func(t *testing.T) {
+// 			var expected string
+
+// 			// run the same code 100 times and check that the output is the same every time
+// 			for i := 0; i < 100; i++ {
+// 				p := New()
+// 				p.PropNamingStrategy = PascalCase
+// 				err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+// 				b, _ := json.MarshalIndent(p.swagger, "", "    ")
+// 				assert.NotEqual(t, "", string(b))
+
+// 				if expected == "" {
+// 					expected = string(b)
+// 				}
+
+// 				assert.Equal(t, expected, string(b))
+// 			}
+// 		})
+// 	}
+// }
+
+func TestApiParseTag(t *testing.T) {
+	searchDir := "testdata/tags"
+	mainAPIFile := "main.go"
+	p := New(SetMarkdownFileDirectory(searchDir))
+	p.PropNamingStrategy = PascalCase
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	if len(p.swagger.Tags) != 3 {
+		t.Error("Number of tags did not match")
+	}
+
+	dogs := p.swagger.Tags[0]
+	if dogs.TagProps.Name != "dogs" || dogs.TagProps.Description != "Dogs are cool" {
+		t.Error("Failed to parse dogs name or description")
+	}
+
+	cats := p.swagger.Tags[1]
+	if cats.TagProps.Name != "cats" || cats.TagProps.Description != "Cats are the devil" {
+		t.Error("Failed to parse cats name or description")
+	}
+
+	if cats.TagProps.ExternalDocs.URL != "https://google.de" || cats.TagProps.ExternalDocs.Description != "google is super useful to find out that cats are evil!" {
+		t.Error("URL: ", cats.TagProps.ExternalDocs.URL)
+		t.Error("Description: ", cats.TagProps.ExternalDocs.Description)
+		t.Error("Failed to parse cats external documentation")
+	}
+}
+
+func TestApiParseTag_NonExistendTag(t *testing.T) {
+	searchDir := "testdata/tags_nonexistend_tag"
+	mainAPIFile := "main.go"
+	p := New(SetMarkdownFileDirectory(searchDir))
+	p.PropNamingStrategy = PascalCase
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.Error(t, err)
+}
+
+func TestApiParseTagDocsWithoutName(t *testing.T) {
+	p := New()
+	err := p.ParseGeneralAPIInfo("testdata/tagsFail1.go")
+	assert.EqualError(t, err, "@tag.docs.url needs to come after a @tag.name")
+}
+
+func TestApiParseTagDuplicated(t *testing.T) {
+	p := New()
+	err := p.ParseGeneralAPIInfo("testdata/tags_duplicated.go")
+	assert.NoError(t, err)
+
+	assert.Len(t, p.swagger.Tags, 2)
+	users, orders := p.swagger.Tags[0], p.swagger.Tags[1]
+	assert.Equal(t, "users", users.Name)
+	assert.Equal(t, "Users of the store", users.Description)
+	assert.Equal(t, "https://example.com/users", users.ExternalDocs.URL)
+	assert.Equal(t, "orders", orders.Name)
+	assert.Equal(t, "Orders of the users", orders.Description)
+}
+
+func TestParseTagMarkdownDescription(t *testing.T) {
+	searchDir := "testdata/tags"
+	mainAPIFile := "main.go"
+	p := New(SetMarkdownFileDirectory(searchDir))
+	p.PropNamingStrategy = PascalCase
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	if err != nil {
+		t.Error("Failed to parse api description: " + err.Error())
+	}
+
+	if len(p.swagger.Tags) != 3 {
+		t.Error("Number of tags did not match")
+	}
+
+	apes := p.swagger.Tags[2]
+	if apes.TagProps.Description == "" {
+		t.Error("Failed to parse tag description markdown file")
+	}
+}
+
+func TestParseApiMarkdownDescription(t *testing.T) {
+	searchDir := "testdata/tags"
+	mainAPIFile := "main.go"
+	p := New(SetMarkdownFileDirectory(searchDir))
+	p.PropNamingStrategy = PascalCase
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	if err != nil {
+		t.Error("Failed to parse api description: " + err.Error())
+	}
+
+	if p.swagger.Info.Description == "" {
+		t.Error("Failed to parse api description: " + err.Error())
+	}
+}
+
+func TestIgnoreInvalidPkg(t *testing.T) {
+	searchDir := "testdata/deps_having_invalid_pkg"
+	mainAPIFile := "main.go"
+	p := New()
+	if err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth); err != nil {
+		t.Error("Failed to ignore valid pkg: " + err.Error())
+	}
+}
+
+func TestFixes432(t *testing.T) {
+	searchDir := "testdata/fixes-432"
+	mainAPIFile := "cmd/main.go"
+
+	p := New()
+	if err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth); err != nil {
+		t.Error("Failed to ignore valid pkg: " + err.Error())
+	}
+}
+
+func TestParseOutsideDependencies(t *testing.T) {
+	searchDir := "testdata/pare_outside_dependencies"
+	mainAPIFile := "cmd/main.go"
+
+	p := New()
+	p.ParseDependency = true
+	if err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth); err != nil {
+		t.Error("Failed to parse api: " + err.Error())
+	}
+}
+
+func TestParseGoList(t *testing.T) {
+	searchDir := "testdata/pare_outside_dependencies"
+	mainAPIFile := "cmd/main.go"
+
+	p := New()
+	p.ParseDependency = true
+	p.ParseGoList = true
+	assert.NoError(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+
+	depTree := New()
+	depTree.ParseDependency = true
+	assert.NoError(t, depTree.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+
+	expected, _ := json.MarshalIndent(depTree.swagger, "", "    ")
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+	assert.Contains(t, p.packages.packages, "github.com/swaggo/swag/example/basic/api")
+	assert.Contains(t, p.packages.packages, "github.com/swaggo/swag/example/basic/web")
+	assert.LessOrEqual(t, len(p.packages.packages), len(depTree.packages.packages))
+
+	err := p.getAllGoFileInfoFromGoList(searchDir, "testdata/does_not_exist")
+	assert.Error(t, err)
+}
+
+func benchmarkParseDependency(b *testing.B, parseGoList bool) {
+	for i := 0; i < b.N; i++ {
+		p := New(SetDebugger(nil))
+		p.ParseDependency = true
+		p.ParseGoList = parseGoList
+		if err := p.ParseAPI("testdata/pare_outside_dependencies", "cmd/main.go", defaultParseDepth); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseDependencyDepTree(b *testing.B) {
+	benchmarkParseDependency(b, false)
+}
+
+func BenchmarkParseDependencyGoList(b *testing.B) {
+	benchmarkParseDependency(b, true)
+}
+
+func TestParseParallelism(t *testing.T) {
+	for _, searchDir := range []string{"testdata/simple", "testdata/pet", "testdata/composition"} {
+		t.Run(searchDir, func(t *testing.T) {
+			var specs []string
+			for _, parallelism := range []int{1, 4} {
+				p := New(SetDebugger(nil))
+				p.Parallelism = parallelism
+				assert.NoError(t, p.ParseAPI(searchDir, "main.go", defaultParseDepth))
+
+				b, err := json.MarshalIndent(p.swagger, "", "    ")
+				assert.NoError(t, err)
+				specs = append(specs, string(b))
+			}
+			assert.Equal(t, specs[0], specs[1])
+		})
+	}
+}
+
+func benchmarkParseParallelism(b *testing.B, parallelism int) {
+	for i := 0; i < b.N; i++ {
+		p := New(SetDebugger(nil))
+		p.Parallelism = parallelism
+		if err := p.ParseAPI("testdata/simple", "main.go", defaultParseDepth); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseSerial(b *testing.B) {
+	benchmarkParseParallelism(b, 1)
+}
+
+func BenchmarkParseParallel(b *testing.B) {
+	benchmarkParseParallelism(b, 0)
+}
+
+func TestParseStructParamCommentByQueryType(t *testing.T) {
+	src := `
+package main
+
+type Student struct {
+	Name string
+	Age int
+	Teachers []string
+	SkipField map[string]string
+}
+
+// @Param request query Student true "query params"
+// @Success 200
+// @Router /test [get]
+func Fun()  {
+
+}
+`
+	expected := `{
+    "info": {
+        "contact": {}
+    },
+    "paths": {
+        "/test": {
+            "get": {
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "name": "age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "name": "teachers",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        }
+    }
+}`
+
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, expected, string(b))
+}
+
+func TestParseRenamedStructDefinition(t *testing.T) {
+	src := `
+package main
+
+type Child struct {
+	Name string
+}//@name Student
+
+type Parent struct {
+	Name string
+	Child Child
+}//@name Teacher
+
+// @Param request body Parent true "query params"
+// @Success 200 {object} Parent
+// @Router /test [get]
+func Fun()  {
+
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	assert.NoError(t, err)
+	teacher, ok := p.swagger.Definitions["Teacher"]
+	assert.True(t, ok)
+	ref := teacher.Properties["child"].SchemaProps.Ref
+	assert.Equal(t, "#/definitions/Student", ref.String())
+	_, ok = p.swagger.Definitions["Student"]
+	assert.True(t, ok)
+	path, ok := p.swagger.Paths.Paths["/test"]
+	assert.True(t, ok)
+	assert.Equal(t, "#/definitions/Teacher", path.Get.Parameters[0].Schema.Ref.String())
+	ref = path.Get.Responses.ResponsesProps.StatusCodeResponses[200].ResponseProps.Schema.Ref
+	assert.Equal(t, "#/definitions/Teacher", ref.String())
+}
+
+func TestParseJSONFieldString(t *testing.T) {
+	expected := `{
+    "swagger": "2.0",
+    "info": {
+        "description": "This is a sample server.",
+        "title": "Swagger Example API",
+        "contact": {},
+        "version": "1.0"
+    },
+    "host": "localhost:4000",
+    "basePath": "/",
+    "paths": {
+        "/do-something": {
+            "post": {
+                "description": "Does something",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Call DoSomething",
+                "parameters": [
+                    {
+                        "description": "My Struct",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.MyStruct"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MyStruct"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "main.MyStruct": {
+            "type": "object",
+            "properties": {
+                "boolvar": {
+                    "description": "boolean as a string",
+                    "type": "string",
+                    "example": "false"
+                },
+                "floatvar": {
+                    "description": "float as a string",
+                    "type": "string",
+                    "example": "0"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 1
+                },
+                "myint": {
+                    "description": "integer as string",
+                    "type": "string",
+                    "example": "0"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "poti"
+                },
+                "truebool": {
+                    "description": "boolean as a string",
+                    "type": "string",
+                    "example": "true"
+                }
+            }
+        }
+    }
+}`
+
+	searchDir := "testdata/json_field_string"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, expected, string(b))
+}
+
+func TestParseSwaggerignoreForEmbedded(t *testing.T) {
+	src := `
+package main
+
+type Child struct {
+	ChildName string
+}//@name Student
+
+type Parent struct {
+	Name string
+	Child ` + "`swaggerignore:\"true\"`" + `
+}//@name Teacher
+
+// @Param request body Parent true "query params"
+// @Success 200 {object} Parent
+// @Router /test [get]
+func Fun()  {
+
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+	p.packages.ParseTypes()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	assert.NoError(t, err)
+	teacher, ok := p.swagger.Definitions["Teacher"]
+	assert.True(t, ok)
+
+	name, ok := teacher.Properties["name"]
+	assert.True(t, ok)
+	assert.Len(t, name.Type, 1)
+	assert.Equal(t, "string", name.Type[0])
+
+	childName, ok := teacher.Properties["childName"]
+	assert.False(t, ok)
+	assert.Empty(t, childName)
+}
+
+func TestDefineTypeOfExample(t *testing.T) {
+	var example interface{}
+	var err error
+
+	example, err = defineTypeOfExample("string", "", "example")
+	assert.NoError(t, err)
+	assert.Equal(t, example.(string), "example")
+
+	example, err = defineTypeOfExample("number", "", "12.34")
+	assert.NoError(t, err)
+	assert.Equal(t, example.(float64), 12.34)
+
+	example, err = defineTypeOfExample("boolean", "", "true")
+	assert.NoError(t, err)
+	assert.Equal(t, example.(bool), true)
+
+	example, err = defineTypeOfExample("array", "", "one,two,three")
+	assert.Error(t, err)
+	assert.Nil(t, example)
+
+	example, err = defineTypeOfExample("array", "string", "one,two,three")
+	assert.NoError(t, err)
+	arr := []string{}
+
+	for _, v := range example.([]interface{}) {
+		arr = append(arr, v.(string))
+	}
+
+	assert.Equal(t, arr, []string{"one", "two", "three"})
+
+	example, err = defineTypeOfExample("object", "", "key_one:one,key_two:two,key_three:three")
+	assert.Error(t, err)
+	assert.Nil(t, example)
+
+	example, err = defineTypeOfExample("object", "string", "key_one,key_two,key_three")
+	assert.Error(t, err)
+	assert.Nil(t, example)
+
+	example, err = defineTypeOfExample("object", "oops", "key_one:one,key_two:two,key_three:three")
+	assert.Error(t, err)
+	assert.Nil(t, example)
+
+	example, err = defineTypeOfExample("object", "string", "key_one:one,key_two:two,key_three:three")
+	assert.NoError(t, err)
+	obj := map[string]string{}
+
+	for k, v := range example.(map[string]interface{}) {
+		obj[k] = v.(string)
+	}
+
+	assert.Equal(t, obj, map[string]string{"key_one": "one", "key_two": "two", "key_three": "three"})
+
+	example, err = defineTypeOfExample("oops", "", "")
+	assert.Error(t, err)
+	assert.Nil(t, example)
+}
+
+func TestParser_ParseStructAdditionalProperties(t *testing.T) {
+	src := `
+package api
+
+type Labels struct {
+	ID    int
+	Extra map[string]string ` + "`json:\",inline\"`" + `
+}
+
+// @additionalProperties []int
+type Scores struct {
+	Name string
+}
+
+type Metadata struct {
+	Kind  string
+	Extra map[string]interface{} ` + "`json:\",inline\"`" + `
+}
+
+// @Success 200 {object} Labels
+// @Success 201 {object} Scores
+// @Success 202 {object} Metadata
+// @Router /api [get]
+func Test(){
+}
+`
+	expected := `{
+   "api.Labels": {
+      "type": "object",
+      "properties": {
+         "id": {
+            "type": "integer"
+         }
+      },
+      "additionalProperties": {
+         "type": "string"
+      }
+   },
+   "api.Metadata": {
+      "type": "object",
+      "properties": {
+         "kind": {
+            "type": "string"
+         }
+      },
+      "additionalProperties": true
+   },
+   "api.Scores": {
+      "type": "object",
+      "properties": {
+         "name": {
+            "type": "string"
+         }
+      },
+      "additionalProperties": {
+         "type": "array",
+         "items": {
+            "type": "integer"
+         }
+      }
+   }
+}`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+}
+
+func TestParser_ParseRouterApiDefaultOperationID(t *testing.T) {
+	src := `
+package api
+
+type UserController struct{}
+
+// @Router /users/{id} [get]
+func (c *UserController) GetUser(){
+}
+
+// @ID create-user
+// @Router /users [post]
+func (c *UserController) CreateUser(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+	assert.Equal(t, "", p.swagger.Paths.Paths["/users/{id}"].Get.ID)
+
+	p = New()
+	p.DefaultOperationID = true
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+	assert.Equal(t, "GetUser", p.swagger.Paths.Paths["/users/{id}"].Get.ID)
+	assert.Equal(t, "create-user", p.swagger.Paths.Paths["/users"].Post.ID)
+	assert.NoError(t, p.checkOperationIDUniqueness())
+}
+
+func TestParser_ParseRouterApiDefaultOperationIDDuplicated(t *testing.T) {
+	src := `
+package api
+
+// @Router /users [get]
+func List(){
+}
+
+// @ID List
+// @Router /users [post]
+func Create(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.DefaultOperationID = true
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+	assert.EqualError(t, p.checkOperationIDUniqueness(),
+		"duplicated operationId 'List' found in 'POST /users', previously declared in: 'GET /users'")
+}
+
+func TestParser_ParseConstEnums(t *testing.T) {
+	src := `
+package api
+
+type Status string
+
+const (
+	StatusActive  Status = "active"  // the account is active
+	StatusBlocked Status = "blocked" // the account is blocked
+)
+
+type Level int
+
+const (
+	LevelLow Level = iota + 1
+	LevelMedium
+	LevelHigh
+	levelCount = 3
+)
+
+const LevelMax = Level(10)
+
+type Account struct {
+	Status Status
+	Level  Level
+	Levels []Level
+}
+
+// @Success 200 {object} Account
+// @Router /api [get]
+func Test(){
+}
+`
+	expected := `{
+   "api.Account": {
+      "type": "object",
+      "properties": {
+         "level": {
+            "type": "integer",
+            "enum": [
+               1,
+               2,
+               3,
+               10
+            ],
+            "x-enum-varnames": [
+               "LevelLow",
+               "LevelMedium",
+               "LevelHigh",
+               "LevelMax"
+            ]
+         },
+         "levels": {
+            "type": "array",
+            "items": {
+               "type": "integer",
+               "enum": [
+                  1,
+                  2,
+                  3,
+                  10
+               ],
+               "x-enum-varnames": [
+                  "LevelLow",
+                  "LevelMedium",
+                  "LevelHigh",
+                  "LevelMax"
+               ]
+            }
+         },
+         "status": {
+            "type": "string",
+            "enum": [
+               "active",
+               "blocked"
+            ],
+            "x-enum-comments": {
+               "StatusActive": "the account is active",
+               "StatusBlocked": "the account is blocked"
+            },
+            "x-enum-descriptions": [
+               "the account is active",
+               "the account is blocked"
+            ],
+            "x-enum-varnames": [
+               "StatusActive",
+               "StatusBlocked"
+            ]
+         }
+      }
+   }
+}`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.packages.CollectAstFile("api", "api/api.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+
+	f, err = goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p = New()
+	p.EmitMsEnum = true
+	p.packages.CollectAstFile("api", "api/api.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+
+	msEnum, err := json.Marshal(p.swagger.Definitions["api.Account"].Properties["status"].Extensions["x-ms-enum"])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "Status",
+		"modelAsString": true,
+		"values": [
+			{"value": "active", "name": "StatusActive", "description": "the account is active"},
+			{"value": "blocked", "name": "StatusBlocked", "description": "the account is blocked"}
+		]
+	}`, string(msEnum))
+}
+
+func TestParseEnumComments(t *testing.T) {
+	searchDir := "testdata/enum_comments"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+}
+
+func TestParseNamedExamples(t *testing.T) {
+	searchDir := "testdata/named_examples"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+}
+
+func TestParseNamedExamplesFailed(t *testing.T) {
+	p := New()
+	err := p.parseNamedExample("@example.named", `UserCreateReq {"name": `)
+	assert.EqualError(t, err, "annotation @example.named UserCreateReq need a valid json value")
+
+	err = p.parseNamedExample("@example.named", "UserCreateReq")
+	assert.EqualError(t, err, "annotation @example.named need a name and a value")
+
+	assert.NoError(t, p.parseNamedExample("@example.named", "UserPage 2"))
+	err = p.parseNamedExample("@example.named", "UserPage 3")
+	assert.EqualError(t, err, "example UserPage is declared more than once")
+
+	operation := NewOperation(p)
+	err = operation.ParseComment(`@Param ids body []int false "ids" example(Missing)`, nil)
+	assert.EqualError(t, err, "example Missing is neither declared by @example.named nor a valid json value")
+
+	err = operation.ParseComment(`@Success 200 {string} string "ok" example(Missing)`, nil)
+	assert.EqualError(t, err, "example Missing is not declared by @example.named")
+}
+
+func TestParseTags(t *testing.T) {
+	searchDir := "testdata/tags_filter"
+	mainAPIFile := "main.go"
+	p := New(SetTags("public,!deprecated"))
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	p = New(SetTags("!internal"))
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	assert.NotContains(t, p.swagger.Paths.Paths, "/audit")
+	assert.NotNil(t, p.swagger.Paths.Paths["/users"].Get)
+	assert.NotNil(t, p.swagger.Paths.Paths["/users/{id}"].Get)
+	assert.Nil(t, p.swagger.Paths.Paths["/users/{id}"].Delete)
+	assert.NotContains(t, p.swagger.Definitions, "main.AuditLog")
+	assert.Contains(t, p.swagger.Definitions, "main.Address")
+}
+
+func TestParsePruneUnusedDefinitions(t *testing.T) {
+	searchDir := "testdata/prune_definitions"
+	mainAPIFile := "main.go"
+	p := New()
+	p.PruneUnusedDefinitions = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	p = New()
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Contains(t, p.swagger.Definitions, "main.Orphan")
+}
+
+func TestParseExternalSpecs(t *testing.T) {
+	searchDir := "testdata/external_spec"
+	mainAPIFile := "main.go"
+	p := New()
+	p.ExternalSpecs = map[string]string{"common.yaml": "common.yaml"}
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+}
+
+func TestParseExternalSpecsFailed(t *testing.T) {
+	p := New()
+	p.searchDir = "testdata/external_spec"
+	p.ExternalSpecs = map[string]string{"common.yaml": "common.yaml"}
+	operation := NewOperation(p)
+
+	err := operation.ParseComment(`@Success 200 {object} external://common.yaml#/definitions/Street`, nil)
+	assert.EqualError(t, err, "can't resolve /definitions/Street in external spec common.yaml (common.yaml)")
+
+	err = operation.ParseComment(`@Success 200 {object} external://other.yaml#/definitions/Address`, nil)
+	assert.EqualError(t, err, "external spec other.yaml is not declared in ExternalSpecs")
+
+	err = operation.ParseComment(`@Success 200 {object} external://common.yaml#/definitions/Address`, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, p.swagger.Definitions, "common.Country")
+}
+
+func TestParseMultiSearchDir(t *testing.T) {
+	searchDirs := []string{"testdata/multi_search_dir/main", "testdata/multi_search_dir/user"}
+	p := New()
+	err := p.ParseAPIMultiSearchDir(searchDirs, "main.go", defaultParseDepth)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Swagger Example API", p.swagger.Info.Title)
+	assert.Contains(t, p.swagger.Paths.Paths, "/orders")
+	assert.Contains(t, p.swagger.Paths.Paths, "/users/{id}")
+	assert.Contains(t, p.swagger.Definitions, "api.Order")
+	assert.Contains(t, p.swagger.Definitions, "user.User")
+
+	searchDirs = []string{"testdata/multi_search_dir/main", "testdata/multi_search_dir/conflict"}
+	p = New()
+	err = p.ParseAPIMultiSearchDir(searchDirs, "main.go", defaultParseDepth)
+	assert.EqualError(t, err, "conflicting definition api.Order declared in both "+
+		"testdata/multi_search_dir/conflict/api/api.go and testdata/multi_search_dir/main/api/api.go")
+}
+
+func TestParser_ParseSwagDirectiveStyle(t *testing.T) {
+	src := `
+package api
+
+//swag:summary Get a user
+//swag:param id path int true "user id"
+//swag:success 200 {string} string "ok"
+//swag:router /users/{id} [get]
+func GetUser(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+	assert.NotContains(t, p.swagger.Paths.Paths, "/users/{id}")
+
+	p = New()
+	p.SwagDirectiveStyle = true
+	err = p.ParseRouterAPIInfo("", f)
+	assert.NoError(t, err)
+	op := p.swagger.Paths.Paths["/users/{id}"].Get
+	if assert.NotNil(t, op) {
+		assert.Equal(t, "Get a user", op.Summary)
+		assert.Len(t, op.Parameters, 1)
+		assert.Contains(t, op.Responses.StatusCodeResponses, 200)
+	}
+
+	p = New()
+	p.SwagDirectiveStyle = true
+	err = p.ParseGeneralAPIInfo("testdata/swag_directive/main.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "Swagger Example API", p.swagger.Info.Title)
+	assert.Equal(t, "1.0", p.swagger.Info.Version)
+	assert.Equal(t, "This is a sample server.", p.swagger.Info.Description)
+	assert.Equal(t, "/v1", p.swagger.BasePath)
+}
+
+func TestParseExcludes(t *testing.T) {
+	searchDir := "testdata/excludes"
+	mainAPIFile := "main.go"
+
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Contains(t, p.swagger.Paths.Paths, "/admin/reset")
+
+	for _, excludes := range []string{"admin", "admin/internalapi", "admin/*", "api, admin"} {
+		p = New(SetExcludedDirsAndFiles(excludes))
+		err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+		assert.NoError(t, err)
+		assert.NotContains(t, p.swagger.Paths.Paths, "/admin/reset", excludes)
+	}
+
+	p = New(SetExcludedDirsAndFiles("admin"))
+	p.ParseDependency = true
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Contains(t, p.swagger.Paths.Paths, "/pets")
+	assert.NotContains(t, p.swagger.Paths.Paths, "/admin/reset")
+}
+
+func TestParseBindingRequired(t *testing.T) {
+	searchDir := "testdata/binding_required"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+}
+
+func TestParseRouterPrefix(t *testing.T) {
+	searchDir := "testdata/router_prefix"
+	mainAPIFile := "main.go"
+	p := New()
+	p.PrefixAnnotation = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	p = New()
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Contains(t, p.swagger.Paths.Paths, "/users")
+	assert.NotContains(t, p.swagger.Paths.Paths, "/v1/users")
+}
+
+func TestParseUndocumentedPathParams(t *testing.T) {
+	src := `
+package api
+
+// @Param bucket path int true "the bucket"
+// @Router /buckets/{bucket}/files/{path} [get]
+func GetFile() {}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	debugger := &recordingDebugger{}
+	p := New(SetDebugger(debugger))
+	err = p.ParseRouterAPIInfo("api.go", f)
+	assert.NoError(t, err)
+
+	params := p.swagger.Paths.Paths["/buckets/{bucket}/files/{path}"].Get.Parameters
+	assert.Len(t, params, 2)
+	assert.Equal(t, "bucket", params[0].Name)
+	assert.Equal(t, "integer", params[0].Type)
+	assert.Equal(t, "the bucket", params[0].Description)
+	assert.Equal(t, "path", params[1].Name)
+	assert.Equal(t, "path", params[1].In)
+	assert.Equal(t, "string", params[1].Type)
+	assert.True(t, params[1].Required)
+	assert.Equal(t, []string{
+		"warning: path parameter path of /buckets/{bucket}/files/{path} has no @Param in api.go, it is documented as a required string",
+	}, debugger.messages)
+}
+
+func TestParseRouterQueryHints(t *testing.T) {
+	src := `
+package api
+
+// @Param q query string true "the searched text"
+// @Router /search?q&page [get]
+func Search() {}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	debugger := &recordingDebugger{}
+	p := New(SetDebugger(debugger))
+	err = p.ParseRouterAPIInfo("api.go", f)
+	assert.NoError(t, err)
+
+	assert.NotContains(t, p.swagger.Paths.Paths, "/search?q&page")
+	params := p.swagger.Paths.Paths["/search"].Get.Parameters
+	assert.Len(t, params, 1)
+	assert.Equal(t, "q", params[0].Name)
+	assert.Equal(t, []string{
+		"warning: query parameter page of the @Router of /search has no @Param in api.go",
+	}, debugger.messages)
+}
+
+func TestParseResponseHeadersOfStruct(t *testing.T) {
+	searchDir := "testdata/header_struct"
+	p := New()
+	err := p.getAllGoFileInfo("testdata", searchDir)
+	assert.NoError(t, err)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	astFile := p.findAstFile(filepath.Join(searchDir, "api.go"))
+	operation := NewOperation(p)
+	assert.NoError(t, operation.ParseComment(`@Success 200 "the user"`, astFile))
+	assert.NoError(t, operation.ParseComment(`@Header 200 {object} RateLimitHeaders`, astFile))
+
+	headers := operation.Responses.StatusCodeResponses[200].Headers
+	assert.Len(t, headers, 4)
+	assert.Equal(t, "integer", headers["X-Rate-Limit-Limit"].Type)
+	assert.Equal(t, "the number of requests allowed per hour", headers["X-Rate-Limit-Limit"].Description)
+	assert.Equal(t, "integer", headers["X-Rate-Limit-Remaining"].Type)
+	assert.Equal(t, "the number of requests left for the hour", headers["X-Rate-Limit-Remaining"].Description)
+	assert.Equal(t, "string", headers["X-Rate-Limit-Reset"].Type)
+	assert.Equal(t, "date-time", headers["X-Rate-Limit-Reset"].Format)
+	assert.Equal(t, "string", headers["X-Request-Id"].Type)
+	assert.NotContains(t, headers, "internal")
+
+	operation = NewOperation(p)
+	assert.NoError(t, operation.ParseComment(`@Success 200 "the users"`, astFile))
+	err = operation.ParseComment(`@Header 200 {object} Pagination`, astFile)
+	assert.EqualError(t, err, "field links of Pagination can't be a header, only the fields of a primitive type can")
+}
+
+func TestParseRouterPrefixFailed(t *testing.T) {
+	for _, src := range []string{
+		`package api
+// @RouterPrefix /v1
+// @RouterPrefix /v2
+`,
+		`package api
+// @RouterPrefix v1
+`,
+	} {
+		f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+		assert.NoError(t, err)
+
+		p := New()
+		p.PrefixAnnotation = true
+		assert.Error(t, p.ParseRouterAPIInfo("api.go", f))
+	}
+}
+
+func TestPrefixRouterPath(t *testing.T) {
+	assert.Equal(t, "/v1/users", prefixRouterPath("/v1", "/users"))
+	assert.Equal(t, "/v1/users", prefixRouterPath("/v1", "/v1/users"))
+	assert.Equal(t, "/v1/v1beta", prefixRouterPath("/v1", "/v1beta"))
+	assert.Equal(t, "/v1", prefixRouterPath("/v1", "/"))
+	assert.Equal(t, "/users", prefixRouterPath("", "/users"))
+}
+
+func TestParseFieldFormat(t *testing.T) {
+	src := `
+package model
+
+type User struct {
+	ID      string ` + "`format:\"uuid\"`" + `
+	Email   string ` + "`format:\"email\"`" + `
+	Country string ` + "`format:\"iso-3166\"`" + `
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	debugger := &recordingDebugger{}
+	p := New(SetDebugger(debugger))
+	p.packages.CollectAstFile("model", "model.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	schema, err := p.getTypeSchema("User", f, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid", schema.Properties["id"].Format)
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+	assert.Equal(t, "iso-3166", schema.Properties["country"].Format)
+	assert.Contains(t, debugger.messages, "Unknown format iso-3166 is kept as it is")
+	assert.NotContains(t, debugger.messages, "Unknown format uuid is kept as it is")
+}
+
+func TestParseFieldEnumDescriptions(t *testing.T) {
+	src := `
+package model
+
+type Order struct {
+	Fruit  string ` + "`enums:\"a,b,c\" enumDescriptions:\"Apple, Banana, Cherry\"`" + `
+}
+
+type Basket struct {
+	Fruit  string ` + "`enums:\"a,b,c\" enumDescriptions:\"Apple\"`" + `
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.packages.CollectAstFile("model", "model.go", f)
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	schema, err := p.getTypeSchema("Order", f, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, schema.Properties["fruit"].Enum)
+	assert.Equal(t, []string{"Apple", "Banana", "Cherry"}, schema.Properties["fruit"].Extensions["x-enum-descriptions"])
+
+	_, err = p.getTypeSchema("Basket", f, false)
+	assert.EqualError(t, err, "enumDescriptions of field Fruit has 1 descriptions for 3 enums")
+}
+
+func TestParseOAuth2Scopes(t *testing.T) {
+	src := `
+package main
+
+// @securitydefinitions.oauth2.accessCode OAuth2AccessCode
+// @authorizationUrl https://example.com/oauth/authorize
+// @tokenUrl https://example.com/oauth/token
+// @scope.read Grants read access
+// @scope.write Grants write access
+// @securitydefinitions.oauth2.implicit OAuth2Implicit
+// @authorizationUrl https://other.example.com/oauth/authorize
+// @scope.admin Grants read and write access to administrative information
+func main() {}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "main.go", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	assert.NoError(t, p.parseGeneralAPIInfo("main.go", f.Comments, map[string]string{}))
+
+	accessCode := p.swagger.SecurityDefinitions["OAuth2AccessCode"]
+	assert.Equal(t, "https://example.com/oauth/authorize", accessCode.AuthorizationURL)
+	assert.Equal(t, "https://example.com/oauth/token", accessCode.TokenURL)
+	assert.Equal(t, map[string]string{"read": "Grants read access", "write": "Grants write access"}, accessCode.Scopes)
+
+	implicit := p.swagger.SecurityDefinitions["OAuth2Implicit"]
+	assert.Equal(t, "https://other.example.com/oauth/authorize", implicit.AuthorizationURL)
+	assert.Equal(t, map[string]string{"admin": "Grants read and write access to administrative information"}, implicit.Scopes)
+
+	src = `
+package main
+
+// @securitydefinitions.oauth2.accessCode OAuth2AccessCode
+// @authorizationUrl https://example.com/oauth/authorize
+// @securitydefinitions.oauth2.password OAuth2Password
+// @tokenUrl https://example.com/oauth/token
+func main() {}
+`
+	f, err = goparser.ParseFile(token.NewFileSet(), "main.go", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p = New()
+	err = p.parseGeneralAPIInfo("main.go", f.Comments, map[string]string{})
+	assert.EqualError(t, err, "@securitydefinitions.oauth2.accesscode is [@tokenurl @authorizationurl] required")
+}
+
+func TestParseContinueOnError(t *testing.T) {
+	searchDir := "testdata/continue_on_error"
+	mainAPIFile := "main.go"
+	p := New()
+	assert.Error(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+
+	debugger := &recordingDebugger{}
+	p = New(SetDebugger(debugger))
+	p.ContinueOnError = true
+	assert.NoError(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+	assert.Contains(t, p.swagger.Paths.Paths, "/pets/{id}")
+	assert.NotContains(t, p.swagger.Paths.Paths, "/pets")
+	assert.Contains(t, p.swagger.Definitions, "main.Pet")
+
+	brokenFile := filepath.Join(searchDir, "broken.go")
+	assert.Contains(t, p.skippedFiles, brokenFile)
+	assert.Contains(t, debugger.messages, "warning: skipped 1 files failing to parse: "+brokenFile)
+}
+
+func TestParseContinueOnErrorWithAnnotation(t *testing.T) {
+	src := `
+package api
+
+// @Router /ok [get]
+func Ok() {}
+
+// @Param id query int true
+// @Router /broken [get]
+func Broken() {}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "api.go", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	p := New()
+	p.ContinueOnError = true
+	p.packages.CollectAstFile("api", "api.go", f)
+	assert.NoError(t, p.packages.RangeFiles(p.parseRouterAPIInfo))
+	assert.Empty(t, p.swagger.Paths.Paths)
+	assert.Contains(t, p.skippedFiles, "api.go")
+
+	err = p.checkSkippedFiles()
+	assert.EqualError(t, err, "no file could be parsed, skipped: api.go")
+}
+
+func TestParseSwaggerIgnore(t *testing.T) {
+	searchDir := "testdata/swaggerignore"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	p = New()
+	p.RequiredByDefault = true
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	b, _ = json.Marshal(p.swagger)
+	for _, name := range []string{"revision", "internal", "cost", "author", "color", "secret"} {
+		assert.NotContains(t, string(b), `"`+name+`"`)
+	}
+}
+
+func TestParseDedupeInlineSchemas(t *testing.T) {
+	searchDir := "testdata/dedupe_inline"
+	mainAPIFile := "main.go"
+	p := New()
+	p.DedupeInlineSchemas = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	p = New()
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Len(t, p.swagger.Definitions, 1)
+	assert.Equal(t, []string{"InlineSchema1", "InlineSchema2"}, DedupeInlineSchemas(p.swagger))
+	assert.Empty(t, DedupeInlineSchemas(p.swagger))
+}
+
+func TestParseOneOfResponses(t *testing.T) {
+	searchDir := "testdata/one_of"
+	mainAPIFile := "main.go"
+	p := New()
+	p.PruneUnusedDefinitions = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	astFile := p.findAstFile(filepath.Join(searchDir, "api.go"))
+	operation := NewOperation(p)
+	err = operation.ParseComment(`@Success 200 {oneOf} Created,Missing "the event"`, astFile)
+	assert.Error(t, err)
+	err = operation.ParseComment(`@Success 200 {oneOf} Created,string "the event"`, astFile)
+	assert.EqualError(t, err, "{oneOf} lists only models, string has no definition")
+	err = operation.ParseComment(`@Success 200 {object} Created "the event" discriminator(type)`, astFile)
+	assert.EqualError(t, err, "discriminator(type) is only allowed on {oneOf} and {anyOf} responses")
+
+	err = operation.ParseComment(`@Success 201 {oneOf} Created,Deleted discriminator(type) example({"type":"created"})`, astFile)
+	assert.NoError(t, err)
+	resp := operation.Responses.StatusCodeResponses[201]
+	assert.Equal(t, "Created", resp.Description)
+	assert.Equal(t, "type", resp.Schema.Discriminator)
+	assert.Equal(t, map[string]interface{}{"application/json": map[string]interface{}{"type": "created"}}, resp.Examples)
+}
+
+func TestParseServers(t *testing.T) {
+	src := `
+package main
+
+// @host api.example.com
+// @host staging.example.com
+// @server https://eu.example.com/v1 Europe
+// @server https://us.example.com/v1
+func main() {}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "main.go", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	debugger := &recordingDebugger{}
+	p := New(SetDebugger(debugger))
+	assert.NoError(t, p.parseGeneralAPIInfo("main.go", f.Comments, map[string]string{}))
+	assert.Equal(t, "api.example.com", p.swagger.Host)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"url": "staging.example.com"},
+		map[string]interface{}{"url": "https://eu.example.com/v1", "description": "Europe"},
+		map[string]interface{}{"url": "https://us.example.com/v1"},
+	}, p.swagger.Extensions["x-servers"])
+	assert.Contains(t, debugger.messages,
+		"warning: Swagger 2.0 has a single host, api.example.com is used and staging.example.com is only listed in x-servers")
+
+	f, err = goparser.ParseFile(token.NewFileSet(), "main.go", "package main\n\n// @server\nfunc main() {}\n", goparser.ParseComments)
+	assert.NoError(t, err)
+	err = New().parseGeneralAPIInfo("main.go", f.Comments, nil)
+	assert.EqualError(t, err, "annotation @server need an url")
+}
+
+func TestParseInferResponses(t *testing.T) {
+	// the handlers import gin, they are parsed as they are since the package can't be listed
+	searchDir := "testdata/infer_responses"
+	p := New()
+	p.InferResponses = true
+	assert.NoError(t, p.parseFile("main", filepath.Join(searchDir, "api.go"), nil))
+	var err error
+	p.parsedSchemas, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+	assert.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	p = New()
+	assert.NoError(t, p.parseFile("main", filepath.Join(searchDir, "api.go"), nil))
+	p.parsedSchemas, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+	assert.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+	assert.Nil(t, p.swagger.Paths.Paths["/users"].Get.Responses)
+}
+
+func TestParseInterfaceFields(t *testing.T) {
+	searchDir := "testdata/interface_fields"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	p = New()
+	p.ResolveInterfaces = true
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	shape := p.swagger.Definitions["main.Shape"]
+	assert.Equal(t, []spec.Schema{*RefSchema("main.Circle"), *RefSchema("main.Square")}, shape.Extensions["x-oneOf"])
+	assert.NotContains(t, p.swagger.Definitions, "main.Line")
+
+	drawing := p.swagger.Definitions["main.Drawing"]
+	assert.Equal(t, *RefSchema("main.Shape"), drawing.Properties["shape"])
+	assert.Equal(t, RefSchema("main.Shape"), drawing.Properties["shapes"].Items.Schema)
+	assert.Equal(t, spec.Schema{}, drawing.Properties["meta"])
+}
+
+func TestParseVendor(t *testing.T) {
+	searchDir := "testdata/vendored"
+	mainAPIFile := "main.go"
+	p := New()
+	p.ParseVendor = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	debugger := &recordingDebugger{}
+	p = New(SetDebugger(debugger))
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot find type definition: money.Amount")
+	assert.Contains(t, debugger.messages, "warning: type money.Amount may be vendored, enable ParseVendor to resolve the types of the vendor folder")
+}
+
+func TestParseReplacedModules(t *testing.T) {
+	searchDir := "testdata/replace_module/app"
+	mainAPIFile := "main.go"
+	p := New()
+	p.ParseDependency = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	// the module replaced by a directory of the search dir is found without parsing the dependencies
+	p = New()
+	err = p.getAllGoFileInfo("example.com/app", searchDir)
+	assert.NoError(t, err)
+	assert.Contains(t, p.packages.packages, "example.com/foo")
+	assert.NotContains(t, p.packages.packages, "example.com/app/internal/foo")
+
+	mod, err := findGoModule(searchDir)
+	assert.NoError(t, err)
+	absSearchDir, _ := filepath.Abs(searchDir)
+	assert.Equal(t, "example.com/app", mod.path)
+	assert.Equal(t, map[string]string{
+		"example.com/foo":    filepath.Join(absSearchDir, "internal", "foo"),
+		"example.com/shared": filepath.Join(filepath.Dir(absSearchDir), "shared"),
+	}, mod.replaces)
+}
+
+func TestParseHiddenOperations(t *testing.T) {
+	searchDir := "testdata/hidden_operations"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
+
+	p = New()
+	p.IncludeHidden = true
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Contains(t, p.swagger.Paths.Paths, "/audits")
+	assert.NotNil(t, p.swagger.Paths.Paths["/pets/{id}"].Post)
+	assert.Contains(t, p.swagger.Definitions, "main.Audit")
+	assert.Contains(t, p.swagger.Definitions, "main.Pet")
+}
+
+func TestParseArrayConstraints(t *testing.T) {
+	searchDir := "testdata/array_constraints"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
 
-	err = p.ParseRouterAPIInfo("", f)
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
 	assert.NoError(t, err)
 
 	b, _ := json.MarshalIndent(p.swagger, "", "    ")
-	assert.Equal(t, expected, string(b))
+	assert.Equal(t, string(expected), string(b))
+
+	quantities := p.swagger.Definitions["main.Basket"].Properties["quantities"]
+	assert.Equal(t, int64(1), *quantities.MinItems)
+	assert.Equal(t, int64(5), *quantities.MaxItems)
+	assert.True(t, quantities.UniqueItems)
+	assert.Equal(t, float64(0), *quantities.Items.Schema.Minimum)
+	assert.Nil(t, quantities.Items.Schema.Maximum)
 }
 
-func TestParseRenamedStructDefinition(t *testing.T) {
-	src := `
-package main
+func TestParseDefaultSchemesAndMimeTypes(t *testing.T) {
+	searchDir := "testdata/default_mime_types"
+	mainAPIFile := "main.go"
+	p := New()
+	p.DefaultSchemes = []string{"https"}
+	p.DefaultConsumes = []string{"json"}
+	p.DefaultProduces = []string{"application/json", "xml"}
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
 
-type Child struct {
-	Name string
-}//@name Student
+	assert.Equal(t, []string{"https"}, p.swagger.Schemes)
+	assert.Equal(t, []string{"application/json"}, p.swagger.Consumes)
+	assert.Equal(t, []string{"application/json", "text/xml"}, p.swagger.Produces)
 
-type Parent struct {
-	Name string
-	Child Child
-}//@name Teacher
+	pets := p.swagger.Paths.Paths["/pets"]
+	assert.Equal(t, []string{"application/json"}, pets.Get.Consumes)
+	assert.Equal(t, []string{"application/json", "text/xml"}, pets.Get.Produces)
+	assert.Equal(t, []string{"text/xml"}, pets.Post.Consumes)
+	assert.Equal(t, []string{"text/plain"}, pets.Post.Produces)
 
-// @Param request body Parent true "query params"
-// @Success 200 {object} Parent
-// @Router /test [get]
-func Fun()  {
+	p = New()
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	assert.Empty(t, p.swagger.Schemes)
+	assert.Empty(t, p.swagger.Consumes)
+	assert.Empty(t, p.swagger.Paths.Paths["/pets"].Get.Produces)
 
+	p = New()
+	p.DefaultProduces = []string{"unknown"}
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.EqualError(t, err, "unknown default produce type can't be accepted"+mimeTypeAliasesHint)
 }
-`
-	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
-	assert.NoError(t, err)
 
+func TestParsePointerFields(t *testing.T) {
+	searchDir := "testdata/pointer_fields"
+	mainAPIFile := "main.go"
 	p := New()
-	p.packages.CollectAstFile("api", "api/api.go", f)
-	_, err = p.packages.ParseTypes()
+	p.Nullable = true
+	p.RequiredByDefault = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
 
-	err = p.ParseRouterAPIInfo("", f)
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
 	assert.NoError(t, err)
 
-	assert.NoError(t, err)
-	teacher, ok := p.swagger.Definitions["Teacher"]
-	assert.True(t, ok)
-	ref := teacher.Properties["child"].SchemaProps.Ref
-	assert.Equal(t, "#/definitions/Student", ref.String())
-	_, ok = p.swagger.Definitions["Student"]
-	assert.True(t, ok)
-	path, ok := p.swagger.Paths.Paths["/test"]
-	assert.True(t, ok)
-	assert.Equal(t, "#/definitions/Teacher", path.Get.Parameters[0].Schema.Ref.String())
-	ref = path.Get.Responses.ResponsesProps.StatusCodeResponses[200].ResponseProps.Schema.Ref
-	assert.Equal(t, "#/definitions/Teacher", ref.String())
-}
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
 
-func TestParseJSONFieldString(t *testing.T) {
-	expected := `{
-    "swagger": "2.0",
-    "info": {
-        "description": "This is a sample server.",
-        "title": "Swagger Example API",
-        "contact": {},
-        "version": "1.0"
-    },
-    "host": "localhost:4000",
-    "basePath": "/",
-    "paths": {
-        "/do-something": {
-            "post": {
-                "description": "Does something",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Call DoSomething",
-                "parameters": [
-                    {
-                        "description": "My Struct",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.MyStruct"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/main.MyStruct"
-                        }
-                    },
-                    "500": {
-                        "description": ""
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "main.MyStruct": {
-            "type": "object",
-            "properties": {
-                "boolvar": {
-                    "description": "boolean as a string",
-                    "type": "string",
-                    "example": "false"
-                },
-                "floatvar": {
-                    "description": "float as a string",
-                    "type": "string",
-                    "example": "0"
-                },
-                "id": {
-                    "type": "integer",
-                    "format": "int64",
-                    "example": 1
-                },
-                "myint": {
-                    "description": "integer as string",
-                    "type": "string",
-                    "example": "0"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "poti"
-                },
-                "truebool": {
-                    "description": "boolean as a string",
-                    "type": "string",
-                    "example": "true"
-                }
-            }
-        }
-    }
-}`
+	settings := p.swagger.Definitions["main.Settings"]
+	for name, typeAndFormat := range map[string][2]string{
+		"limit":      {INTEGER, ""},
+		"enabled":    {BOOLEAN, ""},
+		"name":       {STRING, ""},
+		"updated_at": {STRING, "date-time"},
+		"count":      {INTEGER, ""},
+		"retries":    {INTEGER, ""},
+		"expires_at": {STRING, "date-time"},
+	} {
+		assert.Equal(t, spec.StringOrArray{typeAndFormat[0]}, settings.Properties[name].Type, name)
+		assert.Equal(t, typeAndFormat[1], settings.Properties[name].Format, name)
+		assert.Equal(t, true, settings.Properties[name].Extensions["x-nullable"], name)
+	}
+	assert.NotContains(t, settings.Properties["version"].Extensions, "x-nullable")
+	// omitempty makes a field optional even when the fields are required by default
+	assert.Equal(t, []string{"count", "version"}, settings.Required)
+}
 
-	searchDir := "testdata/json_field_string"
+func TestParseTypeAliases(t *testing.T) {
+	searchDir := "testdata/type_aliases"
 	mainAPIFile := "main.go"
 	p := New()
 	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
+
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
 	b, _ := json.MarshalIndent(p.swagger, "", "    ")
-	assert.Equal(t, expected, string(b))
+	assert.Equal(t, string(expected), string(b))
+
+	assert.NotContains(t, p.swagger.Definitions, "main.Account")
+	profile := p.swagger.Definitions["main.Profile"]
+	assert.Equal(t, *RefSchema("main.User"), profile.Properties["account"])
+	assert.Equal(t, spec.StringOrArray{STRING}, profile.Properties["emails"].Items.Schema.Type)
+	assert.Equal(t, *RefSchema("main.Point"), profile.Properties["home"])
 }
 
-func TestParseSwaggerignoreForEmbedded(t *testing.T) {
-	src := `
-package main
+func TestParseReadOnlyWriteOnly(t *testing.T) {
+	searchDir := "testdata/read_write_only"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
 
-type Child struct {
-	ChildName string
-}//@name Student
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
 
-type Parent struct {
-	Name string
-	Child ` + "`swaggerignore:\"true\"`" + `
-}//@name Teacher
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
 
-// @Param request body Parent true "query params"
-// @Success 200 {object} Parent
-// @Router /test [get]
-func Fun()  {
+	account := p.swagger.Definitions["main.Account"]
+	assert.True(t, account.Properties["id"].ReadOnly)
+	assert.Equal(t, spec.StringOrArray{INTEGER}, account.Properties["id"].Type)
+	assert.True(t, account.Properties["created_at"].ReadOnly)
+	assert.False(t, account.Properties["login"].ReadOnly)
+	assert.Equal(t, true, account.Properties["password"].Extensions["x-writeOnly"])
+	assert.Equal(t, true, account.Properties["secret"].Extensions["x-writeOnly"])
+	assert.Equal(t, spec.StringOrArray{STRING}, account.Properties["secret"].Type)
+	assert.Nil(t, account.Properties["login"].Extensions)
+}
+
+func TestParseReadOnlyAndWriteOnlyField(t *testing.T) {
+	src := `
+package model
 
+type Account struct {
+	Password string ` + "`json:\"password\" readonly:\"true\" writeonly:\"true\"`" + `
 }
 `
 	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
 	assert.NoError(t, err)
 
 	p := New()
-	p.packages.CollectAstFile("api", "api/api.go", f)
-	p.packages.ParseTypes()
-	err = p.ParseRouterAPIInfo("", f)
+	p.packages.CollectAstFile("model", "model.go", f)
+	_, err = p.packages.ParseTypes()
 	assert.NoError(t, err)
 
+	_, err = p.getTypeSchema("Account", f, false)
+	assert.EqualError(t, err, "field Password can't be both readonly and writeonly")
+}
+
+func TestParseInlineSingleUse(t *testing.T) {
+	searchDir := "testdata/inline_single_use"
+	mainAPIFile := "main.go"
+	p := New()
+	p.InlineSingleUse = true
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
-	teacher, ok := p.swagger.Definitions["Teacher"]
-	assert.True(t, ok)
 
-	name, ok := teacher.Properties["name"]
-	assert.True(t, ok)
-	assert.Len(t, name.Type, 1)
-	assert.Equal(t, "string", name.Type[0])
+	expected, err := ioutil.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
 
-	childName, ok := teacher.Properties["childName"]
-	assert.False(t, ok)
-	assert.Empty(t, childName)
-}
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	assert.Equal(t, string(expected), string(b))
 
-func TestDefineTypeOfExample(t *testing.T) {
-	var example interface{}
-	var err error
+	var names []string
+	for name := range p.swagger.Definitions {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"main.Category", "main.Customer", "main.Money"}, names)
+	receipt := p.swagger.Paths.Paths["/receipts/{id}"].Get.Responses.StatusCodeResponses[200].Schema
+	assert.Equal(t, *RefSchema("main.Money"), receipt.Properties["lines"].Items.Schema.Properties["price"])
 
-	example, err = defineTypeOfExample("string", "", "example")
+	p = New()
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
 	assert.NoError(t, err)
-	assert.Equal(t, example.(string), "example")
+	assert.Len(t, p.swagger.Definitions, 6)
+}
 
-	example, err = defineTypeOfExample("number", "", "12.34")
-	assert.NoError(t, err)
-	assert.Equal(t, example.(float64), 12.34)
+func TestParseAPIFromFileSystem(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/go.mod": {Data: []byte("module example.com/app\n")},
+		"app/main.go": {Data: []byte(`package main
 
-	example, err = defineTypeOfExample("boolean", "", "true")
-	assert.NoError(t, err)
-	assert.Equal(t, example.(bool), true)
+// @title In memory API
+// @version 1.0
+func main() {}
+`)},
+		"app/api/users.go": {Data: []byte(`package api
 
-	example, err = defineTypeOfExample("array", "", "one,two,three")
-	assert.Error(t, err)
-	assert.Nil(t, example)
+import "example.com/app/model"
 
-	example, err = defineTypeOfExample("array", "string", "one,two,three")
-	assert.NoError(t, err)
-	arr := []string{}
+// @Summary Get a user
+// @Success 200 {object} model.User
+// @Router /users/{id} [get]
+func GetUser() {}
 
-	for _, v := range example.([]interface{}) {
-		arr = append(arr, v.(string))
+var _ model.User
+`)},
+		"app/model/user.go": {Data: []byte(`package model
+
+// User of the API
+type User struct {
+	ID   int    ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+`)},
+		"app/model/user_test.go": {Data: []byte("package model\n\nfunc broken(")},
 	}
 
-	assert.Equal(t, arr, []string{"one", "two", "three"})
+	p := New()
+	p.FileSystem = fsys
+	err := p.ParseAPI("./app/", "main.go", defaultParseDepth)
+	assert.NoError(t, err)
 
-	example, err = defineTypeOfExample("object", "", "key_one:one,key_two:two,key_three:three")
-	assert.Error(t, err)
-	assert.Nil(t, example)
+	assert.Equal(t, "In memory API", p.swagger.Info.Title)
+	assert.Equal(t, *RefSchema("model.User"), *p.swagger.Paths.Paths["/users/{id}"].Get.Responses.StatusCodeResponses[200].Schema)
+	assert.Equal(t, spec.StringOrArray{OBJECT}, p.swagger.Definitions["model.User"].Type)
+	assert.Len(t, p.swagger.Definitions["model.User"].Properties, 2)
+	assert.NotNil(t, p.packages.findTypeSpec("example.com/app/model", "User"))
 
-	example, err = defineTypeOfExample("object", "string", "key_one,key_two,key_three")
-	assert.Error(t, err)
-	assert.Nil(t, example)
+	p = New()
+	p.FileSystem = fsys
+	err = p.ParseAPI("app", "missing.go", defaultParseDepth)
+	assert.EqualError(t, err, "cannot find the general API info file missing.go, tried app/missing.go and missing.go")
 
-	example, err = defineTypeOfExample("object", "oops", "key_one:one,key_two:two,key_three:three")
-	assert.Error(t, err)
-	assert.Nil(t, example)
+	p = New()
+	p.FileSystem = fsys
+	p.ParseDependency = true
+	err = p.ParseAPI("app", "main.go", defaultParseDepth)
+	assert.EqualError(t, err, "ParseDependency isn't supported along with FileSystem")
+}
 
-	example, err = defineTypeOfExample("object", "string", "key_one:one,key_two:two,key_three:three")
+func TestParseAPIFromFileSystemWithFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/go.mod": {Data: []byte("module example.com/app\n")},
+		"app/main.go": {Data: []byte(`package main
+
+// @title In memory API
+// @version 1.0
+// @description.file description.md
+func main() {}
+
+// @Summary List the users
+// @x-codeSample.file curl list_users
+// @Router /users [get]
+func ListUsers() {}
+`)},
+		"app/description.md":      {Data: []byte("Read from the file system")},
+		"samples/list_users.curl": {Data: []byte("curl /users")},
+	}
+
+	p := New(SetCodeExamplesDirectory("samples"))
+	p.FileSystem = fsys
+	err := p.ParseAPI("app", "main.go", defaultParseDepth)
 	assert.NoError(t, err)
-	obj := map[string]string{}
 
-	for k, v := range example.(map[string]interface{}) {
-		obj[k] = v.(string)
-	}
+	assert.Equal(t, "Read from the file system", p.swagger.Info.Description)
+	assert.Equal(t, []interface{}{map[string]interface{}{"lang": "curl", "source": "curl /users"}},
+		p.swagger.Paths.Paths["/users"].Get.Extensions["x-codeSamples"])
+}
 
-	assert.Equal(t, obj, map[string]string{"key_one": "one", "key_two": "two", "key_three": "three"})
+func TestParseLocale(t *testing.T) {
+	searchDir := "testdata/localized"
+	mainAPIFile := "main.go"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
 
-	example, err = defineTypeOfExample("oops", "", "")
-	assert.Error(t, err)
-	assert.Nil(t, example)
+	get := p.swagger.Paths.Paths["/users/{id}"].Get
+	assert.Equal(t, "Get a user", get.Summary)
+	assert.Equal(t, "Returns the user of the id.", get.Description)
+	assert.Equal(t, "ユーザー取得", get.Extensions["x-summary-ja"])
+	assert.Equal(t, "IDのユーザーを返します。\n存在しない場合は404です。", get.Extensions["x-description-ja"])
+
+	p = New()
+	p.Locale = "ja"
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+
+	get = p.swagger.Paths.Paths["/users/{id}"].Get
+	assert.Equal(t, "ユーザー取得", get.Summary)
+	assert.Equal(t, "IDのユーザーを返します。\n存在しない場合は404です。", get.Description)
+	assert.Equal(t, "ユーザー取得", get.Extensions["x-summary-ja"])
+	// the operations without translation keep their summary
+	assert.Equal(t, "Delete a user", p.swagger.Paths.Paths["/users/{id}"].Delete.Summary)
 }
@@ -28,6 +28,16 @@ const (
 	FUNC = "func"
 )
 
+// knownFormats are the formats defined by swagger 2.0 and json schema, other formats are allowed but tools may
+// ignore them
+var knownFormats = map[string]bool{
+	"int32": true, "int64": true, "float": true, "double": true, "byte": true, "binary": true, "date": true,
+	"date-time": true, "time": true, "duration": true, "password": true, "email": true, "idn-email": true,
+	"hostname": true, "idn-hostname": true, "ipv4": true, "ipv6": true, "uri": true, "uri-reference": true,
+	"iri": true, "iri-reference": true, "uri-template": true, "uuid": true, "json-pointer": true,
+	"relative-json-pointer": true, "regex": true,
+}
+
 // CheckSchemaType checks if typeName is not a name of primitive type
 func CheckSchemaType(typeName string) error {
 	if !IsPrimitiveType(typeName) {
@@ -81,6 +91,22 @@ func TransToValidSchemeType(typeName string) string {
 	}
 }
 
+// TransToValidSchemeFormat returns the swagger format of a golang basic type whose size is fixed, empty otherwise.
+func TransToValidSchemeFormat(typeName string) string {
+	switch typeName {
+	case "int32", "rune":
+		return "int32"
+	case "int64":
+		return "int64"
+	case "float32":
+		return "float"
+	case "float64":
+		return "double"
+	default:
+		return ""
+	}
+}
+
 // IsGolangPrimitiveType determine whether the type name is a golang primitive type
 func IsGolangPrimitiveType(typeName string) bool {
 	switch typeName {
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/swaggo/swag/testdata/annotations_from/service"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+func main() {}
+
+// GetPet handles GET /pets/{id}
+// @annotationsFrom service.PetService.GetPet
+// @Router /pets/{id} [get]
+func GetPet() {
+	_ = service.PetService{}
+}
+
+// ListPets handles GET /pets
+// @Summary List the pets
+// @annotationsFrom service.ListPets
+// @Tags pets
+// @Router /pets [get]
+func ListPets() {}
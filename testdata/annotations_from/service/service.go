@@ -0,0 +1,29 @@
+package service
+
+// Pet is a pet
+type Pet struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// PetService provides pets
+type PetService struct{}
+
+// GetPet returns the pet of the given id
+// @Summary Get a pet
+// @Description Returns the pet of the given id
+// @Tags pets
+// @Param id path int true "ID of the pet"
+// @Success 200 {object} Pet
+// @Failure 404 {string} string "not found"
+func (s PetService) GetPet(id int) (Pet, error) {
+	return Pet{}, nil
+}
+
+// ListPets returns all the pets
+// @Description Returns all the pets
+// @Param limit query int false "maximum number of pets"
+// @Success 200 {array} Pet
+func ListPets(limit int) []Pet {
+	return nil
+}
@@ -0,0 +1,12 @@
+package main
+
+type Basket struct {
+	Quantities []int    `json:"quantities" validate:"min=1,max=5,unique,dive,gte=0"`
+	Labels     []string `json:"labels" binding:"max=3,dive,min=2,max=8,oneof=red green blue"`
+	Codes      []string `json:"codes" validate:"len=2" maxLength:"4"`
+}
+
+// @Summary Get a basket
+// @Success 200 {object} Basket
+// @Router /basket [get]
+func GetBasket() {}
@@ -0,0 +1,18 @@
+package main
+
+// CreateUserRequest is bound by gin with ShouldBindJSON
+type CreateUserRequest struct {
+	Name     string   `json:"name" binding:"required,max=32"`
+	Email    string   `json:"email,omitempty" binding:"required,email"`
+	Nickname string   `json:"nickname" binding:"-" validate:"required"`
+	Age      int      `json:"age" binding:"omitempty,min=18" validate:"required,min=21"`
+	Country  string   `json:"country" binding:"len=2" validate:"required"`
+	Tags     []string `json:"tags" binding:"dive,required"`
+}
+
+// CreateUser godoc
+// @Summary Create a user
+// @Param user body CreateUserRequest true "the user to create"
+// @Success 201
+// @Router /users [post]
+func CreateUser() {}
@@ -0,0 +1,13 @@
+package main
+
+// Pet is a pet of the store
+type Pet struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetPet godoc
+// @Summary Get a pet
+// @Success 200 {object} Pet
+// @Router /pets/{id} [get]
+func GetPet() {}
@@ -0,0 +1,7 @@
+package main
+
+// ListPets godoc
+// @Summary List the pets
+// @Success 200 {array} Pet
+// @Router /pets [get]
+func ListPets( {}
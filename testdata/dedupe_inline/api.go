@@ -0,0 +1,30 @@
+package main
+
+// Order is an order of a customer
+type Order struct {
+	ID       int `json:"id"`
+	Customer struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"customer"`
+}
+
+// GetCustomer returns a customer
+// @Param id path int true "the id"
+// @Success 200 {object} object{id=int,name=string} "the customer"
+// @Router /customers/{id} [get]
+func GetCustomer() {}
+
+// GetSupplier returns a supplier
+// @Param id path int true "the id"
+// @Success 200 {object} object{id=int,name=string} "the supplier"
+// @Router /suppliers/{id} [get]
+func GetSupplier() {}
+
+// GetOrder returns an order
+// @Param id path int true "the id"
+// @Success 200 {object} Order "the order"
+// @Success 202 {object} object{order=object{id=int},total=int} "the pending order"
+// @Failure 409 {object} object{order=object{id=int},total=int} "the conflicting order"
+// @Router /orders/{id} [get]
+func GetOrder() {}
@@ -0,0 +1,13 @@
+package main
+
+// @Summary Create a pet
+// @Accept xml
+// @Produce plain
+// @Success 200 {string} string
+// @Router /pets [post]
+func CreatePet() {}
+
+// @Summary List the pets
+// @Success 200 {array} string
+// @Router /pets [get]
+func ListPets() {}
@@ -0,0 +1,23 @@
+package main
+
+// ErrorResponse is returned by every failing handler
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+// ValidationError lists the invalid fields of a request
+type ValidationError struct {
+	Fields []string `json:"fields"`
+}
+
+// GetPet returns a pet
+// @Header 500 {string} X-Request-Id "request id"
+// @Success 200 {string} string
+// @Router /pets [get]
+func GetPet() {}
+
+// CreatePet creates a pet
+// @Success 201 {string} string
+// @Failure 400 {object} ValidationError "invalid pet"
+// @Router /pets [post]
+func CreatePet() {}
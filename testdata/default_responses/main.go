@@ -0,0 +1,7 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+// @failure.default 400,500 {object} ErrorResponse "error"
+func main() {}
@@ -0,0 +1,16 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+// @description This is a sample server.
+// @description
+// @description It serves pets:
+// @description ```json
+// {
+//     "id": 1,
+//     "name": "doggie"
+// }
+// ```
+// @description See the docs for more.
+// @BasePath /v1
+func main() {}
@@ -0,0 +1,6 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+// @description.file description.md
+func main() {}
@@ -0,0 +1,6 @@
+package admin
+
+// ListUsers lists the users for admins
+// @Success 200 {string} string
+// @Router /users [get]
+func ListUsers() {}
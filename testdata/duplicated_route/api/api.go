@@ -0,0 +1,11 @@
+package api
+
+// GetUsers lists the users
+// @Success 200 {string} string
+// @Router /users [get]
+func GetUsers() {}
+
+// CreateUser creates a user
+// @Success 201 {string} string
+// @Router /users [post]
+func CreateUser() {}
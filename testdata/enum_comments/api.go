@@ -0,0 +1,23 @@
+package main
+
+// Priority is the urgency of a ticket
+type Priority int
+
+const (
+	// PriorityLow can wait for the next release
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh // needs a fix today
+)
+
+// Ticket is a support ticket
+type Ticket struct {
+	ID       int      `json:"id"`
+	Priority Priority `json:"priority"`
+}
+
+// ListTickets lists the tickets
+// @Param priority query int false "priority of the tickets" enumType(Priority)
+// @Success 200 {array} Ticket
+// @Router /tickets [get]
+func ListTickets() {}
@@ -0,0 +1,6 @@
+package internalapi
+
+// Reset resets the store
+// @Success 200 {string} string "ok"
+// @Router /admin/reset [post]
+func Reset() {}
@@ -0,0 +1,6 @@
+package api
+
+// GetPets lists the pets
+// @Success 200 {string} string "ok"
+// @Router /pets [get]
+func GetPets() {}
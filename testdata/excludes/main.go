@@ -0,0 +1,11 @@
+package main
+
+import (
+	_ "github.com/swaggo/swag/testdata/excludes/admin/internalapi"
+	_ "github.com/swaggo/swag/testdata/excludes/api"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+func main() {}
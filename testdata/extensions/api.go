@@ -0,0 +1,20 @@
+package main
+
+// Pet is a pet
+// @x-tablename "pets"
+// @x-group {"name": "animals"}
+type Pet struct {
+	ID   int    `json:"id" extensions:"x-nullable,x-abc=def"`
+	Name string `json:"name"`
+}
+
+// GetPet returns a pet
+// @Success 200 {object} Pet
+// @x-amazon-apigateway-integration {
+//   "uri": "${some_arn}",
+//   "httpMethod": "POST",
+//   "type": "aws_proxy"
+// }
+// @x-order 1
+// @Router /pets/{id} [get]
+func GetPet() {}
@@ -0,0 +1,14 @@
+package main
+
+// User of the api
+type User struct {
+	Name string `json:"name"`
+}
+
+// GetAddress godoc
+// @Summary Get the address of a user
+// @Param user body User true "the user"
+// @Success 200 {object} external://common.yaml#/definitions/Address "the address"
+// @Failure 404 {array} external://common.yaml#/definitions/Error
+// @Router /users/address [post]
+func GetAddress() {}
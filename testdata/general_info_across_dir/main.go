@@ -0,0 +1,6 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+// @description This is a sample server.
+func main() {}
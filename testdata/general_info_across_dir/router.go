@@ -0,0 +1,27 @@
+package main
+
+// @host petstore.swagger.io
+// @BasePath /v2
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+
+// @tag.name pets
+// @tag.description Everything about pets
+
+// Pet is a pet
+// @x-tablename "pets"
+type Pet struct {
+	ID int `json:"id"`
+}
+
+// petResponses documents the responses shared by the pet handlers
+// @Description A pet
+func petResponses() {}
+
+// GetPet returns a pet
+// @Tags pets
+// @Success 200 {object} Pet
+// @Router /pets/{id} [get]
+func GetPet() {}
@@ -0,0 +1,5 @@
+package main
+
+// @host petstore.swagger.io
+// @version 2.0
+func setupRouter() {}
@@ -0,0 +1,37 @@
+package main
+
+// RateLimitHeaders are sent along with every response
+type RateLimitHeaders struct {
+	// the number of requests allowed per hour
+	Limit int `json:"limit" header:"X-Rate-Limit-Limit"`
+	// the number of requests left for the hour
+	Remaining int `json:"remaining" header:"X-Rate-Limit-Remaining"`
+	// the time the limit is reset at
+	Reset string `json:"reset" header:"X-Rate-Limit-Reset" format:"date-time"`
+	// the request id, named after its json name
+	RequestID string `json:"X-Request-Id"`
+	// not sent
+	Internal string `json:"internal" header:"-"`
+}
+
+// Pagination can't be headers
+type Pagination struct {
+	Links []Link `json:"links"`
+}
+
+// Link is a link to another page
+type Link struct {
+	Href string `json:"href"`
+}
+
+// GetUser returns a user
+// @Success 200 "the user"
+// @Header 200 {object} RateLimitHeaders
+// @Router /users/{id} [get]
+func GetUser() {}
+
+// ListUsers returns the users
+// @Success 200 "the users"
+// @Header 200 {object} Pagination
+// @Router /users [get]
+func ListUsers() {}
@@ -0,0 +1,5 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+func main() {}
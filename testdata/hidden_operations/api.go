@@ -0,0 +1,27 @@
+package main
+
+type Pet struct {
+	Name string `json:"name"`
+}
+
+type Audit struct {
+	Actor string `json:"actor"`
+	Pet   Pet    `json:"pet"`
+}
+
+// @Summary Get a pet
+// @Success 200 {object} Pet
+// @Router /pets/{id} [get]
+func GetPet() {}
+
+// @Summary Audit a pet
+// @Hidden
+// @Success 200 {object} Audit
+// @Router /pets/{id} [post]
+func AuditPet() {}
+
+// @Summary List the audits
+// @Hidden
+// @Success 200 {array} Audit
+// @Router /audits [get]
+func ListAudits() {}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// User is a user of the store
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Router /users/{id} [get]
+func GetUser(c *gin.Context) {
+	if c.Param("id") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing id"})
+		return
+	}
+	user := User{ID: 1, Name: "gopher"}
+	c.JSON(http.StatusOK, user)
+}
+
+// ListUsers godoc
+// @Summary List the users
+// @Router /users [get]
+func ListUsers(ctx *gin.Context) {
+	var users []User
+	ctx.JSON(200, users)
+}
+
+// CreateUser godoc
+// @Summary Create a user
+// @Router /users [post]
+func CreateUser(c *gin.Context) {
+	c.JSON(http.StatusCreated, &User{})
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Success 204 {object} User "annotations take precedence"
+// @Router /users/{id} [delete]
+func DeleteUser(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"deleted": true})
+}
@@ -0,0 +1,57 @@
+package main
+
+// Money is used by several models
+type Money struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Address is used by Customer only
+type Address struct {
+	City string `json:"city"`
+}
+
+// Customer is used by several operations
+type Customer struct {
+	Name    string  `json:"name"`
+	Address Address `json:"address"`
+	Balance Money   `json:"balance"`
+}
+
+// Line is used by Receipt only
+type Line struct {
+	Label string `json:"label"`
+	Price Money  `json:"price"`
+}
+
+// Receipt is used by a single operation
+type Receipt struct {
+	Lines []Line `json:"lines"`
+}
+
+// Category refers to itself
+type Category struct {
+	Name     string     `json:"name"`
+	Children []Category `json:"children"`
+}
+
+// @Summary Get a customer
+// @Success 200 {object} Customer
+// @Router /customers/{id} [get]
+func GetCustomer() {}
+
+// @Summary Update a customer
+// @Param customer body Customer true "the customer"
+// @Success 204
+// @Router /customers/{id} [put]
+func UpdateCustomer() {}
+
+// @Summary Get a receipt
+// @Success 200 {object} Receipt
+// @Router /receipts/{id} [get]
+func GetReceipt() {}
+
+// @Summary Get the categories
+// @Success 200 {object} Category
+// @Router /categories [get]
+func GetCategories() {}
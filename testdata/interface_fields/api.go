@@ -0,0 +1,45 @@
+package main
+
+type Labeled interface {
+	Label() string
+}
+
+type Shape interface {
+	Labeled
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+
+func (c Circle) Label() string { return "circle" }
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+func (s *Square) Label() string { return "square" }
+
+// Line has an area but isn't a Shape since it lacks Label
+type Line struct {
+	Length float64 `json:"length"`
+}
+
+func (l Line) Area() float64 { return 0 }
+
+type Drawing struct {
+	Name   string      `json:"name"`
+	Shape  Shape       `json:"shape"`
+	Shapes []Shape     `json:"shapes"`
+	Meta   interface{} `json:"meta"`
+}
+
+// @Summary Get a drawing
+// @Success 200 {object} Drawing
+// @Router /drawing [get]
+func GetDrawing() {}
@@ -0,0 +1,15 @@
+package main
+
+// @Summary Get a user
+// @Summary[ja] ユーザー取得
+// @Description Returns the user of the id.
+// @Description[ja] IDのユーザーを返します。
+// @Description[ja] 存在しない場合は404です。
+// @Success 200 {string} string
+// @Router /users/{id} [get]
+func GetUser() {}
+
+// @Summary Delete a user
+// @Success 204
+// @Router /users/{id} [delete]
+func DeleteUser() {}
@@ -0,0 +1,16 @@
+package api
+
+import "net/http"
+
+// Order an order of another service
+type Order struct {
+	ID string `json:"id"`
+}
+
+// @Summary Get an order
+// @Produce json
+// @Success 200 {object} Order
+// @Router /orders/{id} [get]
+func GetOrder(w http.ResponseWriter, r *http.Request) {
+	//write your code
+}
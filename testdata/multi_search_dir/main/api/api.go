@@ -0,0 +1,17 @@
+package api
+
+import "net/http"
+
+// Order an order of a user
+type Order struct {
+	ID    int    `json:"id"`
+	Total string `json:"total"`
+}
+
+// @Summary List orders
+// @Produce json
+// @Success 200 {array} Order
+// @Router /orders [get]
+func GetOrders(w http.ResponseWriter, r *http.Request) {
+	//write your code
+}
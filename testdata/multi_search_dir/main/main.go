@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/swaggo/swag/testdata/multi_search_dir/main/api"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @description This is a sample server split across several directories.
+
+// @host petstore.swagger.io
+// @BasePath /v2
+func main() {
+	http.HandleFunc("/orders", api.GetOrders)
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,18 @@
+package user
+
+import "net/http"
+
+// User a user of the shop
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// @Summary Get a user
+// @Produce json
+// @Param id path int true "user id"
+// @Success 200 {object} User
+// @Router /users/{id} [get]
+func GetUser(w http.ResponseWriter, r *http.Request) {
+	//write your code
+}
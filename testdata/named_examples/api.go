@@ -0,0 +1,16 @@
+package main
+
+// User of the api
+type User struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+// CreateUser godoc
+// @Summary Create a user
+// @Produce json
+// @Param user body User true "the user to create" example(UserCreateReq)
+// @Param page query int false "page of the listing" example(UserPage)
+// @Success 200 {object} User "the created user" example(UserCreateReq)
+// @Router /users [post]
+func CreateUser() {}
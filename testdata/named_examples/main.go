@@ -0,0 +1,8 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+// @example.named UserCreateReq {"name": "gopher", "age": 10}
+// @example.named UserPage 2
+func main() {}
@@ -0,0 +1,35 @@
+package main
+
+// Event is the part shared by the events
+type Event struct {
+	Type string `json:"type"`
+}
+
+// Created is sent when a user is created
+type Created struct {
+	Event
+	Name string `json:"name"`
+}
+
+// Renamed is sent when a user changes its name
+type Renamed struct {
+	Event
+	OldName string `json:"oldName"`
+	NewName string `json:"newName"`
+}
+
+// Deleted is sent when a user is deleted
+type Deleted struct {
+	Event
+	Reason string `json:"reason"`
+}
+
+// GetEvent returns the last event of a user
+// @Success 200 {oneOf} Created,Renamed,Deleted "the event" discriminator(type)
+// @Router /users/{id}/events/last [get]
+func GetEvent() {}
+
+// ListEvents returns the events of a user
+// @Success 200 {anyOf} Created,Deleted "the events"
+// @Router /users/{id}/events [get]
+func ListEvents() {}
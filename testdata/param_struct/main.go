@@ -0,0 +1,16 @@
+package main
+
+import (
+	_ "github.com/swaggo/swag/testdata/param_struct/model"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+func main() {}
+
+// ListUsers lists the users matching the filter
+// @Param filter query model.UserFilter false "filters"
+// @Success 200 {string} string
+// @Router /users [get]
+func ListUsers() {}
@@ -0,0 +1,18 @@
+package model
+
+// UserFilter binds the query of ListUsers
+type UserFilter struct {
+	// name of the user
+	Name   string   `json:"name" form:"q" validate:"max=32"`
+	Role   string   `json:"role" binding:"required,oneof=admin member"`
+	Tags   []string `json:"tags"`
+	Age    int      `json:"age" validate:"gte=18,lte=130"`
+	Secret string   `json:"secret" form:"-"`
+	Page   Page     `json:"page"`
+}
+
+// Page is the requested page of results
+type Page struct {
+	Number int `json:"number" form:"num" minimum:"1"`
+	Size   int `json:"size" default:"20"`
+}
@@ -0,0 +1,26 @@
+package main
+
+import "time"
+
+// OptionalInt is an int which may be missing
+type OptionalInt *int
+
+// OptionalTime is a time which may be missing
+type OptionalTime = *time.Time
+
+// Settings are the settings of an account
+type Settings struct {
+	Limit     *int         `json:"limit,omitempty"`
+	Enabled   *bool        `json:"enabled,omitempty"`
+	Name      *string      `json:"name,omitempty"`
+	UpdatedAt *time.Time   `json:"updated_at,omitempty"`
+	Count     *int64       `json:"count"`
+	Retries   OptionalInt  `json:"retries,omitempty"`
+	ExpiresAt OptionalTime `json:"expires_at,omitempty"`
+	Version   int          `json:"version"`
+}
+
+// GetSettings returns the settings of the account
+// @Success 200 {object} Settings
+// @Router /settings [get]
+func GetSettings() {}
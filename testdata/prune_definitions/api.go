@@ -0,0 +1,44 @@
+package main
+
+// Response wraps the data of every response
+type Response struct {
+	Data interface{} `json:"data"`
+}
+
+// User of the api
+type User struct {
+	Name     string             `json:"name"`
+	Address  Address            `json:"address"`
+	Pets     []Pet              `json:"pets"`
+	Settings map[string]Setting `json:"settings"`
+}
+
+// Address of a user
+type Address struct {
+	City string `json:"city"`
+}
+
+// Pet of a user
+type Pet struct {
+	Name string `json:"name"`
+}
+
+// Setting of a user
+type Setting struct {
+	Value string `json:"value"`
+}
+
+// Orphan is referenced by no operation
+type Orphan struct {
+	Name string `json:"name"`
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Success 200 {object} Response{data=User}
+// @Router /users/{id} [get]
+func GetUser() {}
+
+// orphanResponse documents a response without being an operation
+// @Success 200 {object} Orphan
+func orphanResponse() {}
@@ -0,0 +1,18 @@
+package main
+
+import "time"
+
+// Account of a user
+type Account struct {
+	ID        int       `json:"id" swaggertype:",readonly"`
+	Login     string    `json:"login"`
+	CreatedAt time.Time `json:"created_at" readonly:"true"`
+	Password  string    `json:"password" writeonly:"true"`
+	Secret    []byte    `json:"secret" swaggertype:"string,writeonly" format:"base64"`
+}
+
+// @Summary Create an account
+// @Param account body Account true "the account"
+// @Success 201 {object} Account
+// @Router /accounts [post]
+func CreateAccount() {}
@@ -0,0 +1,10 @@
+package controller
+
+// User is a user
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserController handles the users
+type UserController struct{}
@@ -0,0 +1,8 @@
+package controller
+
+// Get returns a user
+// @Summary Get a user
+// @Param id path int true "user id"
+// @Success 200 {object} User
+// @Router /users/{id} [get]
+func (c *UserController) Get() {}
@@ -0,0 +1,7 @@
+package controller
+
+// List lists the users
+// @Summary List the users
+// @Success 200 {array} User
+// @Router /users [get]
+func (c UserController) List() {}
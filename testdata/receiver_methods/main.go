@@ -0,0 +1,10 @@
+package main
+
+import (
+	_ "github.com/swaggo/swag/testdata/receiver_methods/controller"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+func main() {}
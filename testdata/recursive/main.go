@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/swaggo/swag/testdata/recursive/model"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+func main() {}
+
+// GetTree returns a tree
+// @Success 200 {object} model.TreeNode
+// @Router /tree [get]
+func GetTree() {
+	_ = model.TreeNode{}
+}
+
+// GetAuthor returns an author with the books written
+// @Success 200 {object} model.Author
+// @Router /author [get]
+func GetAuthor() {}
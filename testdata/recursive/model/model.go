@@ -0,0 +1,21 @@
+package model
+
+// TreeNode is a node of a tree
+type TreeNode struct {
+	Value    string               `json:"value"`
+	Parent   *TreeNode            `json:"parent"`
+	Children []TreeNode           `json:"children"`
+	Index    map[string]*TreeNode `json:"index"`
+}
+
+// Author writes books
+type Author struct {
+	Name  string `json:"name"`
+	Books []Book `json:"books"`
+}
+
+// Book is written by an author
+type Book struct {
+	Title  string `json:"title"`
+	Author Author `json:"author"`
+}
@@ -0,0 +1,13 @@
+module example.com/app
+
+go 1.16
+
+require (
+	example.com/foo v0.0.0
+	example.com/shared v0.0.0
+)
+
+replace (
+	example.com/foo => ./internal/foo
+	example.com/shared v0.0.0 => ../shared // the shared models of the monorepo
+)
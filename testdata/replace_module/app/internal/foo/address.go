@@ -0,0 +1,5 @@
+package foo
+
+type Address struct {
+	City string `json:"city"`
+}
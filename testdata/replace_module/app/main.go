@@ -0,0 +1,20 @@
+package main
+
+import (
+	"example.com/foo"
+	"example.com/shared/model"
+)
+
+type Profile struct {
+	User    model.User  `json:"user"`
+	Address foo.Address `json:"address"`
+}
+
+// @Summary Get a profile
+// @Success 200 {object} Profile
+// @Router /profile [get]
+func GetProfile() {}
+
+// @title Swagger Example API
+// @version 1.0
+func main() {}
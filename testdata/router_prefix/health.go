@@ -0,0 +1,7 @@
+package main
+
+// Health godoc
+// @Summary Check the health of the server
+// @Success 200
+// @Router /health [get]
+func Health() {}
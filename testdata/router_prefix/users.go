@@ -0,0 +1,17 @@
+package main
+
+// the routes of this file are registered in a group
+// @RouterPrefix /v1
+
+// ListUsers godoc
+// @Summary List the users
+// @Success 200
+// @Router /users [get]
+func ListUsers() {}
+
+// GetUser godoc
+// @Summary Get a user
+// @Param id path int true "the id of the user"
+// @Success 200
+// @Router /v1/users/{id} [get]
+func GetUser() {}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/swaggo/swag/testdata/self_described/types"
+)
+
+// Order is an order
+type Order struct {
+	ID        int               `json:"id"`
+	CreatedAt types.Timestamp   `json:"created_at"`
+	Total     *types.Money      `json:"total"`
+	History   []types.Timestamp `json:"history"`
+}
+
+// GetOrder returns an order
+// @Param since query types.Timestamp false "orders created after"
+// @Success 200 {object} Order
+// @Router /orders [get]
+func GetOrder() {}
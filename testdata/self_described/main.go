@@ -0,0 +1,6 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+func main() {}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"strconv"
+	"time"
+)
+
+// Timestamp is marshaled as an RFC 3339 string
+type Timestamp struct {
+	time time.Time
+}
+
+// MarshalJSON writes the timestamp as an RFC 3339 string
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(t.time.Format(time.RFC3339))), nil
+}
+
+// SwaggerSchema documents the timestamp as a date-time string
+func (Timestamp) SwaggerSchema() string {
+	return "string,date-time"
+}
+
+// Money is marshaled as a decimal string
+type Money struct {
+	cents int64
+}
+
+// SwaggerSchema documents the amount as a string
+func (*Money) SwaggerSchema() string {
+	return "string"
+}
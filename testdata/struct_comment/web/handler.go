@@ -22,4 +22,6 @@ type APIError struct {
 	ErrorNo   int64
 	ErrorCtx  string    // Error `context` tick comment
 	CreatedAt time.Time // Error time
+	// Error code, ignored for the description tag
+	Code int `description:"Error code, see the list of codes"`
 }
@@ -0,0 +1,7 @@
+package main
+
+//swag:title Swagger Example API
+//swag:version 1.0
+//swag:description This is a sample server.
+//swag:basepath /v1
+func main() {}
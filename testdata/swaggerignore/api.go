@@ -0,0 +1,56 @@
+package main
+
+// Audit holds the internal bookkeeping of a record
+type Audit struct {
+	CreatedBy string `json:"createdBy"`
+	Revision  int    `json:"revision" binding:"required" swaggerignore:"true"`
+}
+
+// Base is embedded in the records
+type Base struct {
+	ID       int    `json:"id" binding:"required"`
+	Internal string `json:"internal" binding:"required" swaggerignore:"true"`
+	Audit
+}
+
+// Item is an element of an order
+type Item struct {
+	Name  string `json:"name"`
+	Cost  int    `json:"cost" validate:"required" swaggerignore:"TRUE"`
+	Audit `swaggerignore:"true"`
+}
+
+// Order is a record holding items
+type Order struct {
+	*Base
+	Items  []Item          `json:"items"`
+	ByName map[string]Item `json:"byName"`
+	Notes  []struct {
+		Text   string `json:"text"`
+		Author string `json:"author" binding:"required" swaggerignore:"true"`
+	} `json:"notes"`
+	Tags map[string]struct {
+		Label string `json:"label"`
+		Color string `json:"color" binding:"required" swaggerignore:"true"`
+	} `json:"tags"`
+	Secret string `json:"secret" binding:"required" swaggerignore:"true"`
+}
+
+// Response wraps the data of a response
+type Response struct {
+	Code int         `json:"code"`
+	Data interface{} `json:"data"`
+}
+
+// GetOrder godoc
+// @Summary Get an order
+// @Success 200 {object} Response{data=Order}
+// @Router /orders/{id} [get]
+func GetOrder() {}
+
+// ListOrders godoc
+// @Summary List the orders
+// @Param filter query Base false "the filter"
+// @Success 200 {array} Order
+// @Router /orders [get]
+func ListOrders() {}
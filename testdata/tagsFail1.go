@@ -0,0 +1,10 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+
+// @tag.docs.url https://example.com/docs
+// @tag.docs.description Find out more
+
+// @tag.name pet
+func main() {}
@@ -0,0 +1,14 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+
+// @tag.name users
+// @tag.name orders
+// @tag.description Orders of the users
+// @tag.name users
+// @tag.description Users of the store
+// @tag.docs.url https://example.com/users
+// @tag.name orders
+// @tag.description Ignored since orders already has one
+func main() {}
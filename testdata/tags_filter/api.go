@@ -0,0 +1,51 @@
+package main
+
+// Address of a user
+type Address struct {
+	City string `json:"city"`
+}
+
+// User of the api
+type User struct {
+	Name    string  `json:"name"`
+	Address Address `json:"address"`
+}
+
+// AuditLog of the back office
+type AuditLog struct {
+	Entries []string `json:"entries"`
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Tags public
+// @Success 200 {object} User
+// @Router /users/{id} [get]
+func GetUser() {}
+
+// ListUsers godoc
+// @Summary List the users, soon removed from the public portal
+// @Tags public,deprecated
+// @Success 200 {array} User
+// @Router /users [get]
+func ListUsers() {}
+
+// GetAuditLog godoc
+// @Summary Get the audit log
+// @Tags internal
+// @Success 200 {object} AuditLog
+// @Router /audit [get]
+func GetAuditLog() {}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Tags internal
+// @Success 204
+// @Router /users/{id} [delete]
+func DeleteUser() {}
+
+// Health godoc
+// @Summary Check the health of the api
+// @Success 200 {string} string
+// @Router /health [get]
+func Health() {}
@@ -0,0 +1,10 @@
+package main
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+// @tag.name public
+// @tag.description Operations of the public portal
+// @tag.name internal
+// @tag.description Operations of the back office
+func main() {}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// User is a registered user
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Account is the former name of User
+type Account = User
+
+// Email is an email address
+type Email = string
+
+// Payload holds raw json
+type Payload = json.RawMessage
+
+// Instant is a point in time
+type Instant = time.Time
+
+// Point is declared by an alias of a type literal
+type Point = struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Profile of a user
+type Profile struct {
+	Account Account  `json:"account"`
+	Emails  []Email  `json:"emails"`
+	Payload Payload  `json:"payload"`
+	Seen    Instant  `json:"seen"`
+	Backup  *Account `json:"backup"`
+	Home    Point    `json:"home"`
+}
+
+// @Summary Get the profile of a user
+// @Success 200 {object} Profile
+// @Router /profile [get]
+func GetProfile() {}
+
+// @Summary Get an account
+// @Success 200 {object} Account
+// @Router /account [get]
+func GetAccount() {}
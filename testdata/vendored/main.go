@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/acme/money"
+)
+
+type Order struct {
+	ID    int          `json:"id"`
+	Price money.Amount `json:"price"`
+}
+
+// @Summary Get an order
+// @Success 200 {object} Order
+// @Router /orders [get]
+func GetOrder() {}
+
+// @title Swagger Example API
+// @version 1.0
+func main() {}
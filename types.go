@@ -22,6 +22,9 @@ type TypeSpecDef struct {
 
 	//the TypeSpec of this type definition
 	TypeSpec *ast.TypeSpec
+
+	//constants declared with this type, in order of declaration
+	Enums []EnumValue
 }
 
 //Name name of the typeSpec
@@ -56,4 +59,13 @@ type PackageDefinitions struct {
 
 	//definitions in this package, map key is typeName
 	TypeDefinitions map[string]*TypeSpecDef
+
+	//values of constants evaluated in this package, map key is constant name
+	constValues map[string]interface{}
+
+	//SwaggerSchema methods declared in this package, map key is the receiver typeName
+	schemaMethods map[string]*ast.FuncDecl
+
+	//names of the methods declared in this package, map key is the receiver typeName
+	methods map[string]map[string]bool
 }